@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var annotateCmd = &cobra.Command{
+	Use:   "annotate [ evaluation ]",
+	Short: "Annotates an evaluation",
+}
+
+func init() {
+	rootCmd.AddCommand(annotateCmd)
+}
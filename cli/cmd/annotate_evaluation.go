@@ -0,0 +1,87 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os/user"
+
+	"github.com/keptn/keptn/cli/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+const evaluationAnnotationPath = "/v1/evaluation/annotate"
+
+type annotateEvaluationStruct struct {
+	Project *string `json:"project"`
+	Stage   *string `json:"stage"`
+	Service *string `json:"service"`
+	EventID string  `json:"eventId"`
+	Text    *string `json:"text"`
+	Author  *string `json:"author"`
+}
+
+var annotateEvaluationParams annotateEvaluationStruct
+
+var annotateEvaluationCmd = &cobra.Command{
+	Use:   "evaluation EVALUATION_EVENT_ID",
+	Short: "Adds a free-text annotation to an evaluation",
+	Long: `Adds a free-text annotation to an evaluation, which is identified by the ID of its evaluation.finished event.
+
+Annotations are shown when comparing evaluations with 'keptn compare evaluations --annotations'.
+`,
+	Example:      `keptn annotate evaluation 3b41d8f8-ae9b-4c4a-94bf-49a9e5e3f61e --project=sockshop --stage=hardening --service=carts --text="new database version"`,
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return annotateEvaluation(args[0], annotateEvaluationParams)
+	},
+}
+
+func annotateEvaluation(eventID string, params annotateEvaluationStruct) error {
+	params.EventID = eventID
+	if *params.Author == "" {
+		currentUser, err := user.Current()
+		if err != nil {
+			return fmt.Errorf("Could not determine the author, please set --author: %w", err)
+		}
+		*params.Author = currentUser.Username
+	}
+
+	if err := doLighthouseRequest(http.MethodPost, evaluationAnnotationPath, params, nil); err != nil {
+		return fmt.Errorf("could not annotate evaluation: %w", err)
+	}
+	logging.PrintLog("Annotation added to evaluation "+eventID, logging.InfoLevel)
+	return nil
+}
+
+func init() {
+	annotateCmd.AddCommand(annotateEvaluationCmd)
+
+	annotateEvaluationParams.Project = annotateEvaluationCmd.Flags().StringP("project", "", "", "The project containing the evaluated service")
+	annotateEvaluationCmd.MarkFlagRequired("project")
+
+	annotateEvaluationParams.Stage = annotateEvaluationCmd.Flags().StringP("stage", "", "", "The stage containing the evaluated service")
+	annotateEvaluationCmd.MarkFlagRequired("stage")
+
+	annotateEvaluationParams.Service = annotateEvaluationCmd.Flags().StringP("service", "", "", "The evaluated service")
+	annotateEvaluationCmd.MarkFlagRequired("service")
+
+	annotateEvaluationParams.Text = annotateEvaluationCmd.Flags().StringP("text", "", "", "The text of the annotation")
+	annotateEvaluationCmd.MarkFlagRequired("text")
+
+	annotateEvaluationParams.Author = annotateEvaluationCmd.Flags().StringP("author", "", "", "The author of the annotation, defaults to the current user")
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [ evaluations ]",
+	Short: "Compares the results of two Keptn contexts",
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+}
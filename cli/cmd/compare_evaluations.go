@@ -0,0 +1,192 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+const evaluationComparisonPath = "/v1/evaluation/compare"
+
+type compareEvaluationsStruct struct {
+	Output      *string
+	Annotations *bool
+}
+
+type evaluationSummary struct {
+	KeptnContext string                  `json:"keptnContext" yaml:"keptnContext"`
+	EventID      string                  `json:"eventId" yaml:"eventId"`
+	GitCommitID  string                  `json:"gitCommitId" yaml:"gitCommitId"`
+	Project      string                  `json:"project" yaml:"project"`
+	Stage        string                  `json:"stage" yaml:"stage"`
+	Service      string                  `json:"service" yaml:"service"`
+	Result       string                  `json:"result" yaml:"result"`
+	Score        float64                 `json:"score" yaml:"score"`
+	TimeStart    string                  `json:"timeStart" yaml:"timeStart"`
+	TimeEnd      string                  `json:"timeEnd" yaml:"timeEnd"`
+	Annotations  []*evaluationAnnotation `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Invalidation *evaluationValidity     `json:"invalidation,omitempty" yaml:"invalidation,omitempty"`
+}
+
+type evaluationAnnotation struct {
+	Text   string `json:"text" yaml:"text"`
+	Author string `json:"author" yaml:"author"`
+	Time   string `json:"time,omitempty" yaml:"time,omitempty"`
+}
+
+type evaluationValidity struct {
+	Reason string `json:"reason" yaml:"reason"`
+	Author string `json:"author,omitempty" yaml:"author,omitempty"`
+}
+
+type indicatorDiff struct {
+	Metric      string   `json:"metric" yaml:"metric"`
+	DisplayName string   `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	FromValue   *float64 `json:"fromValue,omitempty" yaml:"fromValue,omitempty"`
+	ToValue     *float64 `json:"toValue,omitempty" yaml:"toValue,omitempty"`
+	ValueChange *float64 `json:"valueChange,omitempty" yaml:"valueChange,omitempty"`
+	FromStatus  string   `json:"fromStatus,omitempty" yaml:"fromStatus,omitempty"`
+	ToStatus    string   `json:"toStatus,omitempty" yaml:"toStatus,omitempty"`
+	FromScore   float64  `json:"fromScore" yaml:"fromScore"`
+	ToScore     float64  `json:"toScore" yaml:"toScore"`
+	Regression  bool     `json:"regression" yaml:"regression"`
+}
+
+type sloChange struct {
+	SLI    string      `json:"sli" yaml:"sli"`
+	Change string      `json:"change" yaml:"change"`
+	From   interface{} `json:"from,omitempty" yaml:"from,omitempty"`
+	To     interface{} `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+type evaluationComparison struct {
+	From           *evaluationSummary `json:"from" yaml:"from"`
+	To             *evaluationSummary `json:"to" yaml:"to"`
+	IndicatorDiffs []*indicatorDiff   `json:"indicatorDiffs" yaml:"indicatorDiffs"`
+	SLOChanges     []*sloChange       `json:"sloChanges" yaml:"sloChanges"`
+	Regressions    []string           `json:"regressions" yaml:"regressions"`
+}
+
+var compareEvaluationsParams compareEvaluationsStruct
+
+var compareEvaluationsCmd = &cobra.Command{
+	Use:   "evaluations FROM_KEPTN_CONTEXT TO_KEPTN_CONTEXT",
+	Short: "Compares the evaluation results of two Keptn contexts",
+	Long: `Compares the evaluation results of two Keptn contexts.
+
+For each SLI, the difference of value, status and score is shown, and SLIs that got worse are highlighted as regressions.
+Additionally, the changes of the SLO between the Git commits the evaluations have been executed with are listed.`,
+	Example:      `keptn compare evaluations 1234-5678-90ab-cdef 0987-6543-21fe-dcba --output=json`,
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return compareEvaluations(args[0], args[1], compareEvaluationsParams, os.Stdout)
+	},
+}
+
+func compareEvaluations(from, to string, params compareEvaluationsStruct, writer io.Writer) error {
+	query := url.Values{}
+	query.Add("from", from)
+	query.Add("to", to)
+	if *params.Annotations {
+		query.Add("annotations", "true")
+	}
+
+	comparison := &evaluationComparison{}
+	if err := doLighthouseRequest(http.MethodGet, evaluationComparisonPath+"?"+query.Encode(), nil, comparison); err != nil {
+		return fmt.Errorf("could not compare evaluations: %w", err)
+	}
+
+	if *params.Output == "json" || *params.Output == "yaml" {
+		PrintEvents(writer, *params.Output, comparison)
+		return nil
+	}
+	printEvaluationComparison(writer, comparison)
+	return nil
+}
+
+func printEvaluationComparison(writer io.Writer, comparison *evaluationComparison) {
+	fmt.Fprintf(writer, "Comparing evaluation of %s (commit %s) with %s (commit %s)\n\n",
+		comparison.From.KeptnContext, comparison.From.GitCommitID, comparison.To.KeptnContext, comparison.To.GitCommitID)
+	fmt.Fprintf(writer, "Result: %s (%.2f) -> %s (%.2f)\n\n", comparison.From.Result, comparison.From.Score, comparison.To.Result, comparison.To.Score)
+
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLI\tVALUE\tCHANGE\tSTATUS\tSCORE\t")
+	for _, diff := range comparison.IndicatorDiffs {
+		marker := ""
+		if diff.Regression {
+			marker = "REGRESSION"
+		}
+		fmt.Fprintf(w, "%s\t%s -> %s\t%s\t%s -> %s\t%v -> %v\t%s\n",
+			diff.Metric, formatOptionalValue(diff.FromValue), formatOptionalValue(diff.ToValue), formatOptionalValue(diff.ValueChange),
+			formatOptionalStatus(diff.FromStatus), formatOptionalStatus(diff.ToStatus), diff.FromScore, diff.ToScore, marker)
+	}
+	w.Flush()
+
+	if len(comparison.SLOChanges) > 0 {
+		fmt.Fprintln(writer, "\nSLO changes:")
+		for _, change := range comparison.SLOChanges {
+			fmt.Fprintf(writer, "  %s: %s\n", change.SLI, change.Change)
+		}
+	}
+
+	for _, summary := range []*evaluationSummary{comparison.From, comparison.To} {
+		if summary.Invalidation != nil {
+			fmt.Fprintf(writer, "\nEvaluation of %s has been invalidated by %s: %s\n", summary.KeptnContext, summary.Invalidation.Author, summary.Invalidation.Reason)
+		}
+		if len(summary.Annotations) > 0 {
+			fmt.Fprintf(writer, "\nAnnotations of %s:\n", summary.KeptnContext)
+			for _, annotation := range summary.Annotations {
+				fmt.Fprintf(writer, "  %s (%s, %s)\n", annotation.Text, annotation.Author, annotation.Time)
+			}
+		}
+	}
+
+	if len(comparison.Regressions) > 0 {
+		fmt.Fprintf(writer, "\nRegressions: %s\n", strings.Join(comparison.Regressions, ", "))
+	} else {
+		fmt.Fprintln(writer, "\nNo regressions found")
+	}
+}
+
+func formatOptionalValue(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%v", *value)
+}
+
+func formatOptionalStatus(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return status
+}
+
+func init() {
+	compareCmd.AddCommand(compareEvaluationsCmd)
+	compareEvaluationsParams.Output = compareEvaluationsCmd.Flags().StringP("output", "o", "",
+		"Output format for the comparison [json|yaml], the default prints a table")
+	compareEvaluationsParams.Annotations = compareEvaluationsCmd.Flags().BoolP("annotations", "", false,
+		"Include the annotations and invalidations of the evaluations")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const evaluationComparisonResponse = `{
+  "from": {"keptnContext": "build-41", "gitCommitId": "commit-41", "result": "pass", "score": 100},
+  "to": {"keptnContext": "build-42", "gitCommitId": "commit-42", "result": "fail", "score": 50},
+  "indicatorDiffs": [
+    {"metric": "response_time_p95", "fromValue": 300, "toValue": 450, "valueChange": 150, "fromStatus": "pass", "toStatus": "fail", "fromScore": 1, "toScore": 0, "regression": true},
+    {"metric": "error_rate", "toValue": 0, "toStatus": "pass", "fromScore": 0, "toScore": 1, "regression": false}
+  ],
+  "sloChanges": [{"sli": "error_rate", "change": "added"}],
+  "regressions": ["response_time_p95", "total_score"]
+}`
+
+func newEvaluationComparisonServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		if r.URL.Path != lighthouseAPIPath+evaluationComparisonPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("to") == "unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "evaluation not found for context unknown"}`))
+			return
+		}
+		assert.Equal(t, "build-41", r.URL.Query().Get("from"))
+		assert.Equal(t, "build-42", r.URL.Query().Get("to"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(evaluationComparisonResponse))
+	}))
+}
+
+func Test_compareEvaluations(t *testing.T) {
+	ts := newEvaluationComparisonServer(t)
+	defer ts.Close()
+	os.Setenv("MOCK_SERVER", ts.URL)
+	mocking = true
+	defer func() { mocking = false }()
+
+	buf := &bytes.Buffer{}
+	output := ""
+	annotations := false
+	err := compareEvaluations("build-41", "build-42", compareEvaluationsStruct{Output: &output, Annotations: &annotations}, buf)
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), "Result: pass (100.00) -> fail (50.00)")
+	assert.Contains(t, buf.String(), "REGRESSION")
+	assert.Contains(t, buf.String(), "error_rate: added")
+	assert.Contains(t, buf.String(), "Regressions: response_time_p95, total_score")
+
+	buf = &bytes.Buffer{}
+	output = "json"
+	err = compareEvaluations("build-41", "build-42", compareEvaluationsStruct{Output: &output, Annotations: &annotations}, buf)
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), `"regressions": [`)
+
+	err = compareEvaluations("build-41", "unknown", compareEvaluationsStruct{Output: &output, Annotations: &annotations}, buf)
+	assert.EqualError(t, err, "could not compare evaluations: evaluation not found for context unknown")
+}
+
+// TestCompareEvaluationsMissingArgument
+func TestCompareEvaluationsMissingArgument(t *testing.T) {
+	testInvalidInputHelper("compare evaluations build-41", "accepts 2 arg(s), received 1", t)
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var invalidateCmd = &cobra.Command{
+	Use:   "invalidate [ evaluation ]",
+	Short: "Invalidates evaluations",
+}
+
+func init() {
+	rootCmd.AddCommand(invalidateCmd)
+}
@@ -0,0 +1,119 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os/user"
+	"strings"
+
+	"github.com/keptn/keptn/cli/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+const (
+	evaluationInvalidationPath = "/v1/evaluation/invalidate"
+	evaluationRevalidationPath = "/v1/evaluation/revalidate"
+)
+
+type evaluationValidityStruct struct {
+	Project  *string  `json:"project"`
+	Stage    *string  `json:"stage"`
+	Service  *string  `json:"service"`
+	EventIDs []string `json:"eventIds,omitempty"`
+	From     *string  `json:"from,omitempty"`
+	To       *string  `json:"to,omitempty"`
+	Reason   *string  `json:"reason"`
+	Author   *string  `json:"author"`
+}
+
+type evaluationValidityResponse struct {
+	Evaluations []string `json:"evaluations"`
+}
+
+var invalidateEvaluationParams *evaluationValidityStruct
+
+var invalidateEvaluationCmd = &cobra.Command{
+	Use:   "evaluation [EVALUATION_EVENT_ID...]",
+	Short: "Invalidates evaluations of a service",
+	Long: `Invalidates evaluations of a service, either selected by the IDs of their evaluation.finished events or by a time range (--from, --to).
+
+Invalidated evaluations are not used as comparison baselines for upcoming evaluations anymore. A reason has to be provided for the invalidation.
+The invalidation can be reverted using the command 'keptn revalidate evaluation'.
+`,
+	Example: `keptn invalidate evaluation 3b41d8f8-ae9b-4c4a-94bf-49a9e5e3f61e --project=sockshop --stage=hardening --service=carts --reason="load test was misconfigured"
+keptn invalidate evaluation --project=sockshop --stage=hardening --service=carts --from=2022-04-20T12:00:00 --to=2022-04-20T14:00:00 --reason="outage of the database"`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setEvaluationValidity(evaluationInvalidationPath, invalidateEvaluationParams, args)
+	},
+}
+
+func setEvaluationValidity(path string, params *evaluationValidityStruct, eventIDs []string) error {
+	if len(eventIDs) == 0 && *params.From == "" {
+		return errors.New("Either the IDs of the evaluations or --from have to be provided")
+	}
+	if len(eventIDs) > 0 && (*params.From != "" || *params.To != "") {
+		return errors.New("The IDs of the evaluations can not be used together with --from and --to")
+	}
+	params.EventIDs = eventIDs
+	if *params.Author == "" {
+		currentUser, err := user.Current()
+		if err != nil {
+			return fmt.Errorf("Could not determine the author, please set --author: %w", err)
+		}
+		*params.Author = currentUser.Username
+	}
+
+	response := &evaluationValidityResponse{}
+	if err := doLighthouseRequest(http.MethodPost, path, params, response); err != nil {
+		return fmt.Errorf("could not update evaluations: %w", err)
+	}
+	if len(response.Evaluations) == 0 {
+		logging.PrintLog("No matching evaluations found", logging.InfoLevel)
+		return nil
+	}
+	logging.PrintLog(fmt.Sprintf("Updated evaluations: %s", strings.Join(response.Evaluations, ", ")), logging.InfoLevel)
+	return nil
+}
+
+// addEvaluationValidityFlags adds the flags for selecting evaluations to the invalidate and revalidate commands
+func addEvaluationValidityFlags(cmd *cobra.Command) *evaluationValidityStruct {
+	params := &evaluationValidityStruct{}
+	params.Project = cmd.Flags().StringP("project", "", "", "The project containing the evaluated service")
+	cmd.MarkFlagRequired("project")
+
+	params.Stage = cmd.Flags().StringP("stage", "", "", "The stage containing the evaluated service")
+	cmd.MarkFlagRequired("stage")
+
+	params.Service = cmd.Flags().StringP("service", "", "", "The evaluated service")
+	cmd.MarkFlagRequired("service")
+
+	params.From = cmd.Flags().StringP("from", "", "", "The start of the time range of the evaluations in UTC")
+	params.To = cmd.Flags().StringP("to", "", "", "The end of the time range of the evaluations in UTC, defaults to the current time")
+
+	params.Reason = cmd.Flags().StringP("reason", "", "", "The reason for the change")
+	cmd.MarkFlagRequired("reason")
+
+	params.Author = cmd.Flags().StringP("author", "", "", "The author of the change, defaults to the current user")
+	return params
+}
+
+func init() {
+	invalidateCmd.AddCommand(invalidateEvaluationCmd)
+	invalidateEvaluationParams = addEvaluationValidityFlags(invalidateEvaluationCmd)
+}
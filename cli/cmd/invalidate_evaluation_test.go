@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/keptn/keptn/cli/pkg/credentialmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvaluationValidityServer(t *testing.T, received map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			// e.g. the version check of the CLI
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := map[string]interface{}{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		received[r.URL.Path] = body
+
+		switch r.URL.Path {
+		case lighthouseAPIPath + evaluationInvalidationPath, lighthouseAPIPath + evaluationRevalidationPath:
+			_, _ = w.Write([]byte(`{"evaluations": ["evaluation-1"]}`))
+		case lighthouseAPIPath + evaluationAnnotationPath:
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestInvalidateEvaluation(t *testing.T) {
+	credentialmanager.MockAuthCreds = true
+	received := map[string]map[string]interface{}{}
+	ts := newEvaluationValidityServer(t, received)
+	defer ts.Close()
+	os.Setenv("MOCK_SERVER", ts.URL)
+
+	_, err := executeActionCommandC(`invalidate evaluation evaluation-1 --project=sockshop --stage=hardening --service=carts --reason="outage" --author=jane --mock`)
+	require.Nil(t, err)
+	body := received[lighthouseAPIPath+evaluationInvalidationPath]
+	assert.Equal(t, []interface{}{"evaluation-1"}, body["eventIds"])
+	assert.Equal(t, "outage", body["reason"])
+	assert.Equal(t, "jane", body["author"])
+	assert.Equal(t, "carts", body["service"])
+
+	_, err = executeActionCommandC(`revalidate evaluation --project=sockshop --stage=hardening --service=carts --from=2022-04-20T12:00:00Z --reason="false alarm" --author=jane --mock`)
+	require.Nil(t, err)
+	body = received[lighthouseAPIPath+evaluationRevalidationPath]
+	assert.Nil(t, body["eventIds"])
+	assert.Equal(t, "2022-04-20T12:00:00Z", body["from"])
+}
+
+func TestInvalidateEvaluationInvalidSelection(t *testing.T) {
+	testInvalidInputHelper(`invalidate evaluation --project=sockshop --stage=hardening --service=carts --reason=outage --mock`,
+		"Either the IDs of the evaluations or --from have to be provided", t)
+	testInvalidInputHelper(`invalidate evaluation evaluation-1 --project=sockshop --stage=hardening --service=carts --reason=outage --from=2022-04-20T12:00:00Z --mock`,
+		"The IDs of the evaluations can not be used together with --from and --to", t)
+}
+
+func TestAnnotateEvaluation(t *testing.T) {
+	credentialmanager.MockAuthCreds = true
+	received := map[string]map[string]interface{}{}
+	ts := newEvaluationValidityServer(t, received)
+	defer ts.Close()
+	os.Setenv("MOCK_SERVER", ts.URL)
+
+	_, err := executeActionCommandC(`annotate evaluation evaluation-1 --project=sockshop --stage=hardening --service=carts --text="new database version" --author=jane --mock`)
+	require.Nil(t, err)
+	body := received[lighthouseAPIPath+evaluationAnnotationPath]
+	assert.Equal(t, "evaluation-1", body["eventId"])
+	assert.Equal(t, "new database version", body["text"])
+}
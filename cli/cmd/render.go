@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   `render [webhook]`,
+	Short: `Renders a configuration file of Keptn locally`,
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/keptn/keptn/cli/internal/webhook"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type renderWebhookCmdParams struct {
+	WebhookConfig  *string
+	Event          *string
+	SubscriptionID *string
+	Secrets        []string
+}
+
+var renderWebhookParams *renderWebhookCmdParams
+
+var renderWebhookCmd = &cobra.Command{
+	Use:   `webhook --webhook-config=webhook.yaml --event=event.json`,
+	Short: "Renders the requests of a webhook config against a sample event",
+	Long: `Renders the requests of a webhook config against a sample event, using the same template functions as the webhook-service.
+
+This command does not execute any request, and does not connect to Keptn. Secrets referenced via envFrom are replaced by a placeholder,
+unless their value is provided via --secret. The URL of asynchronous webhooks is replaced by ` + webhook.CallbackURLPlaceholder + `.
+`,
+	Example: `keptn render webhook --webhook-config=webhook/webhook.yaml --event=deployment.triggered.json
+
+keptn render webhook --webhook-config=webhook/webhook.yaml --event=deployment.triggered.json --subscription-id=my-subscription-id --secret=token=my-token`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		webhookConfig, err := os.ReadFile(*renderWebhookParams.WebhookConfig)
+		if err != nil {
+			return fmt.Errorf("could not read webhook config: %w", err)
+		}
+		event, err := os.ReadFile(*renderWebhookParams.Event)
+		if err != nil {
+			return fmt.Errorf("could not read event: %w", err)
+		}
+		secrets, err := parseRenderSecrets(renderWebhookParams.Secrets)
+		if err != nil {
+			return err
+		}
+
+		webhooks, err := webhook.Render(webhookConfig, event, secrets, *renderWebhookParams.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		output, err := yaml.Marshal(webhooks)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output))
+		return nil
+	},
+}
+
+func parseRenderSecrets(secrets []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, secret := range secrets {
+		keyValue := strings.SplitN(secret, "=", 2)
+		if len(keyValue) != 2 || keyValue[0] == "" {
+			return nil, errors.New("secrets must be provided in the format name=value")
+		}
+		result[keyValue[0]] = keyValue[1]
+	}
+	return result, nil
+}
+
+func init() {
+	renderCmd.AddCommand(renderWebhookCmd)
+	renderWebhookParams = &renderWebhookCmdParams{}
+	renderWebhookParams.WebhookConfig = renderWebhookCmd.Flags().StringP("webhook-config", "", "", "The webhook config file, i.e. webhook/webhook.yaml")
+	renderWebhookCmd.MarkFlagRequired("webhook-config")
+	renderWebhookParams.Event = renderWebhookCmd.Flags().StringP("event", "e", "", "The JSON file containing the sample event")
+	renderWebhookCmd.MarkFlagRequired("event")
+	renderWebhookParams.SubscriptionID = renderWebhookCmd.Flags().StringP("subscription-id", "", "", "Only render the webhook of this subscription")
+	renderWebhookCmd.Flags().StringArrayVar(&renderWebhookParams.Secrets, "secret", renderWebhookParams.Secrets, "The value of a secret referenced via envFrom (i.e. my-token=some-value)")
+}
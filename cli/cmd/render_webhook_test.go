@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const renderWebhookConfig = `apiVersion: webhookconfig.keptn.sh/v1alpha1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      envFrom:
+        - name: token
+          secretRef:
+            name: my-secret
+            key: token
+      requests:
+        - "curl --header 'x-token: {{.env.token}}' http://my-webhook/{{.data.project}}"`
+
+const renderWebhookEvent = `{"type": "sh.keptn.event.webhook.triggered", "data": {"project": "my-project", "stage": "dev", "service": "carts"}}`
+
+func TestRenderWebhook(t *testing.T) {
+	dir := t.TempDir()
+	webhookFile := filepath.Join(dir, "webhook.yaml")
+	eventFile := filepath.Join(dir, "event.json")
+	require.Nil(t, os.WriteFile(webhookFile, []byte(renderWebhookConfig), 0644))
+	require.Nil(t, os.WriteFile(eventFile, []byte(renderWebhookEvent), 0644))
+
+	r := newRedirector()
+	r.redirectStdOut()
+	_, err := executeActionCommandC("render webhook --webhook-config=" + webhookFile + " --event=" + eventFile + " --secret=token=my-token")
+	out := r.revertStdOut()
+
+	require.Nil(t, err)
+	require.Equal(t, `- subscriptionID: my-subscription-id
+  type: sh.keptn.event.webhook.triggered
+  requests:
+    - 'curl --header ''x-token: my-token'' http://my-webhook/my-project'
+`, out)
+}
+
+func TestRenderWebhookInvalidSecret(t *testing.T) {
+	dir := t.TempDir()
+	webhookFile := filepath.Join(dir, "webhook.yaml")
+	eventFile := filepath.Join(dir, "event.json")
+	require.Nil(t, os.WriteFile(webhookFile, []byte(renderWebhookConfig), 0644))
+	require.Nil(t, os.WriteFile(eventFile, []byte(renderWebhookEvent), 0644))
+
+	testInvalidInputHelper("render webhook --webhook-config="+webhookFile+" --event="+eventFile+" --secret=my-token", "secrets must be provided in the format name=value", t)
+}
+
+func TestRenderWebhookUnknownParameter(t *testing.T) {
+	testInvalidInputHelper("render webhook --project=sockshop", "unknown flag: --project", t)
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var revalidateCmd = &cobra.Command{
+	Use:   "revalidate [ evaluation ]",
+	Short: "Reverts the invalidation of evaluations",
+}
+
+func init() {
+	rootCmd.AddCommand(revalidateCmd)
+}
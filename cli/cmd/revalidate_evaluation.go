@@ -0,0 +1,38 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+var revalidateEvaluationParams *evaluationValidityStruct
+
+var revalidateEvaluationCmd = &cobra.Command{
+	Use:   "evaluation [EVALUATION_EVENT_ID...]",
+	Short: "Reverts the invalidation of evaluations of a service",
+	Long: `Reverts the invalidation of evaluations of a service, either selected by the IDs of their evaluation.finished events or by a time range (--from, --to).
+
+Re-validated evaluations are used as comparison baselines for upcoming evaluations again.
+`,
+	Example:      `keptn revalidate evaluation 3b41d8f8-ae9b-4c4a-94bf-49a9e5e3f61e --project=sockshop --stage=hardening --service=carts --reason="invalidated by mistake"`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setEvaluationValidity(evaluationRevalidationPath, revalidateEvaluationParams, args)
+	},
+}
+
+func init() {
+	revalidateCmd.AddCommand(revalidateEvaluationCmd)
+	revalidateEvaluationParams = addEvaluationValidityFlags(revalidateEvaluationCmd)
+}
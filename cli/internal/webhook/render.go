@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// responseReferencePattern matches references to the responses of named requests, e.g. {{.responses.startJob.id}}
+var responseReferencePattern = regexp.MustCompile(`\.responses\.([A-Za-z0-9_-]+)((?:\.[A-Za-z0-9_-]+)*)`)
+
+// CallbackURLPlaceholder is used for the {{.callback.url}} of asynchronous webhooks, since the callback URL is only known during the execution
+const CallbackURLPlaceholder = "<callback-url>"
+
+// Config contains the parts of a webhook config that are relevant for rendering its requests
+type Config struct {
+	ApiVersion string `yaml:"apiVersion"`
+	Spec       struct {
+		Webhooks []Webhook `yaml:"webhooks"`
+	} `yaml:"spec"`
+}
+
+type Webhook struct {
+	Type           string      `yaml:"type"`
+	SubscriptionID string      `yaml:"subscriptionID"`
+	EnvFrom        []EnvFrom   `yaml:"envFrom"`
+	Requests       []yaml.Node `yaml:"requests"`
+	Async          struct {
+		Poll *struct {
+			Request yaml.Node `yaml:"request"`
+		} `yaml:"poll"`
+	} `yaml:"async"`
+}
+
+type EnvFrom struct {
+	Name      string `yaml:"name"`
+	SecretRef struct {
+		Name string `yaml:"name"`
+		Key  string `yaml:"key"`
+	} `yaml:"secretRef"`
+}
+
+// Request is a v1beta1 webhook request
+type Request struct {
+	Name    string   `yaml:"name,omitempty"`
+	URL     string   `yaml:"url"`
+	Method  string   `yaml:"method"`
+	Headers []Header `yaml:"headers,omitempty"`
+	Payload string   `yaml:"payload,omitempty"`
+	Options string   `yaml:"options,omitempty"`
+	Proxy   string   `yaml:"proxy,omitempty"`
+}
+
+type Header struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+// namedRequest contains the parts of a v1beta1 request that make its response available to subsequent requests
+type namedRequest struct {
+	Name     string `yaml:"name"`
+	Response struct {
+		Outputs []struct {
+			Name string `yaml:"name"`
+		} `yaml:"outputs"`
+	} `yaml:"response"`
+}
+
+// RenderedWebhook contains the requests of a webhook after the placeholders have been replaced.
+// Each request is either a curl command (v1alpha1) or a Request (v1beta1). Poll is the rendered poll request of an asynchronous webhook
+type RenderedWebhook struct {
+	SubscriptionID string        `yaml:"subscriptionID"`
+	Type           string        `yaml:"type"`
+	Requests       []interface{} `yaml:"requests"`
+	Poll           *Request      `yaml:"poll,omitempty"`
+}
+
+// Render replaces the placeholders of the webhook requests using the event, like the webhook-service does when it receives the event.
+// Secrets referenced via envFrom are taken from the given map, or replaced by a placeholder if they are not provided.
+// If subscriptionID is set, only the webhook of this subscription is rendered
+func Render(webhookConfig []byte, event []byte, secrets map[string]string, subscriptionID string) ([]RenderedWebhook, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(webhookConfig, config); err != nil {
+		return nil, fmt.Errorf("could not parse webhook config: %w", err)
+	}
+	eventData := map[string]interface{}{}
+	if err := json.Unmarshal(event, &eventData); err != nil {
+		return nil, fmt.Errorf("could not parse event: %w", err)
+	}
+	if _, ok := eventData["data"]; !ok {
+		return nil, errors.New("event does not contain a 'data' property")
+	}
+
+	result := []RenderedWebhook{}
+	for _, webhook := range config.Spec.Webhooks {
+		if subscriptionID != "" && webhook.SubscriptionID != subscriptionID {
+			continue
+		}
+		rendered, err := renderWebhook(webhook, eventData, secrets)
+		if err != nil {
+			return nil, fmt.Errorf("could not render webhook for subscription '%s': %w", webhook.SubscriptionID, err)
+		}
+		result = append(result, *rendered)
+	}
+	if subscriptionID != "" && len(result) == 0 {
+		return nil, fmt.Errorf("no webhook found for subscription '%s'", subscriptionID)
+	}
+	return result, nil
+}
+
+func renderWebhook(webhook Webhook, eventData map[string]interface{}, secrets map[string]string) (*RenderedWebhook, error) {
+	env := map[string]interface{}{}
+	for _, envFrom := range webhook.EnvFrom {
+		if value, ok := secrets[envFrom.Name]; ok {
+			env[envFrom.Name] = value
+		} else {
+			env[envFrom.Name] = fmt.Sprintf("<secret %s/%s>", envFrom.SecretRef.Name, envFrom.SecretRef.Key)
+		}
+	}
+	data := map[string]interface{}{}
+	for key, value := range eventData {
+		data[key] = value
+	}
+	data["env"] = env
+	data["callback"] = map[string]interface{}{"url": CallbackURLPlaceholder}
+
+	responses := responsePlaceholders(webhook)
+	namedResponses := map[string]interface{}{}
+	rendered := &RenderedWebhook{SubscriptionID: webhook.SubscriptionID, Type: webhook.Type, Requests: []interface{}{}}
+	for i, node := range webhook.Requests {
+		request, err := renderRequest(node, data)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", i+1, err)
+		}
+		rendered.Requests = append(rendered.Requests, request)
+
+		// like the webhook-service, the response of a named request is only available to the subsequent requests
+		named := namedRequest{}
+		if node.Kind == yaml.MappingNode && node.Decode(&named) == nil && named.Name != "" {
+			namedResponses[named.Name] = responses[named.Name]
+			data["responses"] = namedResponses
+		}
+	}
+
+	if webhook.Async.Poll != nil {
+		// the poll request is executed without a callback URL
+		delete(data, "callback")
+		request, err := renderRequest(webhook.Async.Poll.Request, data)
+		if err != nil {
+			return nil, fmt.Errorf("poll request: %w", err)
+		}
+		pollRequest, ok := request.(Request)
+		if !ok {
+			return nil, errors.New("poll request must not be a curl command")
+		}
+		rendered.Poll = &pollRequest
+	}
+	return rendered, nil
+}
+
+// responsePlaceholders returns placeholders for the responses of the named requests, since they are only known during the execution.
+// The placeholders contain the outputs of each request and all fields that are referenced via {{.responses.<name>.<field>}}
+func responsePlaceholders(webhook Webhook) map[string]map[string]interface{} {
+	responses := map[string]map[string]interface{}{}
+	for _, node := range webhook.Requests {
+		named := namedRequest{}
+		if node.Kind != yaml.MappingNode || node.Decode(&named) != nil || named.Name == "" {
+			continue
+		}
+		responses[named.Name] = map[string]interface{}{}
+		for _, output := range named.Response.Outputs {
+			responses[named.Name][output.Name] = fmt.Sprintf("<response %s.%s>", named.Name, output.Name)
+		}
+	}
+
+	references := [][]string{}
+	for i := range webhook.Requests {
+		collectResponseReferences(&webhook.Requests[i], &references)
+	}
+	if webhook.Async.Poll != nil {
+		collectResponseReferences(&webhook.Async.Poll.Request, &references)
+	}
+	for _, reference := range references {
+		response, ok := responses[reference[1]]
+		if !ok || reference[2] == "" {
+			continue
+		}
+		addPlaceholder(response, strings.Split(strings.TrimPrefix(reference[2], "."), "."), "<response "+reference[1]+reference[2]+">")
+	}
+	return responses
+}
+
+// collectResponseReferences adds the references to named responses of all scalar values of the node
+func collectResponseReferences(node *yaml.Node, references *[][]string) {
+	if node.Kind == yaml.ScalarNode {
+		*references = append(*references, responseReferencePattern.FindAllStringSubmatch(node.Value, -1)...)
+	}
+	for _, child := range node.Content {
+		collectResponseReferences(child, references)
+	}
+}
+
+// addPlaceholder sets the placeholder at the given path of nested maps, replacing placeholders of parent fields if needed
+func addPlaceholder(data map[string]interface{}, path []string, placeholder string) {
+	for _, field := range path[:len(path)-1] {
+		child, ok := data[field].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			data[field] = child
+		}
+		data = child
+	}
+	if _, ok := data[path[len(path)-1]].(map[string]interface{}); !ok {
+		data[path[len(path)-1]] = placeholder
+	}
+}
+
+func renderRequest(node yaml.Node, data map[string]interface{}) (interface{}, error) {
+	// v1alpha1 requests are curl commands
+	if node.Kind == yaml.ScalarNode {
+		return parseTemplate(data, node.Value)
+	}
+
+	request := Request{}
+	if err := node.Decode(&request); err != nil {
+		return nil, err
+	}
+	var err error
+	parse := func(templateStr string) string {
+		if err != nil || templateStr == "" {
+			return templateStr
+		}
+		var parsed string
+		parsed, err = parseTemplate(data, templateStr)
+		return parsed
+	}
+	request.URL = parse(request.URL)
+	request.Payload = parse(request.Payload)
+	request.Proxy = parse(request.Proxy)
+	for i, header := range request.Headers {
+		request.Headers[i] = Header{Key: parse(header.Key), Value: parse(header.Value)}
+	}
+	return request, err
+}
+
+func parseTemplate(data interface{}, templateStr string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Funcs(templateFuncs).Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+	var tpl bytes.Buffer
+	if err := tmpl.Execute(&tpl, data); err != nil {
+		return "", err
+	}
+	return tpl.String(), nil
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleEvent = `{
+  "type": "sh.keptn.event.webhook.triggered",
+  "id": "my-event-id",
+  "time": "2022-02-07T11:15:46.123Z",
+  "shkeptncontext": "my-keptn-context",
+  "data": {
+    "project": "my-project",
+    "stage": "dev",
+    "service": "carts",
+    "labels": {"owner": "team-a"}
+  }
+}`
+
+const sampleWebhookConfig = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      envFrom:
+        - name: token
+          secretRef:
+            name: my-secret
+            key: token
+      requests:
+        - url: "http://my-webhook/{{.data.project}}/{{ upper .data.stage }}"
+          method: POST
+          headers:
+            - key: Authorization
+              value: "Bearer {{.env.token}}"
+          payload: '{"labels": {{ toJson .data.labels }}, "date": "{{ date "2006-01-02" .time }}", "version": "{{ default "latest" (get .data "configurationChange" "tag") }}", "callback": "{{.callback.url}}"}'
+    - type: "sh.keptn.event.test.triggered"
+      subscriptionID: "other-subscription-id"
+      requests:
+        - "curl http://my-webhook/{{.data.service}}"`
+
+func TestRender(t *testing.T) {
+	webhooks, err := Render([]byte(sampleWebhookConfig), []byte(sampleEvent), map[string]string{}, "")
+	require.Nil(t, err)
+	require.Equal(t, []RenderedWebhook{
+		{
+			SubscriptionID: "my-subscription-id",
+			Type:           "sh.keptn.event.webhook.triggered",
+			Requests: []interface{}{
+				Request{
+					URL:     "http://my-webhook/my-project/DEV",
+					Method:  "POST",
+					Headers: []Header{{Key: "Authorization", Value: "Bearer <secret my-secret/token>"}},
+					Payload: `{"labels": {"owner":"team-a"}, "date": "2022-02-07", "version": "latest", "callback": "<callback-url>"}`,
+				},
+			},
+		},
+		{
+			SubscriptionID: "other-subscription-id",
+			Type:           "sh.keptn.event.test.triggered",
+			Requests:       []interface{}{"curl http://my-webhook/carts"},
+		},
+	}, webhooks)
+}
+
+func TestRender_SubscriptionAndSecrets(t *testing.T) {
+	webhooks, err := Render([]byte(sampleWebhookConfig), []byte(sampleEvent), map[string]string{"token": "my-token"}, "my-subscription-id")
+	require.Nil(t, err)
+	require.Len(t, webhooks, 1)
+	require.Equal(t, "Bearer my-token", webhooks[0].Requests[0].(Request).Headers[0].Value)
+
+	_, err = Render([]byte(sampleWebhookConfig), []byte(sampleEvent), nil, "unknown")
+	require.EqualError(t, err, "no webhook found for subscription 'unknown'")
+}
+
+func TestRender_ChainedAndPollingWebhook(t *testing.T) {
+	webhooks, err := Render([]byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - name: login
+          url: http://my-webhook/login
+          method: POST
+          response:
+            outputs:
+              - name: token
+                jsonPath: $.token
+        - name: startJob
+          url: http://my-webhook/jobs
+          method: POST
+          headers:
+            - key: Authorization
+              value: "Bearer {{.responses.login.token}}"
+      async:
+        poll:
+          request:
+            url: "http://my-webhook/jobs/{{.responses.startJob.job.id}}"
+            method: GET
+          until:
+            - jsonPath: $.state
+              equals: done`), []byte(sampleEvent), nil, "")
+	require.Nil(t, err)
+	require.Equal(t, []RenderedWebhook{
+		{
+			SubscriptionID: "my-subscription-id",
+			Type:           "sh.keptn.event.webhook.triggered",
+			Requests: []interface{}{
+				Request{Name: "login", URL: "http://my-webhook/login", Method: "POST"},
+				Request{
+					Name:    "startJob",
+					URL:     "http://my-webhook/jobs",
+					Method:  "POST",
+					Headers: []Header{{Key: "Authorization", Value: "Bearer <response login.token>"}},
+				},
+			},
+			Poll: &Request{URL: "http://my-webhook/jobs/<response startJob.job.id>", Method: "GET"},
+		},
+	}, webhooks)
+
+	// responses are only available to the subsequent requests
+	_, err = Render([]byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+spec:
+  webhooks:
+    - subscriptionID: "my-subscription-id"
+      requests:
+        - url: "http://my-webhook/{{.responses.later.id}}"
+          method: GET
+        - name: later
+          url: http://my-webhook/jobs
+          method: POST`), []byte(sampleEvent), nil, "")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `map has no entry for key "responses"`)
+}
+
+func TestRender_Errors(t *testing.T) {
+	_, err := Render([]byte(sampleWebhookConfig), []byte(`{"type": "my-type"}`), nil, "")
+	require.EqualError(t, err, "event does not contain a 'data' property")
+
+	_, err = Render([]byte(`apiVersion: webhookconfig.keptn.sh/v1alpha1
+spec:
+  webhooks:
+    - subscriptionID: "my-subscription-id"
+      requests:
+        - "curl http://my-webhook/{{.data.unknown}}"`), []byte(sampleEvent), nil, "")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "could not render webhook for subscription 'my-subscription-id': request 1")
+	require.Contains(t, err.Error(), `map has no entry for key "unknown"`)
+
+	// functions that could read files or env vars are not available
+	_, err = Render([]byte(`apiVersion: webhookconfig.keptn.sh/v1alpha1
+spec:
+  webhooks:
+    - subscriptionID: "my-subscription-id"
+      requests:
+        - "curl http://my-webhook/{{ env \"HOME\" }}"`), []byte(sampleEvent), nil, "")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `function "env" not defined`)
+}
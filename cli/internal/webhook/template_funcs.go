@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs mirrors the functions available in the templates of the webhook-service (webhook-service/lib/template_funcs.go),
+// so templates can be rendered locally exactly as they will be rendered by Keptn. Both sets must be kept in sync
+var templateFuncs = template.FuncMap{
+	"toJson":  toJSON,
+	"quote":   quote,
+	"default": defaultValue,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"b64enc":  b64enc,
+	"date":    formatDate,
+	"hasKey":  hasKey,
+	"get":     get,
+}
+
+// toJSON returns the JSON representation of the value, e.g. {{ toJson .data.labels }}
+func toJSON(value interface{}) (string, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// quote returns the value as JSON string literal, i.e. in double quotes and with escaped special characters
+func quote(value interface{}) (string, error) {
+	if value == nil {
+		return `""`, nil
+	}
+	return toJSON(fmt.Sprint(value))
+}
+
+// defaultValue returns the value, or the given default if the value is empty, e.g. {{ default "latest" (get .data "configurationChange" "tag") }}
+func defaultValue(def interface{}, value interface{}) interface{} {
+	if isEmpty(value) {
+		return def
+	}
+	return value
+}
+
+func b64enc(value interface{}) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+}
+
+// formatDate formats a time.Time, an RFC3339 timestamp or unix seconds using the Go layout, e.g. {{ date "2006-01-02" .time }}
+func formatDate(layout string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case *time.Time:
+		if v == nil {
+			return "", fmt.Errorf("date: time is nil")
+		}
+		return v.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return "", fmt.Errorf("date: could not parse '%s' as RFC3339 timestamp", v)
+		}
+		return t.Format(layout), nil
+	case int:
+		return time.Unix(int64(v), 0).UTC().Format(layout), nil
+	case int64:
+		return time.Unix(v, 0).UTC().Format(layout), nil
+	case float64:
+		return time.Unix(int64(v), 0).UTC().Format(layout), nil
+	}
+	return "", fmt.Errorf("date: unsupported type %T", value)
+}
+
+// hasKey returns true if the map contains the key, e.g. {{ if hasKey .data "deployment" }}
+func hasKey(m interface{}, key string) bool {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	return v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())).IsValid()
+}
+
+// get returns the value of a nested map or slice, e.g. {{ get .data "deployment" "deploymentURIsPublic" 0 }}.
+// In contrast to the builtin index function, it returns nil instead of failing if a key or an index does not exist
+func get(item interface{}, keys ...interface{}) (interface{}, error) {
+	current := reflect.ValueOf(item)
+	for _, key := range keys {
+		for current.IsValid() && (current.Kind() == reflect.Interface || current.Kind() == reflect.Ptr) {
+			current = current.Elem()
+		}
+		if !current.IsValid() {
+			return nil, nil
+		}
+		switch current.Kind() {
+		case reflect.Map:
+			k, ok := key.(string)
+			if !ok || current.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("get: cannot index map with %v", key)
+			}
+			current = current.MapIndex(reflect.ValueOf(k).Convert(current.Type().Key()))
+		case reflect.Slice, reflect.Array:
+			i, ok := toInt(key)
+			if !ok {
+				return nil, fmt.Errorf("get: cannot index list with %v", key)
+			}
+			if i < 0 || i >= current.Len() {
+				return nil, nil
+			}
+			current = current.Index(i)
+		default:
+			return nil, fmt.Errorf("get: cannot index %s", current.Kind())
+		}
+	}
+	if !current.IsValid() {
+		return nil, nil
+	}
+	return current.Interface(), nil
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Map, reflect.Slice, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
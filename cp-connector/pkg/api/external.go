@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ExternalAPIConfig contains the information needed to connect to the Keptn API from outside the control plane,
+// e.g. from a remote execution plane
+type ExternalAPIConfig struct {
+	// Endpoint is the URL of the Keptn API, e.g. https://keptn.example.com/api
+	Endpoint string
+	// Token is the Keptn API token, which is sent in the x-token header
+	Token string
+	// OAuth can be set to authenticate using the OAuth client credentials flow
+	OAuth *OAuthConfig
+	// HTTPClient is the http client used for the requests to the Keptn API
+	HTTPClient *http.Client
+}
+
+// OAuthConfig contains the client credentials used to get an access token for the Keptn API
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	TokenURL     string
+}
+
+// NewExternal creates a new APISet usable for calling the Keptn API from outside the control plane
+func NewExternal(config ExternalAPIConfig) (*api.APISet, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("unable to create external api set: no endpoint set")
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if config.OAuth != nil {
+		conf := clientcredentials.Config{
+			ClientID:     config.OAuth.ClientID,
+			ClientSecret: config.OAuth.ClientSecret,
+			Scopes:       config.OAuth.Scopes,
+			TokenURL:     config.OAuth.TokenURL,
+		}
+		httpClient = conf.Client(context.WithValue(context.Background(), oauth2.HTTPClient, httpClient))
+	}
+	options := []func(*api.APISet){api.WithHTTPClient(httpClient)}
+	if config.Token != "" {
+		options = append(options, api.WithAuthToken(config.Token))
+	}
+	return api.New(config.Endpoint, options...)
+}
@@ -4,11 +4,21 @@ import (
 	"context"
 	"errors"
 	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/cp-connector/pkg/logger"
 )
 
 var ErrEventHandleFatal = errors.New("fatal event handling error")
 
+// tmpDataDistributorKey is the key of the temporary data containing the subscription ID of an event.
+// It is the same key the distributor uses, so that integrations can rely on it regardless of how they are connected
+const tmpDataDistributorKey = "distributor"
+
+// AdditionalSubscriptionData is the data added as temporary data to the events passed to the integration
+type AdditionalSubscriptionData struct {
+	SubscriptionID string `json:"subscriptionID"`
+}
+
 type RegistrationData models.Integration
 
 // Integration represents a Keptn Service that wants to receive events from the Keptn Control plane
@@ -25,31 +35,52 @@ type ControlPlane struct {
 	subscriptionSource   SubscriptionSource
 	eventSource          EventSource
 	currentSubscriptions []models.EventSubscription
+	errorLogSender       *ErrorLogSender
+	integrationID        string
+	sentEvents           *sentEventsCache
 	logger               logger.Logger
 }
 
+// WithErrorLogSender configures the ControlPlane to forward the error logs contained in the events sent
+// by the integration to the log ingestion API of the control plane
+func WithErrorLogSender(errorLogSender *ErrorLogSender) func(cp *ControlPlane) {
+	return func(cp *ControlPlane) {
+		cp.errorLogSender = errorLogSender
+	}
+}
+
 // New creates a new ControlPlane
 // It is using a SubscriptionSource source to get information about current uniform subscriptions
 // as well as an EventSource to actually receive events from Keptn
-func New(subscriptionSource SubscriptionSource, eventSource EventSource) *ControlPlane {
-	return &ControlPlane{
+func New(subscriptionSource SubscriptionSource, eventSource EventSource, options ...func(cp *ControlPlane)) *ControlPlane {
+	controlPlane := &ControlPlane{
 		subscriptionSource:   subscriptionSource,
 		eventSource:          eventSource,
 		currentSubscriptions: []models.EventSubscription{},
+		sentEvents:           newSentEventsCache(),
 		logger:               logger.NewDefaultLogger(),
 	}
+	for _, o := range options {
+		o(controlPlane)
+	}
+	return controlPlane
 }
 
 // Register is initially used to register the Keptn integration to the Control Plane
 func (cp *ControlPlane) Register(ctx context.Context, integration Integration) error {
 	eventUpdates := make(chan EventUpdate)
 	subscriptionUpdates := make(chan []models.EventSubscription)
-	if err := cp.eventSource.Start(ctx, integration.RegistrationData(), eventUpdates); err != nil {
+	registrationData := integration.RegistrationData()
+	if err := cp.eventSource.Start(ctx, registrationData, eventUpdates); err != nil {
 		return err
 	}
-	if err := cp.subscriptionSource.Start(ctx, integration.RegistrationData(), subscriptionUpdates); err != nil {
+	if err := cp.subscriptionSource.Start(ctx, registrationData, subscriptionUpdates); err != nil {
 		return err
 	}
+	if cp.errorLogSender != nil {
+		cp.integrationID = integrationID(registrationData)
+		cp.errorLogSender.Start(ctx)
+	}
 	for {
 		select {
 		case event := <-eventUpdates:
@@ -67,23 +98,74 @@ func (cp *ControlPlane) Register(ctx context.Context, integration Integration) e
 }
 
 func (cp *ControlPlane) handle(ctx context.Context, eventUpdate EventUpdate, integration Integration) error {
-	for _, subscription := range cp.currentSubscriptions {
-		if subscription.Event == eventUpdate.MetaData.Subject {
-			matcher := NewEventMatcherFromSubscription(subscription)
-			if matcher.Matches(eventUpdate.KeptnEvent) {
-				if err := integration.OnEvent(context.WithValue(ctx, EventSenderKey, cp.eventSource.Sender()), eventUpdate.KeptnEvent); err != nil {
-					if errors.Is(err, ErrEventHandleFatal) {
-						cp.logger.Errorf("Fatal error during handling of event: %v", err)
-						return err
-					}
-					cp.logger.Warnf("Error during handling of event: %v", err)
-				}
+	cp.sentEvents.removeExpired()
+	for _, subscription := range cp.getMatchingSubscriptions(eventUpdate) {
+		// with overlapping subscriptions, the event source might deliver the same event multiple times
+		if !cp.sentEvents.add(subscription, eventUpdate.KeptnEvent.ID) {
+			continue
+		}
+		event := eventUpdate.KeptnEvent
+		if event.Data == nil {
+			event.Data = map[string]interface{}{}
+		}
+		if err := event.AddTemporaryData(tmpDataDistributorKey, AdditionalSubscriptionData{SubscriptionID: subscription.ID}, models.AddTemporaryDataOptions{OverwriteIfExisting: true}); err != nil {
+			cp.logger.Warnf("Could not add temporary information about subscription %s to event: %v", subscription.ID, err)
+		}
+		if err := integration.OnEvent(context.WithValue(ctx, EventSenderKey, cp.sender()), event); err != nil {
+			if errors.Is(err, ErrEventHandleFatal) {
+				cp.logger.Errorf("Fatal error during handling of event: %v", err)
+				return err
 			}
+			cp.logger.Warnf("Error during handling of event: %v", err)
 		}
 	}
 	return nil
 }
 
+// getMatchingSubscriptions returns all current subscriptions whose event type and filters match the received event
+func (cp *ControlPlane) getMatchingSubscriptions(eventUpdate EventUpdate) []models.EventSubscription {
+	event := eventUpdate.KeptnEvent
+	if event.Type == nil {
+		// fall back to the subject the event has been received for
+		event.Type = &eventUpdate.MetaData.Subject
+	}
+	matchingSubscriptions := []models.EventSubscription{}
+	for _, subscription := range cp.currentSubscriptions {
+		if NewEventMatcherFromSubscription(subscription).Matches(event) {
+			matchingSubscriptions = append(matchingSubscriptions, subscription)
+		}
+	}
+	return matchingSubscriptions
+}
+
+// sender returns the EventSender of the event source, which additionally forwards error logs if an ErrorLogSender is set.
+// Like the distributor, sh.keptn.log.error events are only forwarded to the log ingestion API and are not sent as events
+func (cp *ControlPlane) sender() EventSender {
+	sender := cp.eventSource.Sender()
+	if cp.errorLogSender == nil {
+		return sender
+	}
+	return func(ce models.KeptnContextExtendedCE) error {
+		if err := cp.errorLogSender.SendLog(cp.integrationID, ce); err != nil {
+			cp.logger.Warnf("Unable to forward error log: %v", err)
+		}
+		if ce.Type != nil && *ce.Type == keptnv2.ErrorLogEventName {
+			return nil
+		}
+		return sender(ce)
+	}
+}
+
+// integrationID computes the ID the control plane assigns to the registration of the integration
+func integrationID(registrationData RegistrationData) string {
+	id, _ := models.IntegrationID{
+		Name:      registrationData.Name,
+		Namespace: registrationData.MetaData.KubernetesMetaData.Namespace,
+		NodeName:  registrationData.MetaData.Hostname,
+	}.Hash()
+	return id
+}
+
 func subjects(subscriptions []models.EventSubscription) []string {
 	var ret []string
 	for _, s := range subscriptions {
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/keptn/go-utils/pkg/api/models"
 	"github.com/keptn/go-utils/pkg/common/strutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/stretchr/testify/require"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -93,8 +95,14 @@ func TestControlPlaneInboundEventIsForwardedToIntegration(t *testing.T) {
 	subsChan <- []models.EventSubscription{{ID: "some-id", Event: "sh.keptn.event.echo.triggered", Filter: models.EventSubscriptionFilter{}}}
 	eventChan <- eventUpdate
 
+	expectedEvent := eventUpdate.KeptnEvent
+	expectedEvent.Data = map[string]interface{}{
+		"temporaryData": map[string]interface{}{
+			"distributor": AdditionalSubscriptionData{SubscriptionID: "some-id"},
+		},
+	}
 	require.Eventually(t, func() bool {
-		return reflect.DeepEqual(eventUpdate.KeptnEvent, integrationReceivedEvent)
+		return reflect.DeepEqual(expectedEvent, integrationReceivedEvent)
 	},
 		time.Second, time.Millisecond*100)
 }
@@ -184,3 +192,179 @@ func TestControlPlaneIntegrationOnEventThrowsFatalError(t *testing.T) {
 	require.Eventually(t, func() bool { return integrationReceivedEvent }, time.Second, time.Millisecond*100)
 	require.Eventually(t, func() bool { return controlPlaneErr != nil }, time.Second, time.Millisecond*100)
 }
+
+func TestControlPlaneForwardsErrorLogsOfSentEvents(t *testing.T) {
+	var eventChan chan EventUpdate
+	var subsChan chan []models.EventSubscription
+	var sentEvents []models.KeptnContextExtendedCE
+	var registrationData RegistrationData
+	var mtx sync.Mutex
+
+	ssm := &SubscriptionSourceMock{
+		StartFn: func(ctx context.Context, data RegistrationData, c chan []models.EventSubscription) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			registrationData = data
+			subsChan = c
+			return nil
+		},
+	}
+	esm := &EventSourceMock{
+		StartFn: func(ctx context.Context, data RegistrationData, ces chan EventUpdate) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			eventChan = ces
+			return nil
+		},
+		OnSubscriptionUpdateFn: func(strings []string) {},
+		SenderFn: func() EventSender {
+			return func(ce models.KeptnContextExtendedCE) error {
+				mtx.Lock()
+				defer mtx.Unlock()
+				sentEvents = append(sentEvents, ce)
+				return nil
+			}
+		},
+	}
+	logAPI := &LogsInterfaceMock{StartFn: func(ctx context.Context) {}}
+
+	controlPlane := New(ssm, esm, WithErrorLogSender(NewErrorLogSender(logAPI)))
+
+	integration := ExampleIntegration{
+		RegistrationDataFn: func() RegistrationData {
+			return RegistrationData{
+				Name:     "my-integration",
+				MetaData: models.MetaData{Hostname: "my-node", KubernetesMetaData: models.KubernetesMetaData{Namespace: "keptn"}},
+			}
+		},
+		OnEventFn: func(ctx context.Context, ce models.KeptnContextExtendedCE) error {
+			sender := ctx.Value(EventSenderKey).(EventSender)
+			if err := sender(models.KeptnContextExtendedCE{
+				Type: strutils.Stringp(keptnv2.ErrorLogEventName),
+				Data: keptnv2.ErrorLogEvent{Message: "something went wrong"},
+			}); err != nil {
+				return err
+			}
+			return sender(models.KeptnContextExtendedCE{
+				Type: strutils.Stringp(keptnv2.GetFinishedEventType("echo")),
+				Data: keptnv2.EventData{Status: keptnv2.StatusErrored, Message: "echo failed"},
+			})
+		},
+	}
+	go controlPlane.Register(context.TODO(), integration)
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return subsChan != nil && eventChan != nil
+	}, time.Second, time.Millisecond*100)
+
+	subsChan <- []models.EventSubscription{{ID: "some-id", Event: "sh.keptn.event.echo.triggered", Filter: models.EventSubscriptionFilter{}}}
+	eventChan <- EventUpdate{KeptnEvent: models.KeptnContextExtendedCE{ID: "some-id", Type: strutils.Stringp("sh.keptn.event.echo.triggered")}, MetaData: EventUpdateMetaData{Subject: "sh.keptn.event.echo.triggered"}}
+
+	require.Eventually(t, func() bool {
+		return len(logAPI.Logs()) == 2
+	}, time.Second, time.Millisecond*100)
+	expectedID, _ := models.IntegrationID{Name: "my-integration", Namespace: "keptn", NodeName: "my-node"}.Hash()
+	require.Equal(t, expectedID, logAPI.Logs()[0].IntegrationID)
+	require.Equal(t, "something went wrong", logAPI.Logs()[0].Message)
+	require.Equal(t, "echo failed", logAPI.Logs()[1].Message)
+	mtx.Lock()
+	defer mtx.Unlock()
+	// the error log event is only forwarded to the log ingestion API
+	require.Len(t, sentEvents, 1)
+	require.Equal(t, keptnv2.GetFinishedEventType("echo"), *sentEvents[0].Type)
+	require.Empty(t, registrationData.MetaData.DistributorVersion)
+}
+
+func TestControlPlaneEventMatchingOverlappingSubscriptionsIsForwardedPerSubscription(t *testing.T) {
+	var eventChan chan EventUpdate
+	var subsChan chan []models.EventSubscription
+	var receivedSubscriptionIDs []string
+	var mtx sync.Mutex
+
+	ssm := &SubscriptionSourceMock{
+		StartFn: func(ctx context.Context, data RegistrationData, c chan []models.EventSubscription) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			subsChan = c
+			return nil
+		},
+	}
+	esm := &EventSourceMock{
+		StartFn: func(ctx context.Context, data RegistrationData, ces chan EventUpdate) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			eventChan = ces
+			return nil
+		},
+		OnSubscriptionUpdateFn: func(strings []string) {},
+		SenderFn:               func() EventSender { return func(ce models.KeptnContextExtendedCE) error { return nil } },
+	}
+
+	controlPlane := New(ssm, esm)
+
+	integration := ExampleIntegration{
+		RegistrationDataFn: func() RegistrationData { return RegistrationData{} },
+		OnEventFn: func(ctx context.Context, ce models.KeptnContextExtendedCE) error {
+			subscriptionData := AdditionalSubscriptionData{}
+			if err := ce.GetTemporaryData("distributor", &subscriptionData); err != nil {
+				return err
+			}
+			mtx.Lock()
+			defer mtx.Unlock()
+			receivedSubscriptionIDs = append(receivedSubscriptionIDs, subscriptionData.SubscriptionID)
+			return nil
+		},
+	}
+	go controlPlane.Register(context.TODO(), integration)
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return subsChan != nil && eventChan != nil
+	}, time.Second, time.Millisecond*100)
+
+	subsChan <- []models.EventSubscription{
+		{ID: "id1", Event: "sh.keptn.event.echo.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"my-project"}}},
+		{ID: "id2", Event: "sh.keptn.event.>", Filter: models.EventSubscriptionFilter{}},
+		{ID: "id3", Event: "sh.keptn.event.*.finished", Filter: models.EventSubscriptionFilter{}},
+		{ID: "id4", Event: "sh.keptn.event.echo.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"other-project"}}},
+	}
+	event := models.KeptnContextExtendedCE{
+		ID:   "event-id",
+		Type: strutils.Stringp("sh.keptn.event.echo.triggered"),
+		Data: map[string]interface{}{"project": "my-project"},
+	}
+	// overlapping subscriptions of the event source deliver the event once per subscription subject
+	eventChan <- EventUpdate{KeptnEvent: event, MetaData: EventUpdateMetaData{Subject: "sh.keptn.event.echo.triggered"}}
+	eventChan <- EventUpdate{KeptnEvent: event, MetaData: EventUpdateMetaData{Subject: "sh.keptn.event.>"}}
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(receivedSubscriptionIDs) == 2
+	}, time.Second, time.Millisecond*100)
+	require.Never(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(receivedSubscriptionIDs) > 2
+	}, 300*time.Millisecond, time.Millisecond*100)
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, []string{"id1", "id2"}, receivedSubscriptionIDs)
+}
+
+func TestSentEventsCache(t *testing.T) {
+	now := time.Now()
+	cache := newSentEventsCache()
+	cache.now = func() time.Time { return now }
+	subscription := models.EventSubscription{ID: "id1", Event: "sh.keptn.event.>"}
+
+	require.True(t, cache.add(subscription, "event-id"))
+	require.False(t, cache.add(subscription, "event-id"))
+	require.True(t, cache.add(models.EventSubscription{ID: "id2", Event: "sh.keptn.event.>"}, "event-id"))
+	require.True(t, cache.add(subscription, "other-event-id"))
+
+	now = now.Add(sentEventsTTL + time.Second)
+	cache.removeExpired()
+	require.True(t, cache.add(subscription, "event-id"))
+}
@@ -0,0 +1,74 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// ErrorLogSender forwards the error logs of an integration to the log ingestion API of the control plane,
+// so that they are shown for the integration in the bridge
+type ErrorLogSender struct {
+	logAPI api.LogsV1Interface
+}
+
+// NewErrorLogSender creates a new ErrorLogSender
+func NewErrorLogSender(logAPI api.LogsV1Interface) *ErrorLogSender {
+	return &ErrorLogSender{logAPI: logAPI}
+}
+
+// Start triggers sending the collected error logs to the log ingestion API periodically
+func (l *ErrorLogSender) Start(ctx context.Context) {
+	l.logAPI.Start(ctx)
+}
+
+// SendLog creates a log entry for the integration if the event is a sh.keptn.log.error event
+// or a .finished event with status errored. All other events are ignored
+func (l *ErrorLogSender) SendLog(integrationID string, event models.KeptnContextExtendedCE) error {
+	if event.Type == nil {
+		return nil
+	}
+	if *event.Type == keptnv2.ErrorLogEventName {
+		eventData := &keptnv2.ErrorLogEvent{}
+		if err := keptnv2.EventDataAs(event, eventData); err != nil {
+			return fmt.Errorf("could not decode data of error log event: %w", err)
+		}
+		if eventData.IntegrationID != "" {
+			// the integration ID set in the event takes precedence
+			integrationID = eventData.IntegrationID
+		}
+		l.log(models.LogEntry{
+			IntegrationID: integrationID,
+			Message:       eventData.Message,
+			KeptnContext:  event.Shkeptncontext,
+			Task:          eventData.Task,
+			TriggeredID:   event.Triggeredid,
+		})
+		return nil
+	}
+	if keptnv2.IsFinishedEventType(*event.Type) {
+		eventData := &keptnv2.EventData{}
+		if err := keptnv2.EventDataAs(event, eventData); err != nil {
+			return fmt.Errorf("could not decode data of finished event: %w", err)
+		}
+		if eventData.Status != keptnv2.StatusErrored {
+			return nil
+		}
+		taskName, _, _ := keptnv2.ParseTaskEventType(*event.Type)
+		l.log(models.LogEntry{
+			IntegrationID: integrationID,
+			Message:       eventData.Message,
+			KeptnContext:  event.Shkeptncontext,
+			Task:          taskName,
+			TriggeredID:   event.Triggeredid,
+		})
+	}
+	return nil
+}
+
+func (l *ErrorLogSender) log(entry models.LogEntry) {
+	l.logAPI.Log([]models.LogEntry{entry})
+}
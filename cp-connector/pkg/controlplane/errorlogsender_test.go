@@ -0,0 +1,121 @@
+package controlplane
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	"github.com/keptn/go-utils/pkg/common/strutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/require"
+)
+
+type LogsInterfaceMock struct {
+	sync.Mutex
+	StartFn func(ctx context.Context)
+	logs    []models.LogEntry
+}
+
+func (m *LogsInterfaceMock) Log(logs []models.LogEntry) {
+	m.Lock()
+	defer m.Unlock()
+	m.logs = append(m.logs, logs...)
+}
+
+func (m *LogsInterfaceMock) Logs() []models.LogEntry {
+	m.Lock()
+	defer m.Unlock()
+	return m.logs
+}
+
+func (m *LogsInterfaceMock) Flush() error {
+	panic("implement me")
+}
+
+func (m *LogsInterfaceMock) GetLogs(params models.GetLogsParams) (*models.GetLogsResponse, error) {
+	panic("implement me")
+}
+
+func (m *LogsInterfaceMock) DeleteLogs(filter models.LogFilter) error {
+	panic("implement me")
+}
+
+func (m *LogsInterfaceMock) Start(ctx context.Context) {
+	if m.StartFn != nil {
+		m.StartFn(ctx)
+		return
+	}
+	panic("Start() not implemented")
+}
+
+func TestErrorLogSenderSendLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     models.KeptnContextExtendedCE
+		wantLogs  []models.LogEntry
+		wantError bool
+	}{
+		{
+			name: "error log event",
+			event: models.KeptnContextExtendedCE{
+				Type:           strutils.Stringp(keptnv2.ErrorLogEventName),
+				Shkeptncontext: "my-context",
+				Triggeredid:    "my-triggered-id",
+				Data:           keptnv2.ErrorLogEvent{Message: "something went wrong", Task: "deployment"},
+			},
+			wantLogs: []models.LogEntry{{IntegrationID: "my-integration-id", Message: "something went wrong", KeptnContext: "my-context", Task: "deployment", TriggeredID: "my-triggered-id"}},
+		},
+		{
+			name: "error log event with integration ID",
+			event: models.KeptnContextExtendedCE{
+				Type: strutils.Stringp(keptnv2.ErrorLogEventName),
+				Data: keptnv2.ErrorLogEvent{Message: "something went wrong", IntegrationID: "other-integration-id"},
+			},
+			wantLogs: []models.LogEntry{{IntegrationID: "other-integration-id", Message: "something went wrong"}},
+		},
+		{
+			name: "errored finished event",
+			event: models.KeptnContextExtendedCE{
+				Type:           strutils.Stringp("sh.keptn.event.deployment.finished"),
+				Shkeptncontext: "my-context",
+				Triggeredid:    "my-triggered-id",
+				Data:           keptnv2.EventData{Status: keptnv2.StatusErrored, Result: keptnv2.ResultFailed, Message: "deployment failed"},
+			},
+			wantLogs: []models.LogEntry{{IntegrationID: "my-integration-id", Message: "deployment failed", KeptnContext: "my-context", Task: "deployment", TriggeredID: "my-triggered-id"}},
+		},
+		{
+			name: "succeeded finished event",
+			event: models.KeptnContextExtendedCE{
+				Type: strutils.Stringp("sh.keptn.event.deployment.finished"),
+				Data: keptnv2.EventData{Status: keptnv2.StatusSucceeded, Result: keptnv2.ResultPass},
+			},
+		},
+		{
+			name: "started event",
+			event: models.KeptnContextExtendedCE{
+				Type: strutils.Stringp("sh.keptn.event.deployment.started"),
+				Data: keptnv2.EventData{},
+			},
+		},
+		{
+			name: "invalid error log event",
+			event: models.KeptnContextExtendedCE{
+				Type: strutils.Stringp(keptnv2.ErrorLogEventName),
+				Data: "invalid",
+			},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logAPI := &LogsInterfaceMock{}
+			errorLogSender := NewErrorLogSender(logAPI)
+
+			err := errorLogSender.SendLog("my-integration-id", tt.event)
+
+			require.Equal(t, tt.wantError, err != nil)
+			require.Equal(t, tt.wantLogs, logAPI.Logs())
+		})
+	}
+}
@@ -7,36 +7,60 @@ import (
 	"strings"
 )
 
-// EventMatcher is used to check whether an event contains is containing information
-// about a specif event, stage or service
+// EventMatcher is used to check whether an event is of a certain type
+// and contains information about a specific project, stage or service
 type EventMatcher struct {
-	Project string
-	Stage   string
-	Service string
+	// EventType is the subject the type of the event has to match, which may contain wildcards (see SubjectMatches)
+	EventType string
+	Projects  []string
+	Stages    []string
+	Services  []string
 }
 
 // NewEventMatcherFromSubscription creates a new EventMatcher that is configured
-// with information about project, stage and service filter contained in an event subscription
+// with the event type as well as the project, stage and service filter contained in an event subscription
 func NewEventMatcherFromSubscription(subscription models.EventSubscription) *EventMatcher {
 	return &EventMatcher{
-		Project: strings.Join(subscription.Filter.Projects, ","),
-		Stage:   strings.Join(subscription.Filter.Stages, ","),
-		Service: strings.Join(subscription.Filter.Services, ","),
+		EventType: subscription.Event,
+		Projects:  subscription.Filter.Projects,
+		Stages:    subscription.Filter.Stages,
+		Services:  subscription.Filter.Services,
 	}
 }
 
 // Matches checks whether a Keptn event matches the information of the currently configured
 // EventMatcher
 func (ef EventMatcher) Matches(e models.KeptnContextExtendedCE) bool {
+	if ef.EventType != "" && (e.Type == nil || !SubjectMatches(ef.EventType, *e.Type)) {
+		return false
+	}
+
 	generalEventData := &v0_2_0.EventData{}
 	if err := e.DataAs(generalEventData); err != nil {
 		return false
 	}
 
-	if ef.Project != "" && !sliceutils.ContainsStr(strings.Split(ef.Project, ","), generalEventData.Project) ||
-		ef.Stage != "" && !sliceutils.ContainsStr(strings.Split(ef.Stage, ","), generalEventData.Stage) ||
-		ef.Service != "" && !sliceutils.ContainsStr(strings.Split(ef.Service, ","), generalEventData.Service) {
+	if len(ef.Projects) > 0 && !sliceutils.ContainsStr(ef.Projects, generalEventData.Project) ||
+		len(ef.Stages) > 0 && !sliceutils.ContainsStr(ef.Stages, generalEventData.Stage) ||
+		len(ef.Services) > 0 && !sliceutils.ContainsStr(ef.Services, generalEventData.Service) {
 		return false
 	}
 	return true
 }
+
+// SubjectMatches checks whether a subject, e.g. the type of an event, matches the subject of a subscription.
+// Like in NATS, the subscription subject may contain the wildcard '*', matching exactly one token,
+// and end with the wildcard '>', matching one or more tokens
+func SubjectMatches(subscriptionSubject string, subject string) bool {
+	subscriptionTokens := strings.Split(subscriptionSubject, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range subscriptionTokens {
+		if token == ">" && i == len(subscriptionTokens)-1 {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(subscriptionTokens) == len(subjectTokens)
+}
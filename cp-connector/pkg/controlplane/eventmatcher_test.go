@@ -3,10 +3,19 @@ package controlplane
 import (
 	"github.com/keptn/go-utils/pkg/api/models"
 	"github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/go-utils/pkg/common/strutils"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
+func filterValues(filter string) []string {
+	if filter == "" {
+		return nil
+	}
+	return strings.Split(filter, ",")
+}
+
 func TestEventMatcherUnableToDecodeEventData(t *testing.T) {
 	require.False(t, EventMatcher{}.Matches(models.KeptnContextExtendedCE{Data: 0}))
 }
@@ -254,9 +263,9 @@ func TestEventMatcher_Matches(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ef := EventMatcher{
-				Project: tt.fields.Project,
-				Stage:   tt.fields.Stage,
-				Service: tt.fields.Service,
+				Projects: filterValues(tt.fields.Project),
+				Stages:   filterValues(tt.fields.Stage),
+				Services: filterValues(tt.fields.Service),
 			}
 			if got := ef.Matches(tt.args.e); got != tt.want {
 				t.Errorf("Matches() = %v, want %v", got, tt.want)
@@ -264,3 +273,97 @@ func TestEventMatcher_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestEventMatcher_MatchesSubscription(t *testing.T) {
+	eventData := v0_2_0.EventData{Project: "my-project", Stage: "my-stage", Service: "my-service"}
+	tests := []struct {
+		name         string
+		subscription models.EventSubscription
+		event        models.KeptnContextExtendedCE
+		want         bool
+	}{
+		{
+			name:         "no filter",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.task.triggered"},
+			event:        models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.triggered"), Data: eventData},
+			want:         true,
+		},
+		{
+			name:         "event type - should not match",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.task.triggered"},
+			event:        models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.finished"), Data: eventData},
+			want:         false,
+		},
+		{
+			name:         "no event type",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.task.triggered"},
+			event:        models.KeptnContextExtendedCE{Data: eventData},
+			want:         false,
+		},
+		{
+			name:         "wildcard event type",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.>"},
+			event:        models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.triggered"), Data: eventData},
+			want:         true,
+		},
+		{
+			name: "project filter (multiple values) - should match",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.*.triggered", Filter: models.EventSubscriptionFilter{
+				Projects: []string{"my-other-project", "my-project"},
+			}},
+			event: models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.triggered"), Data: eventData},
+			want:  true,
+		},
+		{
+			name: "project, stage and service filter - should not match",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.task.triggered", Filter: models.EventSubscriptionFilter{
+				Projects: []string{"my-project"},
+				Stages:   []string{"my-stage"},
+				Services: []string{"my-other-service"},
+			}},
+			event: models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.triggered"), Data: eventData},
+			want:  false,
+		},
+		{
+			name: "filter value containing a comma - should not match",
+			subscription: models.EventSubscription{Event: "sh.keptn.event.task.triggered", Filter: models.EventSubscriptionFilter{
+				Projects: []string{"my-project,my-other-project"},
+			}},
+			event: models.KeptnContextExtendedCE{Type: strutils.Stringp("sh.keptn.event.task.triggered"), Data: eventData},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewEventMatcherFromSubscription(tt.subscription).Matches(tt.event))
+		})
+	}
+}
+
+func TestSubjectMatches(t *testing.T) {
+	tests := []struct {
+		subscriptionSubject string
+		subject             string
+		want                bool
+	}{
+		{subscriptionSubject: "sh.keptn.event.task.triggered", subject: "sh.keptn.event.task.triggered", want: true},
+		{subscriptionSubject: "sh.keptn.event.task.triggered", subject: "sh.keptn.event.other.triggered", want: false},
+		{subscriptionSubject: "sh.keptn.event.task", subject: "sh.keptn.event.task.triggered", want: false},
+		{subscriptionSubject: "sh.keptn.event.task.triggered", subject: "sh.keptn.event.task", want: false},
+		{subscriptionSubject: "sh.keptn.event.*.triggered", subject: "sh.keptn.event.task.triggered", want: true},
+		{subscriptionSubject: "sh.keptn.event.*.triggered", subject: "sh.keptn.event.task.finished", want: false},
+		{subscriptionSubject: "sh.keptn.event.*.triggered", subject: "sh.keptn.event.task.sub.triggered", want: false},
+		{subscriptionSubject: "sh.keptn.event.*.*", subject: "sh.keptn.event.task.started", want: true},
+		{subscriptionSubject: "sh.keptn.event.>", subject: "sh.keptn.event.task.triggered", want: true},
+		{subscriptionSubject: "sh.keptn.event.>", subject: "sh.keptn.event.task.status.changed", want: true},
+		{subscriptionSubject: "sh.keptn.event.>", subject: "sh.keptn.event", want: false},
+		{subscriptionSubject: "sh.keptn.>", subject: "sh.keptn.log.error", want: true},
+		{subscriptionSubject: "sh.keptn.>.triggered", subject: "sh.keptn.event.task.triggered", want: false},
+		{subscriptionSubject: ">", subject: "sh.keptn.event.task.triggered", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subscriptionSubject+" "+tt.subject, func(t *testing.T) {
+			require.Equal(t, tt.want, SubjectMatches(tt.subscriptionSubject, tt.subject))
+		})
+	}
+}
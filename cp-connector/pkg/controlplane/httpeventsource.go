@@ -0,0 +1,168 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/keptn/go-utils/pkg/api/models"
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	keptnapi "github.com/keptn/keptn/cp-connector/pkg/api"
+	"github.com/keptn/keptn/cp-connector/pkg/logger"
+)
+
+// HTTPEventSource is an implementation of EventSource
+// that polls the Keptn API for triggered events and sends events back via the Keptn API.
+// It can be used by integrations running outside of the Keptn cluster
+type HTTPEventSource struct {
+	mutex              sync.Mutex
+	clock              clock.Clock
+	pollInterval       time.Duration
+	shipyardControlAPI api.ShipyardControlV1Interface
+	eventAPI           api.APIV1Interface
+	currentSubjects    []string
+	sentEvents         map[string]map[string]struct{}
+	cancel             context.CancelFunc
+	logger             logger.Logger
+}
+
+// WithPollInterval specifies the interval the event source should
+// use when polling for new triggered events
+func WithPollInterval(interval time.Duration) func(s *HTTPEventSource) {
+	return func(s *HTTPEventSource) {
+		s.pollInterval = interval
+	}
+}
+
+// NewHTTPEventSource creates a new HTTPEventSource
+func NewHTTPEventSource(shipyardControlAPI api.ShipyardControlV1Interface, eventAPI api.APIV1Interface, options ...func(source *HTTPEventSource)) *HTTPEventSource {
+	eventSource := &HTTPEventSource{
+		clock:              clock.New(),
+		pollInterval:       time.Second * 10,
+		shipyardControlAPI: shipyardControlAPI,
+		eventAPI:           eventAPI,
+		currentSubjects:    []string{},
+		sentEvents:         map[string]map[string]struct{}{},
+		logger:             logger.NewDefaultLogger(),
+	}
+	for _, o := range options {
+		o(eventSource)
+	}
+	return eventSource
+}
+
+func (hes *HTTPEventSource) Start(ctx context.Context, registrationData RegistrationData, eventChannel chan EventUpdate) error {
+	ctx, cancel := context.WithCancel(ctx)
+	hes.mutex.Lock()
+	hes.cancel = cancel
+	hes.mutex.Unlock()
+	ticker := hes.clock.Ticker(hes.pollInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				hes.doPoll(ctx, eventChannel)
+			}
+		}
+	}()
+	return nil
+}
+
+// OnSubscriptionUpdate sets the subjects whose triggered events are polled. Since the Keptn API can only be queried
+// for the triggered events of a specific type, subjects containing wildcards are skipped
+func (hes *HTTPEventSource) OnSubscriptionUpdate(subjects []string) {
+	polledSubjects := []string{}
+	for _, subject := range dedup(subjects) {
+		if isWildcardSubject(subject) {
+			hes.logger.Warnf("Subject %s contains wildcards and can not be polled via the Keptn API", subject)
+			continue
+		}
+		polledSubjects = append(polledSubjects, subject)
+	}
+	hes.mutex.Lock()
+	defer hes.mutex.Unlock()
+	hes.currentSubjects = polledSubjects
+}
+
+func (hes *HTTPEventSource) Sender() EventSender {
+	return func(ce models.KeptnContextExtendedCE) error {
+		if _, err := hes.eventAPI.SendEvent(ce); err != nil {
+			return fmt.Errorf("could not send event via Keptn API: %s", err.GetMessage())
+		}
+		return nil
+	}
+}
+
+func (hes *HTTPEventSource) Stop() error {
+	hes.mutex.Lock()
+	defer hes.mutex.Unlock()
+	if hes.cancel != nil {
+		hes.cancel()
+	}
+	return nil
+}
+
+func (hes *HTTPEventSource) doPoll(ctx context.Context, eventChannel chan EventUpdate) {
+	hes.mutex.Lock()
+	subjects := make([]string, len(hes.currentSubjects))
+	copy(subjects, hes.currentSubjects)
+	hes.mutex.Unlock()
+
+	sentEvents := make(map[string]map[string]struct{}, len(subjects))
+	for _, subject := range subjects {
+		events, err := hes.shipyardControlAPI.GetOpenTriggeredEvents(api.EventFilter{EventType: subject})
+		if err != nil {
+			hes.logger.Warnf("Could not retrieve events of type %s: %v", subject, err)
+			// keep the already sent events, so that they are not sent again once the API is available
+			sentEvents[subject] = hes.sentEvents[subject]
+			continue
+		}
+		sentEvents[subject] = map[string]struct{}{}
+		for _, event := range events {
+			if event == nil {
+				continue
+			}
+			sentEvents[subject][event.ID] = struct{}{}
+			if _, ok := hes.sentEvents[subject][event.ID]; ok {
+				continue
+			}
+			select {
+			case eventChannel <- EventUpdate{KeptnEvent: *event, MetaData: EventUpdateMetaData{Subject: subject}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	// only events which are still open are remembered
+	hes.sentEvents = sentEvents
+}
+
+func isWildcardSubject(subject string) bool {
+	for _, token := range strings.Split(subject, ".") {
+		if token == "*" || token == ">" {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPSubscriptionSource is a UniformSubscriptionSource which registers the integration
+// at the Keptn API of a remote control plane, authenticating with an API token or OAuth
+type HTTPSubscriptionSource struct {
+	*UniformSubscriptionSource
+}
+
+// NewHTTPSubscriptionSource creates a new HTTPSubscriptionSource
+func NewHTTPSubscriptionSource(config keptnapi.ExternalAPIConfig, options ...func(source *UniformSubscriptionSource)) (*HTTPSubscriptionSource, error) {
+	apiSet, err := keptnapi.NewExternal(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create HTTP subscription source: %w", err)
+	}
+	return &HTTPSubscriptionSource{UniformSubscriptionSource: NewUniformSubscriptionSource(apiSet.UniformV1(), options...)}, nil
+}
@@ -0,0 +1,204 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/keptn/go-utils/pkg/api/models"
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	keptnapi "github.com/keptn/keptn/cp-connector/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+type stubKeptnAPI struct {
+	sync.Mutex
+	triggeredEvents map[string][]*models.KeptnContextExtendedCE
+	polledTypes     []string
+	sentEvents      []models.KeptnContextExtendedCE
+	headers         []http.Header
+}
+
+func newStubKeptnAPI(t *testing.T) (*stubKeptnAPI, *httptest.Server) {
+	stub := &stubKeptnAPI{triggeredEvents: map[string][]*models.KeptnContextExtendedCE{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.Lock()
+		defer stub.Unlock()
+		stub.headers = append(stub.headers, r.Header.Clone())
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/controlPlane/v1/event/triggered/"):
+			eventType := strings.TrimPrefix(r.URL.Path, "/controlPlane/v1/event/triggered/")
+			stub.polledTypes = append(stub.polledTypes, eventType)
+			_ = json.NewEncoder(w).Encode(models.Events{Events: stub.triggeredEvents[eventType]})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/event":
+			event := models.KeptnContextExtendedCE{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+			stub.sentEvents = append(stub.sentEvents, event)
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/controlPlane/v1/uniform/registration":
+			_ = json.NewEncoder(w).Encode(models.RegisterIntegrationResponse{ID: "my-integration-id"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+func (s *stubKeptnAPI) addTriggeredEvent(eventType string, id string) {
+	s.Lock()
+	defer s.Unlock()
+	s.triggeredEvents[eventType] = append(s.triggeredEvents[eventType], &models.KeptnContextExtendedCE{ID: id, Type: &eventType})
+}
+
+func (s *stubKeptnAPI) lastHeader() http.Header {
+	s.Lock()
+	defer s.Unlock()
+	return s.headers[len(s.headers)-1]
+}
+
+func newTestHTTPEventSource(t *testing.T, server *httptest.Server) (*HTTPEventSource, *clock.Mock) {
+	apiSet, err := api.New(server.URL)
+	require.NoError(t, err)
+	eventSource := NewHTTPEventSource(apiSet.ShipyardControlV1(), apiSet.APIV1(), WithPollInterval(time.Second))
+	clk := clock.NewMock()
+	eventSource.clock = clk
+	return eventSource, clk
+}
+
+func TestHTTPEventSourcePollsTriggeredEventsOfSubjects(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	stub.addTriggeredEvent("sh.keptn.event.task.triggered", "id-1")
+	stub.addTriggeredEvent("sh.keptn.event.other.triggered", "id-2")
+	eventSource, clk := newTestHTTPEventSource(t, server)
+	eventUpdates := make(chan EventUpdate)
+
+	err := eventSource.Start(context.TODO(), RegistrationData{}, eventUpdates)
+	require.NoError(t, err)
+	eventSource.OnSubscriptionUpdate([]string{"sh.keptn.event.task.triggered", "sh.keptn.event.task.triggered"})
+	clk.Add(time.Second)
+
+	eventUpdate := <-eventUpdates
+	require.Equal(t, "id-1", eventUpdate.KeptnEvent.ID)
+	require.Equal(t, "sh.keptn.event.task.triggered", eventUpdate.MetaData.Subject)
+}
+
+func TestHTTPEventSourceSkipsAlreadySentEvents(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	stub.addTriggeredEvent("sh.keptn.event.task.triggered", "id-1")
+	eventSource, clk := newTestHTTPEventSource(t, server)
+	eventUpdates := make(chan EventUpdate)
+
+	err := eventSource.Start(context.TODO(), RegistrationData{}, eventUpdates)
+	require.NoError(t, err)
+	eventSource.OnSubscriptionUpdate([]string{"sh.keptn.event.task.triggered"})
+	clk.Add(time.Second)
+	require.Equal(t, "id-1", (<-eventUpdates).KeptnEvent.ID)
+
+	stub.addTriggeredEvent("sh.keptn.event.task.triggered", "id-2")
+	clk.Add(time.Second)
+	require.Equal(t, "id-2", (<-eventUpdates).KeptnEvent.ID)
+}
+
+func TestHTTPEventSourceSkipsWildcardSubjects(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	stub.addTriggeredEvent("sh.keptn.event.task.triggered", "id-1")
+	eventSource, clk := newTestHTTPEventSource(t, server)
+	eventUpdates := make(chan EventUpdate)
+
+	err := eventSource.Start(context.TODO(), RegistrationData{}, eventUpdates)
+	require.NoError(t, err)
+	eventSource.OnSubscriptionUpdate([]string{"sh.keptn.event.*.triggered", "sh.keptn.event.task.triggered", "sh.keptn.event.>"})
+	clk.Add(time.Second)
+	require.Equal(t, "id-1", (<-eventUpdates).KeptnEvent.ID)
+
+	stub.Lock()
+	defer stub.Unlock()
+	require.Equal(t, []string{"sh.keptn.event.task.triggered"}, stub.polledTypes)
+}
+
+func TestHTTPEventSourceStop(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	stub.addTriggeredEvent("sh.keptn.event.task.triggered", "id-1")
+	eventSource, clk := newTestHTTPEventSource(t, server)
+	eventUpdates := make(chan EventUpdate)
+
+	err := eventSource.Start(context.TODO(), RegistrationData{}, eventUpdates)
+	require.NoError(t, err)
+	eventSource.OnSubscriptionUpdate([]string{"sh.keptn.event.task.triggered"})
+	require.NoError(t, eventSource.Stop())
+	clk.Add(time.Second)
+
+	select {
+	case <-eventUpdates:
+		require.FailNow(t, "got event after event source has been stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHTTPEventSourceSender(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	apiSet, err := keptnapi.NewExternal(keptnapi.ExternalAPIConfig{Endpoint: server.URL, Token: "my-token"})
+	require.NoError(t, err)
+	eventSource := NewHTTPEventSource(apiSet.ShipyardControlV1(), apiSet.APIV1())
+
+	eventType := "sh.keptn.event.task.started"
+	err = eventSource.Sender()(models.KeptnContextExtendedCE{ID: "id-1", Type: &eventType})
+	require.NoError(t, err)
+
+	require.Len(t, stub.sentEvents, 1)
+	require.Equal(t, "id-1", stub.sentEvents[0].ID)
+	require.Equal(t, "my-token", stub.lastHeader().Get("x-token"))
+}
+
+func TestHTTPEventSourceSenderFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"code":500,"message":"internal error"}`))
+	}))
+	defer server.Close()
+	eventSource, _ := newTestHTTPEventSource(t, server)
+
+	err := eventSource.Sender()(models.KeptnContextExtendedCE{ID: "id-1"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "internal error")
+}
+
+func TestHTTPSubscriptionSourceWithOAuth(t *testing.T) {
+	stub, server := newStubKeptnAPI(t)
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"my-access-token","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	subscriptionSource, err := NewHTTPSubscriptionSource(keptnapi.ExternalAPIConfig{
+		Endpoint: server.URL,
+		OAuth: &keptnapi.OAuthConfig{
+			ClientID:     "my-client",
+			ClientSecret: "my-secret",
+			Scopes:       []string{"keptn:events"},
+			TokenURL:     tokenServer.URL,
+		},
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = subscriptionSource.Start(ctx, RegistrationData{Name: "my-integration"}, make(chan []models.EventSubscription))
+	require.NoError(t, err)
+	require.Equal(t, "Bearer my-access-token", stub.lastHeader().Get("Authorization"))
+}
+
+func TestHTTPSubscriptionSourceWithoutEndpoint(t *testing.T) {
+	_, err := NewHTTPSubscriptionSource(keptnapi.ExternalAPIConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package controlplane
+
+import (
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+)
+
+// sentEventsTTL is the duration an event is remembered after it has been passed to the integration
+const sentEventsTTL = 10 * time.Second
+
+// sentEventsCache remembers for a limited time which events have been passed to the integration for which subscription
+type sentEventsCache struct {
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+func newSentEventsCache() *sentEventsCache {
+	return &sentEventsCache{entries: map[string]time.Time{}, now: time.Now}
+}
+
+// add remembers the event for the subscription and returns false if it has already been added before
+func (c *sentEventsCache) add(subscription models.EventSubscription, eventID string) bool {
+	subscriptionKey := subscription.ID
+	if subscriptionKey == "" {
+		subscriptionKey = subscription.Event
+	}
+	key := subscriptionKey + "/" + eventID
+	if _, ok := c.entries[key]; ok {
+		return false
+	}
+	c.entries[key] = c.now()
+	return true
+}
+
+func (c *sentEventsCache) removeExpired() {
+	for key, added := range c.entries {
+		if c.now().Sub(added) > sentEventsTTL {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -16,10 +16,11 @@ type SubscriptionSource interface {
 
 // UniformSubscriptionSource represents a source for uniform subscriptions
 type UniformSubscriptionSource struct {
-	uniformAPI    api.UniformV1Interface
-	clock         clock.Clock
-	fetchInterval time.Duration
-	logger        logger.Logger
+	uniformAPI          api.UniformV1Interface
+	clock               clock.Clock
+	fetchInterval       time.Duration
+	maxHeartbeatRetries int
+	logger              logger.Logger
 }
 
 // WithFetchInterval specifies the interval the subscription source should
@@ -30,9 +31,17 @@ func WithFetchInterval(interval time.Duration) func(s *UniformSubscriptionSource
 	}
 }
 
+// WithMaxHeartbeatRetries specifies how many consecutive pings to the control plane may fail
+// before the subscription source tries to renew the registration of the integration
+func WithMaxHeartbeatRetries(retries int) func(s *UniformSubscriptionSource) {
+	return func(s *UniformSubscriptionSource) {
+		s.maxHeartbeatRetries = retries
+	}
+}
+
 // NewUniformSubscriptionSource creates a new UniformSubscriptionSource
 func NewUniformSubscriptionSource(uniformAPI api.UniformV1Interface, options ...func(source *UniformSubscriptionSource)) *UniformSubscriptionSource {
-	subscriptionSource := &UniformSubscriptionSource{uniformAPI: uniformAPI, clock: clock.New(), fetchInterval: time.Second * 5, logger: logger.NewDefaultLogger()}
+	subscriptionSource := &UniformSubscriptionSource{uniformAPI: uniformAPI, clock: clock.New(), fetchInterval: time.Second * 5, maxHeartbeatRetries: 10, logger: logger.NewDefaultLogger()}
 	for _, o := range options {
 		o(subscriptionSource)
 	}
@@ -47,6 +56,7 @@ func (s *UniformSubscriptionSource) Start(ctx context.Context, registrationData
 	}
 	ticker := s.clock.Ticker(s.fetchInterval)
 	go func() {
+		failedHeartbeats := 0
 		for {
 			select {
 			case <-ctx.Done():
@@ -54,16 +64,37 @@ func (s *UniformSubscriptionSource) Start(ctx context.Context, registrationData
 			case <-ticker.C:
 				updatedIntegrationData, err := s.uniformAPI.Ping(integrationID)
 				if err != nil {
-					s.logger.Errorf("Unable to ping control plane: %v", err)
+					failedHeartbeats++
+					s.logger.Errorf("Unable to ping control plane (retry count: %d/%d): %v", failedHeartbeats, s.maxHeartbeatRetries, err)
+					if failedHeartbeats >= s.maxHeartbeatRetries {
+						// the registration might have been removed by the control plane in the meantime
+						integrationID = s.renewRegistration(registrationData, integrationID)
+						failedHeartbeats = 0
+					}
 					continue
 				}
-				subscriptionChannel <- updatedIntegrationData.Subscriptions
+				failedHeartbeats = 0
+				select {
+				case subscriptionChannel <- updatedIntegrationData.Subscriptions:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 	return nil
 }
 
+func (s *UniformSubscriptionSource) renewRegistration(registrationData RegistrationData, integrationID string) string {
+	renewedIntegrationID, err := s.uniformAPI.RegisterIntegration(models.Integration(registrationData))
+	if err != nil {
+		s.logger.Errorf("Unable to renew registration of integration %s: %v", integrationID, err)
+		return integrationID
+	}
+	s.logger.Infof("Renewed registration of integration with ID %s", renewedIntegrationID)
+	return renewedIntegrationID
+}
+
 // FixedSubscriptionSource can be used to use a fixed list of subscriptions rather than
 // consulting the Keptn API for subscriptions.
 // This is useful when you want to consume events from an event source, but NOT register
@@ -6,6 +6,7 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/keptn/go-utils/pkg/api/models"
 	"github.com/stretchr/testify/require"
+	"sync"
 	"testing"
 	"time"
 )
@@ -227,3 +228,50 @@ func TestFixedSubscriptionSourcer_WithNoSubscriptions(t *testing.T) {
 	updates := <-subchan
 	require.Equal(t, 0, len(updates))
 }
+
+func TestSubscriptionSourceRenewsRegistrationAfterFailedHeartbeats(t *testing.T) {
+	var mtx sync.Mutex
+	registrationCount := 0
+	pingedIDs := []string{}
+
+	uniformInterface := &UniformInterfaceMock{
+		RegisterIntegrationFn: func(integration models.Integration) (string, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			registrationCount++
+			return fmt.Sprintf("id-%d", registrationCount), nil
+		},
+		PingFn: func(id string) (*models.Integration, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			pingedIDs = append(pingedIDs, id)
+			if id == "id-1" {
+				return nil, fmt.Errorf("integration not found")
+			}
+			return &models.Integration{ID: id}, nil
+		},
+	}
+
+	subscriptionSource := NewUniformSubscriptionSource(uniformInterface, WithMaxHeartbeatRetries(2))
+	clock := clock.NewMock()
+	subscriptionSource.clock = clock
+	subscriptionUpdates := make(chan []models.EventSubscription)
+
+	err := subscriptionSource.Start(context.TODO(), RegistrationData{}, subscriptionUpdates)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		clock.Add(5 * time.Second)
+		require.Eventually(t, func() bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+			return len(pingedIDs) == i+1
+		}, time.Second, 10*time.Millisecond)
+	}
+	clock.Add(5 * time.Second)
+	<-subscriptionUpdates
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, 2, registrationCount)
+	require.Equal(t, []string{"id-1", "id-1", "id-2"}, pingedIDs)
+}
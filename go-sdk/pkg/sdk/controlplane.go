@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/cp-connector/pkg/controlplane"
+)
+
+// ErrNoControlPlaneEventSender is returned when an event shall be sent before the control plane provided a way to send events
+var ErrNoControlPlaneEventSender = errors.New("no event sender provided by the control plane yet")
+
+type integrationEnvConfig struct {
+	Version           string `envconfig:"VERSION" default:""`
+	Location          string `envconfig:"LOCATION" default:""`
+	K8sDeploymentName string `envconfig:"K8S_DEPLOYMENT_NAME" default:""`
+	K8sNamespace      string `envconfig:"K8S_NAMESPACE" default:""`
+	K8sPodName        string `envconfig:"K8S_POD_NAME" default:""`
+	K8sNodeName       string `envconfig:"K8S_NODE_NAME" default:""`
+}
+
+// WithControlPlane configures keptn to register itself directly at the Keptn control plane using the given
+// controlplane.ControlPlane, instead of receiving and sending events via a distributor sidecar.
+// Events are then received from and sent to the event source of the control plane (e.g. NATS)
+func WithControlPlane(controlPlane *controlplane.ControlPlane) KeptnOption {
+	return func(k *Keptn) {
+		k.controlPlane = controlPlane
+		k.eventSender = &controlPlaneEventSender{}
+		k.receivedEvents = newReceivedEventsCache()
+	}
+}
+
+// OnEvent is called by the control plane for every received event matching the subscriptions of the integration.
+// Since the control plane passes an event once per matching subscription, events that have already been received are ignored
+func (k *Keptn) OnEvent(ctx context.Context, event models.KeptnContextExtendedCE) error {
+	if k.receivedEvents != nil && event.ID != "" && !k.receivedEvents.add(event.ID) {
+		k.logger.Debugf("Ignoring event %s, since it has already been received", event.ID)
+		return nil
+	}
+	if sender, ok := ctx.Value(controlplane.EventSenderKey).(controlplane.EventSender); ok {
+		if cpEventSender, ok := k.eventSender.(*controlPlaneEventSender); ok {
+			cpEventSender.setSender(sender)
+		}
+	}
+	if ctx.Value(gracefulShutdownKey) == nil {
+		ctx = context.WithValue(ctx, gracefulShutdownKey, &nopWG{})
+	}
+	ce := keptnv2.ToCloudEvent(event)
+	k.gotEvent(ctx, ce)
+	return nil
+}
+
+// RegistrationData returns the data used to register keptn as integration at the control plane.
+// The integration subscribes to the event types of all registered task handlers
+func (k *Keptn) RegistrationData() controlplane.RegistrationData {
+	var env integrationEnvConfig
+	if err := envconfig.Process("", &env); err != nil {
+		k.logger.Errorf("failed to process env var: %v", err)
+	}
+	subscriptions := []models.EventSubscription{}
+	subscribeToAborts := false
+	k.taskRegistry.RLock()
+	for eventType, entry := range k.taskRegistry.Entries {
+		if eventType == "*" {
+			eventType = "sh.keptn.>"
+		}
+		subscriptions = append(subscriptions, models.EventSubscription{Event: eventType})
+		subscribeToAborts = subscribeToAborts || entry.ContextTaskHandler != nil
+	}
+	k.taskRegistry.RUnlock()
+	// context aware task handlers are cancelled when the sequence is aborted
+	if subscribeToAborts {
+		subscriptions = append(subscriptions, models.EventSubscription{Event: SequenceAbortedEventType})
+	}
+	return controlplane.RegistrationData{
+		Name: k.source,
+		MetaData: models.MetaData{
+			Hostname:           env.K8sNodeName,
+			IntegrationVersion: env.Version,
+			Location:           env.Location,
+			KubernetesMetaData: models.KubernetesMetaData{
+				Namespace:      env.K8sNamespace,
+				PodName:        env.K8sPodName,
+				DeploymentName: env.K8sDeploymentName,
+			},
+		},
+		Subscriptions: subscriptions,
+	}
+}
+
+// controlPlaneEventSender sends events using the sender provided by the control plane together with a received event
+type controlPlaneEventSender struct {
+	sync.RWMutex
+	sender controlplane.EventSender
+}
+
+func (s *controlPlaneEventSender) setSender(sender controlplane.EventSender) {
+	s.Lock()
+	defer s.Unlock()
+	s.sender = sender
+}
+
+func (s *controlPlaneEventSender) SendEvent(event cloudevents.Event) error {
+	s.RLock()
+	sender := s.sender
+	s.RUnlock()
+	if sender == nil {
+		return ErrNoControlPlaneEventSender
+	}
+	keptnEvent := models.KeptnContextExtendedCE{}
+	if err := keptnv2.Decode(&event, &keptnEvent); err != nil {
+		return fmt.Errorf("unable to convert event %s: %w", event.Type(), err)
+	}
+	return sender(keptnEvent)
+}
@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/cp-connector/pkg/controlplane"
+	"github.com/stretchr/testify/require"
+)
+
+type testEventSource struct {
+	sync.Mutex
+	eventChannel chan controlplane.EventUpdate
+	sentEvents   []models.KeptnContextExtendedCE
+	subscribed   chan struct{}
+}
+
+func (t *testEventSource) Start(ctx context.Context, data controlplane.RegistrationData, updates chan controlplane.EventUpdate) error {
+	t.eventChannel = updates
+	return nil
+}
+
+func (t *testEventSource) OnSubscriptionUpdate(subjects []string) {
+	close(t.subscribed)
+}
+
+func (t *testEventSource) Sender() controlplane.EventSender {
+	return func(ce models.KeptnContextExtendedCE) error {
+		t.Lock()
+		defer t.Unlock()
+		t.sentEvents = append(t.sentEvents, ce)
+		return nil
+	}
+}
+
+func (t *testEventSource) Stop() error {
+	return nil
+}
+
+func (t *testEventSource) SentEvents() []models.KeptnContextExtendedCE {
+	t.Lock()
+	defer t.Unlock()
+	return append([]models.KeptnContextExtendedCE{}, t.sentEvents...)
+}
+
+func Test_WhenReceivingAnEventFromTheControlPlane_StartedEventAndFinishedEventsAreSent(t *testing.T) {
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		return FakeTaskData{}, nil
+	}
+
+	eventSource := &testEventSource{subscribed: make(chan struct{})}
+	subscriptionSource := controlplane.NewFixedSubscriptionSource(controlplane.WithFixedSubscriptions(models.EventSubscription{Event: "sh.keptn.event.faketask.triggered"}))
+
+	keptn := NewKeptn("fake-service",
+		WithControlPlane(controlplane.New(subscriptionSource, eventSource)),
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithGracefulShutdown(false),
+	)
+	go keptn.Start()
+	<-eventSource.subscribed
+
+	keptnEvent := models.KeptnContextExtendedCE{}
+	require.Nil(t, keptnv2.Decode(newTestTaskTriggeredEvent(), &keptnEvent))
+	eventSource.eventChannel <- controlplane.EventUpdate{KeptnEvent: keptnEvent, MetaData: controlplane.EventUpdateMetaData{Subject: "sh.keptn.event.faketask.triggered"}}
+	require.Eventually(t, func() bool {
+		return len(eventSource.SentEvents()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	sentEvents := eventSource.SentEvents()
+	require.Equal(t, "sh.keptn.event.faketask.started", *sentEvents[0].Type)
+	require.Equal(t, "sh.keptn.event.faketask.finished", *sentEvents[1].Type)
+	require.Equal(t, "keptncontext", sentEvents[1].Shkeptncontext)
+	require.Equal(t, "fake-service", *sentEvents[1].Source)
+}
+
+func Test_WhenAnEventMatchesSeveralSubscriptions_TheTaskIsOnlyExecutedOnce(t *testing.T) {
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		return FakeTaskData{}, nil
+	}
+
+	eventSource := &testEventSource{subscribed: make(chan struct{})}
+	subscriptionSource := controlplane.NewFixedSubscriptionSource(controlplane.WithFixedSubscriptions(
+		models.EventSubscription{ID: "sub-1", Event: "sh.keptn.event.faketask.triggered"},
+		models.EventSubscription{ID: "sub-2", Event: "sh.keptn.event.>"},
+	))
+
+	keptn := NewKeptn("fake-service",
+		WithControlPlane(controlplane.New(subscriptionSource, eventSource)),
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithGracefulShutdown(false),
+	)
+	go keptn.Start()
+	<-eventSource.subscribed
+
+	keptnEvent := models.KeptnContextExtendedCE{}
+	require.Nil(t, keptnv2.Decode(newTestTaskTriggeredEvent(), &keptnEvent))
+	eventSource.eventChannel <- controlplane.EventUpdate{KeptnEvent: keptnEvent, MetaData: controlplane.EventUpdateMetaData{Subject: "sh.keptn.event.faketask.triggered"}}
+	require.Eventually(t, func() bool {
+		return len(eventSource.SentEvents()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool {
+		return len(eventSource.SentEvents()) > 2
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	require.Len(t, taskHandler.ExecuteCalls(), 1)
+}
+
+func Test_ReceivedEventsCache(t *testing.T) {
+	now := time.Now()
+	cache := newReceivedEventsCache()
+	cache.now = func() time.Time { return now }
+
+	require.True(t, cache.add("event-1"))
+	require.False(t, cache.add("event-1"))
+	require.True(t, cache.add("event-2"))
+
+	now = now.Add(receivedEventsTTL + time.Second)
+	require.True(t, cache.add("event-1"))
+}
+
+func Test_RegistrationData(t *testing.T) {
+	t.Setenv("VERSION", "1.0.0")
+	t.Setenv("K8S_NAMESPACE", "keptn")
+	keptn := NewKeptn("fake-service",
+		WithTaskHandler("sh.keptn.event.faketask.triggered", &TaskHandlerMock{}),
+	)
+
+	registrationData := keptn.RegistrationData()
+
+	require.Equal(t, "fake-service", registrationData.Name)
+	require.Equal(t, "1.0.0", registrationData.MetaData.IntegrationVersion)
+	require.Equal(t, "keptn", registrationData.MetaData.KubernetesMetaData.Namespace)
+	require.Equal(t, []models.EventSubscription{{Event: "sh.keptn.event.faketask.triggered"}}, registrationData.Subscriptions)
+}
+
+func Test_ControlPlaneEventSender_NoSenderProvided(t *testing.T) {
+	sender := &controlPlaneEventSender{}
+	require.ErrorIs(t, sender.SendEvent(newTestTaskTriggeredEvent()), ErrNoControlPlaneEventSender)
+}
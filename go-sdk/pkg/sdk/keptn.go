@@ -8,10 +8,13 @@ import (
 	"github.com/keptn/go-utils/pkg/api/models"
 	api "github.com/keptn/go-utils/pkg/api/utils"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/cp-connector/pkg/controlplane"
+	"net/url"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 )
 
 const DefaultHTTPEventEndpoint = "http://localhost:8081/event"
@@ -19,11 +22,23 @@ const KeptnContextCEExtension = "shkeptncontext"
 const TriggeredIDCEExtension = "triggeredid"
 const GitCommitIDCEExtension = "gitcommitid"
 
+// StatusChangedEventKind is the kind of the events reporting the progress of a task, e.g. sh.keptn.event.test.status.changed
+const StatusChangedEventKind = "status.changed"
+
 //go:generate moq  -out ./resourcehandler_mock.go . ResourceHandler
 type ResourceHandler interface {
 	GetResource(scope api.ResourceScope, options ...api.URIOption) (*models.Resource, error)
 }
 
+// commitIDQueryParam is the query parameter used to retrieve a resource in the version of a certain commit
+const commitIDQueryParam = "gitCommitID"
+
+// WithCommitID can be passed to ResourceHandler.GetResource to retrieve the resource in the version of the given commit,
+// e.g. the one of the gitcommitid extension of an event
+func WithCommitID(commitID string) api.URIOption {
+	return api.AppendQuery(url.Values{commitIDQueryParam: []string{commitID}})
+}
+
 //go:generate moq  -out ./eventsender_mock.go . EventSender
 type EventSender interface {
 	SendEvent(event cloudevents.Event) error
@@ -43,6 +58,9 @@ type IKeptn interface {
 	SendStartedEvent(event KeptnEvent) error
 	// SendFinishedEvent sends a finished event for the given input event to the Keptn API
 	SendFinishedEvent(event KeptnEvent, result interface{}) error
+	// SendStatusChangedEvent sends a .status.changed event for the given input event to the Keptn API, which reports the
+	// progress of a long-running task with a message and its percentage of completion
+	SendStatusChangedEvent(event KeptnEvent, message string, progress int) error
 	// Logger returns the logger used by the sdk
 	// Per default DefaultLogger is used which internally just uses the go logging package
 	// Another logger can be configured using the sdk.WithLogger function
@@ -61,6 +79,13 @@ type TaskHandler interface {
 
 type KeptnEvent models.KeptnContextExtendedCE
 
+// StatusChangedEventData is the data of a .status.changed event
+type StatusChangedEventData struct {
+	keptnv2.EventData
+	// Progress is the percentage of completion of the task
+	Progress int `json:"progress,omitempty"`
+}
+
 // Opaque key type used for graceful shutdown context value
 type gracefulShutdownKeyType struct{}
 
@@ -134,6 +159,8 @@ func WithLogger(logger Logger) KeptnOption {
 type Keptn struct {
 	eventSender            EventSender
 	eventReceiver          EventReceiver
+	controlPlane           *controlplane.ControlPlane
+	receivedEvents         *receivedEventsCache
 	resourceHandler        ResourceHandler
 	source                 string
 	taskRegistry           *TaskRegistry
@@ -142,6 +169,11 @@ type Keptn struct {
 	gracefulShutdown       bool
 	receivingEvent         interface{}
 	logger                 Logger
+	taskPool               *taskPool
+	taskMetricsHook        TaskMetricsHook
+	runningTasks           runningTasks
+	shutdownTimeout        time.Duration
+	subscriptionFilter     *subscriptionFilter
 }
 
 // NewKeptn creates a new Keptn
@@ -160,16 +192,31 @@ func NewKeptn(source string, opts ...KeptnOption) *Keptn {
 		gracefulShutdown:       true,
 		syncProcessing:         false,
 		logger:                 logger,
+		shutdownTimeout:        DefaultShutdownTimeout,
 	}
 	for _, opt := range opts {
 		opt(keptn)
 	}
+	if keptn.taskPool != nil {
+		keptn.taskPool.onUpdate = keptn.reportTaskMetrics
+	}
 	return keptn
 }
 
 func (k *Keptn) Start() error {
 	ctx := getContext(k.gracefulShutdown)
-	err := k.eventReceiver.StartReceiver(ctx, k.gotEvent)
+	if k.subscriptionFilter != nil {
+		k.subscriptionFilter.start(ctx, k.logger)
+	}
+	var err error
+	if k.controlPlane != nil {
+		err = k.controlPlane.Register(ctx, k)
+	} else {
+		err = k.eventReceiver.StartReceiver(ctx, k.gotEvent)
+	}
+	if ctx.Err() != nil {
+		k.cancelTasksOnShutdown()
+	}
 	ctx.Value(gracefulShutdownKey).(wgInterface).Wait()
 	return err
 }
@@ -204,95 +251,167 @@ func (k *Keptn) SendFinishedEvent(event KeptnEvent, result interface{}) error {
 	return k.send(*finishedEvent)
 }
 
+func (k *Keptn) SendStatusChangedEvent(event KeptnEvent, message string, progress int) error {
+	inputCE := cloudevents.Event{}
+	err := keptnv2.Decode(event, &inputCE)
+	if err != nil {
+		return err
+	}
+	statusChangedEvent, err := k.createStatusChangedEventForTriggeredEvent(inputCE, message, progress)
+	if err != nil {
+		return err
+	}
+	return k.send(*statusChangedEvent)
+}
+
 func (k *Keptn) Logger() Logger {
 	return k.logger
 }
 
 func (k *Keptn) gotEvent(ctx context.Context, event cloudevents.Event) {
+	if event.Type() == SequenceAbortedEventType {
+		if keptnContext, err := event.Context.GetExtension(KeptnContextCEExtension); err == nil {
+			k.abortTasks(fmt.Sprint(keptnContext))
+		}
+		return
+	}
 	if !keptnv2.IsTaskEventType(event.Type()) {
 		k.logger.Errorf("event with event type %s is no valid keptn task event type", event.Type())
 		return
 	}
+	handler, ok := k.taskRegistry.Contains(event.Type())
+	if !ok {
+		return
+	}
+	keptnEvent := &KeptnEvent{}
+	if err := keptnv2.Decode(&event, keptnEvent); err != nil {
+		errorLogEvent, err := k.createErrorLogEventForTriggeredEvent(event, nil, &Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed})
+		if err != nil {
+			k.logger.Errorf("unable to create '.error.log' event from '.triggered' event: %v", err)
+			return
+		}
+		// no started event sent yet, so it only makes sense to Send an error log event at this point
+		if err := k.send(*errorLogEvent); err != nil {
+			k.logger.Errorf("unable to send '.finished' event: %v", err)
+			return
+		}
+	}
+
+	// the filters are applied before the task is submitted, so ignored events do not take up the capacity of the task pool
+	if !k.matchesFilters(handler, *keptnEvent) {
+		k.logger.Infof("Will not handle incoming %s event", event.Type())
+		return
+	}
+
 	ctx.Value(gracefulShutdownKey).(wgInterface).Add(1)
-	k.runEventTaskAction(func() {
-		{
+	k.runEventTaskAction(queuedTask{
+		eventType:    event.Type(),
+		keptnContext: keptnEvent.Shkeptncontext,
+		fn: func() {
+			defer ctx.Value(gracefulShutdownKey).(wgInterface).Done()
+			k.executeTask(event, handler, *keptnEvent)
+		},
+		drop: func() {
 			defer ctx.Value(gracefulShutdownKey).(wgInterface).Done()
-			if handler, ok := k.taskRegistry.Contains(event.Type()); ok {
-				keptnEvent := &KeptnEvent{}
-				if err := keptnv2.Decode(&event, keptnEvent); err != nil {
-					errorLogEvent, err := k.createErrorLogEventForTriggeredEvent(event, nil, &Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed})
-					if err != nil {
-						k.logger.Errorf("unable to create '.error.log' event from '.triggered' event: %v", err)
-						return
-					}
-					// no started event sent yet, so it only makes sense to Send an error log event at this point
-					if err := k.send(*errorLogEvent); err != nil {
-						k.logger.Errorf("unable to send '.finished' event: %v", err)
-						return
-					}
-				}
-
-				// execute the filtering functions of the task handler to determine whether the incoming event should be handled
-				// only if all functions return true, the event will be handled
-				for _, filterFn := range handler.EventFilters {
-					if !filterFn(k, *keptnEvent) {
-						k.logger.Infof("Will not handle incoming %s event", event.Type())
-						return
-					}
-				}
-
-				// only respond with .started event if the incoming event is a task.triggered event
-				if keptnv2.IsTaskEventType(event.Type()) && keptnv2.IsTriggeredEventType(event.Type()) && k.automaticEventResponse {
-					startedEvent, err := k.createStartedEventForTriggeredEvent(event)
-					if err != nil {
-						k.logger.Errorf("unable to create '.started' event from '.triggered' event: %v", err)
-						return
-					}
-					if err := k.send(*startedEvent); err != nil {
-						k.logger.Errorf("unable to send '.started' event: %v", err)
-						return
-					}
-				}
-
-				result, err := handler.TaskHandler.Execute(k, *keptnEvent)
-				if err != nil {
-					k.logger.Errorf("error during task execution %v", err.Err)
-					if k.automaticEventResponse {
-						errorEvent, err := k.createErrorEvent(event, result, err)
-						if err != nil {
-							k.logger.Errorf("unable to create '.error' event: %v", err)
-							return
-						}
-						if err := k.send(*errorEvent); err != nil {
-							k.logger.Errorf("unable to send '.error' event: %v", err)
-							return
-						}
-					}
-					return
-				}
-				if result == nil {
-					k.logger.Infof("no finished data set by task executor for event %s. Skipping sending finished event", event.Type())
-				} else if keptnv2.IsTaskEventType(event.Type()) && keptnv2.IsTriggeredEventType(event.Type()) && k.automaticEventResponse {
-					finishedEvent, err := k.createFinishedEventForReceivedEvent(event, result)
-					if err != nil {
-						k.logger.Errorf("unable to create '.finished' event: %v", err)
-						return
-					}
-					if err := k.send(*finishedEvent); err != nil {
-						k.logger.Errorf("unable to send '.finished' event: %v", err)
-						return
-					}
-				}
+			k.logger.Infof("Dropping queued %s event of aborted sequence with context %s", event.Type(), keptnEvent.Shkeptncontext)
+		},
+	}, func() {
+		defer ctx.Value(gracefulShutdownKey).(wgInterface).Done()
+		k.rejectTask(event)
+	})
+}
+
+func (k *Keptn) executeTask(event cloudevents.Event, handler *TaskEntry, keptnEvent KeptnEvent) {
+	// only respond with .started event if the incoming event is a task.triggered event
+	if keptnv2.IsTaskEventType(event.Type()) && keptnv2.IsTriggeredEventType(event.Type()) && k.automaticEventResponse {
+		startedEvent, err := k.createStartedEventForTriggeredEvent(event)
+		if err != nil {
+			k.logger.Errorf("unable to create '.started' event from '.triggered' event: %v", err)
+			return
+		}
+		if err := k.send(*startedEvent); err != nil {
+			k.logger.Errorf("unable to send '.started' event: %v", err)
+			return
+		}
+	}
+
+	taskCtx, finishTask := k.runningTasks.start(keptnEvent.Shkeptncontext)
+	defer finishTask()
+	result, err := handler.GetContextTaskHandler().Execute(taskCtx, k, keptnEvent)
+	if err != nil {
+		k.logger.Errorf("error during task execution %v", err.Err)
+		if k.automaticEventResponse {
+			errorEvent, err := k.createErrorEvent(event, result, err)
+			if err != nil {
+				k.logger.Errorf("unable to create '.error' event: %v", err)
+				return
+			}
+			if err := k.send(*errorEvent); err != nil {
+				k.logger.Errorf("unable to send '.error' event: %v", err)
+				return
 			}
 		}
-	})
+		return
+	}
+	if result == nil {
+		k.logger.Infof("no finished data set by task executor for event %s. Skipping sending finished event", event.Type())
+	} else if keptnv2.IsTaskEventType(event.Type()) && keptnv2.IsTriggeredEventType(event.Type()) && k.automaticEventResponse {
+		finishedEvent, err := k.createFinishedEventForReceivedEvent(event, result)
+		if err != nil {
+			k.logger.Errorf("unable to create '.finished' event: %v", err)
+			return
+		}
+		if err := k.send(*finishedEvent); err != nil {
+			k.logger.Errorf("unable to send '.finished' event: %v", err)
+			return
+		}
+	}
 }
 
-func (k *Keptn) runEventTaskAction(fn func()) {
+// matchesFilters determines whether the incoming event should be handled: it must match the subscription filters of the
+// uniform registration, if configured, and all filtering functions of the task handler must return true
+func (k *Keptn) matchesFilters(handler *TaskEntry, event KeptnEvent) bool {
+	if k.subscriptionFilter != nil && !k.subscriptionFilter.Matches(event) {
+		return false
+	}
+	for _, filterFn := range handler.EventFilters {
+		if !filterFn(k, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (k *Keptn) runEventTaskAction(task queuedTask, rejectFn func()) {
 	if k.syncProcessing {
-		fn()
+		task.fn()
+	} else if k.taskPool != nil {
+		if !k.taskPool.Submit(task) {
+			rejectFn()
+		}
 	} else {
-		go fn()
+		go task.fn()
+	}
+}
+
+// rejectTask responds with an errored .finished event to a .triggered event which has been rejected because
+// the maximum number of concurrent tasks is reached
+func (k *Keptn) rejectTask(event cloudevents.Event) {
+	k.logger.Warnf("Rejecting %s event because the maximum number of concurrent tasks is reached", event.Type())
+	if !keptnv2.IsTriggeredEventType(event.Type()) || !k.automaticEventResponse {
+		return
+	}
+	errorEvent, err := k.createErrorFinishedEventForTriggeredEvent(event, nil, &Error{
+		StatusType: keptnv2.StatusErrored,
+		ResultType: keptnv2.ResultFailed,
+		Message:    "the task has been rejected because the maximum number of concurrent tasks is reached",
+	})
+	if err != nil {
+		k.logger.Errorf("unable to create '.finished' event: %v", err)
+		return
+	}
+	if err := k.send(*errorEvent); err != nil {
+		k.logger.Errorf("unable to send '.finished' event: %v", err)
 	}
 }
 
@@ -326,6 +445,37 @@ func (k *Keptn) createStartedEventForTriggeredEvent(triggeredEvent cloudevents.E
 	return &c, nil
 }
 
+func (k *Keptn) createStatusChangedEventForTriggeredEvent(triggeredEvent cloudevents.Event, message string, progress int) (*cloudevents.Event, error) {
+	statusChangedEventType, err := keptnv2.ReplaceEventTypeKind(triggeredEvent.Type(), StatusChangedEventKind)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create '.status.changed' event: %v from %s", err, triggeredEvent.Type())
+	}
+	keptnContext, err := triggeredEvent.Context.GetExtension(KeptnContextCEExtension)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get keptn context from '.triggered' event: %v", err)
+	}
+	eventData := keptnv2.EventData{}
+	triggeredEvent.DataAs(&eventData)
+	c := cloudevents.NewEvent()
+	c.SetID(uuid.New().String())
+	c.SetType(statusChangedEventType)
+	c.SetDataContentType(cloudevents.ApplicationJSON)
+	c.SetExtension(KeptnContextCEExtension, keptnContext)
+	c.SetExtension(TriggeredIDCEExtension, triggeredEvent.ID())
+	c.SetSource(k.source)
+	c.SetData(cloudevents.ApplicationJSON, StatusChangedEventData{
+		EventData: keptnv2.EventData{
+			Project: eventData.Project,
+			Stage:   eventData.Stage,
+			Service: eventData.Service,
+			Labels:  eventData.Labels,
+			Message: message,
+		},
+		Progress: progress,
+	})
+	return &c, nil
+}
+
 func (k *Keptn) createFinishedEventForReceivedEvent(receivedEvent cloudevents.Event, eventData interface{}) (*cloudevents.Event, error) {
 	var genericEvent map[string]interface{}
 	keptnv2.Decode(eventData, &genericEvent)
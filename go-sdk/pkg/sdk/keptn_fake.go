@@ -30,6 +30,10 @@ func (f *FakeKeptn) SendFinishedEvent(event KeptnEvent, result interface{}) erro
 	return f.Keptn.SendFinishedEvent(event, result)
 }
 
+func (f *FakeKeptn) SendStatusChangedEvent(event KeptnEvent, message string, progress int) error {
+	return f.Keptn.SendStatusChangedEvent(event, message, progress)
+}
+
 func (f *FakeKeptn) Logger() Logger {
 	return f.Keptn.Logger()
 }
@@ -63,6 +67,10 @@ func (f *FakeKeptn) AddTaskHandler(eventType string, handler TaskHandler, filter
 	f.Keptn.taskRegistry.Add(eventType, TaskEntry{TaskHandler: handler, EventFilters: filters})
 }
 
+func (f *FakeKeptn) AddContextTaskHandler(eventType string, handler ContextTaskHandler, filters ...func(keptnHandle IKeptn, event KeptnEvent) bool) {
+	f.Keptn.taskRegistry.Add(eventType, TaskEntry{ContextTaskHandler: handler, EventFilters: filters})
+}
+
 func NewFakeKeptn(source string) *FakeKeptn {
 	eventReceiver := &TestReceiver{}
 	eventSender := &TestSender{}
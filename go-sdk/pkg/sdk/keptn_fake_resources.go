@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	api "github.com/keptn/go-utils/pkg/api/utils"
+)
+
+type resourceRevision struct {
+	commit  int
+	content string
+}
+
+// InMemoryResourceStore is a ResourceHandler keeping resources in memory. Every added resource creates a new commit,
+// and resources can be retrieved in the version of a commit using WithCommitID
+type InMemoryResourceStore struct {
+	sync.RWMutex
+	resources map[string][]resourceRevision
+	commits   map[string]int
+	nrCommits int
+}
+
+// NewInMemoryResourceStore creates an empty InMemoryResourceStore
+func NewInMemoryResourceStore() *InMemoryResourceStore {
+	return &InMemoryResourceStore{
+		resources: map[string][]resourceRevision{},
+		commits:   map[string]int{},
+	}
+}
+
+// AddResource stores the content of the resource in the given scope and returns the ID of the created commit
+func (s *InMemoryResourceStore) AddResource(scope api.ResourceScope, content string) string {
+	s.Lock()
+	defer s.Unlock()
+	s.nrCommits++
+	commitID := fmt.Sprintf("%040x", s.nrCommits)
+	s.commits[commitID] = s.nrCommits
+	path := resourcePath(scope)
+	s.resources[path] = append(s.resources[path], resourceRevision{commit: s.nrCommits, content: content})
+	return commitID
+}
+
+// GetResource returns the latest version of the resource, or the version at the commit given with WithCommitID
+func (s *InMemoryResourceStore) GetResource(scope api.ResourceScope, options ...api.URIOption) (*models.Resource, error) {
+	s.RLock()
+	defer s.RUnlock()
+	commit := s.nrCommits
+	if commitID := commitIDFromOptions(options); commitID != "" {
+		var ok bool
+		if commit, ok = s.commits[commitID]; !ok {
+			return nil, fmt.Errorf("commit %s not found: %w", commitID, api.ResourceNotFoundError)
+		}
+	}
+	path := resourcePath(scope)
+	revisions := s.resources[path]
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].commit <= commit {
+			uri := path
+			return &models.Resource{
+				Metadata:        &models.Version{Version: fmt.Sprintf("%040x", revisions[i].commit)},
+				ResourceContent: revisions[i].content,
+				ResourceURI:     &uri,
+			}, nil
+		}
+	}
+	return nil, api.ResourceNotFoundError
+}
+
+func resourcePath(scope api.ResourceScope) string {
+	return scope.GetProjectPath() + scope.GetStagePath() + scope.GetServicePath() + scope.GetResourcePath()
+}
+
+func commitIDFromOptions(options []api.URIOption) string {
+	uri := ""
+	for _, option := range options {
+		uri = option(uri)
+	}
+	_, query, found := strings.Cut(uri, "?")
+	if !found {
+		return ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get(commitIDQueryParam)
+}
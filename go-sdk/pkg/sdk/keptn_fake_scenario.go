@@ -0,0 +1,218 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// UpdateGoldenFilesEnvVar can be set to "true" to let ScenarioResult.AssertGoldenFile write the golden files instead of comparing them
+const UpdateGoldenFilesEnvVar = "UPDATE_GOLDEN_FILES"
+
+// ScenarioOption configures a scenario executed with FakeKeptn.RunScenario
+type ScenarioOption func(*scenarioConfig)
+
+type scenarioConfig struct {
+	abortAfter time.Duration
+	timeout    time.Duration
+}
+
+// WithSequenceAbort simulates that the sequence of the triggered event is aborted after the given duration,
+// which cancels the context of context-aware task handlers
+func WithSequenceAbort(after time.Duration) ScenarioOption {
+	return func(c *scenarioConfig) {
+		c.abortAfter = after
+	}
+}
+
+// WithScenarioTimeout simulates a timeout of the task: if the handler did not finish within the given duration,
+// its context is cancelled and the scenario waits until the handler returns
+func WithScenarioTimeout(timeout time.Duration) ScenarioOption {
+	return func(c *scenarioConfig) {
+		c.timeout = timeout
+	}
+}
+
+// ScenarioResult contains the events sent while handling the triggered event of a scenario
+type ScenarioResult struct {
+	TriggeredEvent cloudevents.Event
+	Events         []cloudevents.Event
+	// TimedOut is true if the handler did not finish within the timeout set with WithScenarioTimeout
+	TimedOut bool
+}
+
+// RunScenario passes the triggered event to the registered task handlers, waits until it has been handled and
+// returns the events sent in the meantime
+func (f *FakeKeptn) RunScenario(triggered cloudevents.Event, opts ...ScenarioOption) *ScenarioResult {
+	config := scenarioConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if f.Keptn.eventReceiver.(*TestReceiver).receiverFn == nil {
+		_ = f.Start()
+	}
+	sender := f.GetEventSender()
+	sentBefore := len(sender.SentEvents)
+	keptnContext := fmt.Sprint(triggered.Extensions()[KeptnContextCEExtension])
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.NewEvent(triggered)
+	}()
+	if config.abortAfter > 0 {
+		abortTimer := time.AfterFunc(config.abortAfter, func() {
+			f.NewEvent(newSequenceAbortedEvent(keptnContext))
+		})
+		defer abortTimer.Stop()
+	}
+
+	result := &ScenarioResult{TriggeredEvent: triggered}
+	var timeout <-chan time.Time
+	if config.timeout > 0 {
+		timeout = time.After(config.timeout)
+	}
+	select {
+	case <-done:
+	case <-timeout:
+		result.TimedOut = true
+		f.Keptn.abortTasks(keptnContext)
+		<-done
+	}
+	result.Events = append([]cloudevents.Event{}, sender.SentEvents[sentBefore:]...)
+	return result
+}
+
+func newSequenceAbortedEvent(keptnContext string) cloudevents.Event {
+	c := cloudevents.NewEvent()
+	c.SetID(uuid.New().String())
+	c.SetType(SequenceAbortedEventType)
+	c.SetDataContentType(cloudevents.ApplicationJSON)
+	c.SetExtension(KeptnContextCEExtension, keptnContext)
+	c.SetSource("fake-keptn")
+	return c
+}
+
+// EventTypes returns the types of the sent events
+func (r *ScenarioResult) EventTypes() []string {
+	eventTypes := []string{}
+	for _, event := range r.Events {
+		eventTypes = append(eventTypes, event.Type())
+	}
+	return eventTypes
+}
+
+// AssertStartedThenFinished checks that a .started event was sent first and a .finished event last for the triggered
+// event, with only .status.changed events in between
+func (r *ScenarioResult) AssertStartedThenFinished() error {
+	startedEventType, err := keptnv2.ReplaceEventTypeKind(r.TriggeredEvent.Type(), "started")
+	if err != nil {
+		return err
+	}
+	statusChangedEventType, _ := keptnv2.ReplaceEventTypeKind(r.TriggeredEvent.Type(), StatusChangedEventKind)
+	finishedEventType, _ := keptnv2.ReplaceEventTypeKind(r.TriggeredEvent.Type(), "finished")
+	if len(r.Events) < 2 {
+		return fmt.Errorf("expected a %s and a %s event, got %v", startedEventType, finishedEventType, r.EventTypes())
+	}
+	for index, event := range r.Events {
+		expectedType := statusChangedEventType
+		if index == 0 {
+			expectedType = startedEventType
+		} else if index == len(r.Events)-1 {
+			expectedType = finishedEventType
+		}
+		if event.Type() != expectedType {
+			return fmt.Errorf("expected %s event at position %d, got %v", expectedType, index, r.EventTypes())
+		}
+		if triggeredID := fmt.Sprint(event.Extensions()[TriggeredIDCEExtension]); triggeredID != r.TriggeredEvent.ID() {
+			return fmt.Errorf("%s event refers to triggered event %s instead of %s", event.Type(), triggeredID, r.TriggeredEvent.ID())
+		}
+	}
+	return nil
+}
+
+// FinishedEventData returns the data of the .finished event of the scenario
+func (r *ScenarioResult) FinishedEventData() (*keptnv2.EventData, error) {
+	for _, event := range r.Events {
+		if keptnv2.IsFinishedEventType(event.Type()) {
+			eventData := &keptnv2.EventData{}
+			if err := event.DataAs(eventData); err != nil {
+				return nil, fmt.Errorf("could not decode data of %s event: %w", event.Type(), err)
+			}
+			return eventData, nil
+		}
+	}
+	return nil, fmt.Errorf("no .finished event has been sent, got %v", r.EventTypes())
+}
+
+// AssertResult checks the result of the .finished event of the scenario
+func (r *ScenarioResult) AssertResult(result keptnv2.ResultType) error {
+	eventData, err := r.FinishedEventData()
+	if err != nil {
+		return err
+	}
+	if eventData.Result != result {
+		return fmt.Errorf("expected result '%s', got '%s' with message: %s", result, eventData.Result, eventData.Message)
+	}
+	return nil
+}
+
+// AssertStatus checks the status of the .finished event of the scenario
+func (r *ScenarioResult) AssertStatus(status keptnv2.StatusType) error {
+	eventData, err := r.FinishedEventData()
+	if err != nil {
+		return err
+	}
+	if eventData.Status != status {
+		return fmt.Errorf("expected status '%s', got '%s' with message: %s", status, eventData.Status, eventData.Message)
+	}
+	return nil
+}
+
+type goldenEvent struct {
+	Type   string      `json:"type"`
+	Source string      `json:"source"`
+	Data   interface{} `json:"data"`
+}
+
+// AssertGoldenFile compares the type, source and data of the sent events with the content of the given file.
+// Attributes differing in every run, like IDs and timestamps, are not compared.
+// If the environment variable UPDATE_GOLDEN_FILES is set to "true", the file is written instead
+func (r *ScenarioResult) AssertGoldenFile(filename string) error {
+	events := []goldenEvent{}
+	for _, event := range r.Events {
+		var data interface{}
+		if err := event.DataAs(&data); err != nil {
+			return fmt.Errorf("could not decode data of %s event: %w", event.Type(), err)
+		}
+		events = append(events, goldenEvent{Type: event.Type(), Source: event.Source(), Data: data})
+	}
+	actual, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return err
+	}
+	actual = append(actual, '\n')
+
+	if os.Getenv(UpdateGoldenFilesEnvVar) == "true" {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filename, actual, 0644)
+	}
+	expected, err := ioutil.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return fmt.Errorf("could not read golden file (set %s=true to create it): %w", UpdateGoldenFilesEnvVar, err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(expected), bytes.TrimSpace(actual)) {
+		return fmt.Errorf("sent events do not match golden file %s\nexpected:\n%s\nactual:\n%s", filename, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunScenario(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake")
+	fakeKeptn.AddTaskHandler("sh.keptn.event.faketask.triggered", &TaskHandlerMock{
+		ExecuteFunc: func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			_ = keptnHandle.SendStatusChangedEvent(event, "half way there", 50)
+			return FakeTaskData{}, nil
+		},
+	})
+
+	result := fakeKeptn.RunScenario(newTestTaskTriggeredEvent())
+
+	require.False(t, result.TimedOut)
+	require.Nil(t, result.AssertStartedThenFinished())
+	require.Nil(t, result.AssertResult(keptnv2.ResultPass))
+	require.Nil(t, result.AssertStatus(keptnv2.StatusSucceeded))
+	require.NotNil(t, result.AssertResult(keptnv2.ResultFailed))
+	require.Nil(t, result.AssertGoldenFile("testdata/scenario_status_changed.golden.json"))
+}
+
+func Test_RunScenario_OnlyEventsOfScenarioAreReturned(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake")
+	fakeKeptn.AddTaskHandler("sh.keptn.event.faketask.triggered", &TaskHandlerMock{
+		ExecuteFunc: func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			return FakeTaskData{}, nil
+		},
+	})
+
+	fakeKeptn.RunScenario(newTestTaskTriggeredEvent())
+	result := fakeKeptn.RunScenario(newTestTaskTriggeredEvent())
+
+	require.Len(t, result.Events, 2)
+	require.Nil(t, result.AssertStartedThenFinished())
+}
+
+func Test_RunScenario_HandlerFails(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake")
+	fakeKeptn.AddTaskHandler("sh.keptn.event.faketask.triggered", &TaskHandlerMock{
+		ExecuteFunc: func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			return nil, &Error{StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: "something went wrong"}
+		},
+	})
+
+	result := fakeKeptn.RunScenario(newTestTaskTriggeredEvent())
+
+	require.Nil(t, result.AssertStartedThenFinished())
+	require.Nil(t, result.AssertResult(keptnv2.ResultFailed))
+	require.Nil(t, result.AssertStatus(keptnv2.StatusErrored))
+	require.Nil(t, result.AssertGoldenFile("testdata/scenario_error.golden.json"))
+}
+
+func Test_RunScenario_WithSequenceAbort(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake")
+	fakeKeptn.AddContextTaskHandler("sh.keptn.event.faketask.triggered", &ContextTaskHandlerMock{
+		ExecuteFunc: func(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			<-ctx.Done()
+			return nil, &Error{StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: "aborted"}
+		},
+	})
+
+	result := fakeKeptn.RunScenario(newTestTaskTriggeredEvent(), WithSequenceAbort(50*time.Millisecond))
+
+	require.False(t, result.TimedOut)
+	require.Nil(t, result.AssertResult(keptnv2.ResultFailed))
+}
+
+func Test_RunScenario_WithScenarioTimeout(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake")
+	fakeKeptn.AddContextTaskHandler("sh.keptn.event.faketask.triggered", &ContextTaskHandlerMock{
+		ExecuteFunc: func(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			<-ctx.Done()
+			return nil, &Error{StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: "timed out"}
+		},
+	})
+
+	result := fakeKeptn.RunScenario(newTestTaskTriggeredEvent(), WithScenarioTimeout(50*time.Millisecond))
+
+	require.True(t, result.TimedOut)
+	require.Nil(t, result.AssertStatus(keptnv2.StatusErrored))
+}
+
+func Test_InMemoryResourceStore(t *testing.T) {
+	store := NewInMemoryResourceStore()
+	scope := *api.NewResourceScope().Project("my-project").Stage("dev").Service("my-service").Resource("config.yaml")
+
+	firstCommit := store.AddResource(scope, "version: 1")
+	secondCommit := store.AddResource(scope, "version: 2")
+	otherScope := *api.NewResourceScope().Project("my-project").Stage("dev").Resource("other.yaml")
+	thirdCommit := store.AddResource(otherScope, "other")
+
+	resource, err := store.GetResource(scope)
+	require.Nil(t, err)
+	require.Equal(t, "version: 2", resource.ResourceContent)
+	require.Equal(t, secondCommit, resource.Metadata.Version)
+
+	resource, err = store.GetResource(scope, WithCommitID(firstCommit))
+	require.Nil(t, err)
+	require.Equal(t, "version: 1", resource.ResourceContent)
+
+	resource, err = store.GetResource(scope, WithCommitID(thirdCommit))
+	require.Nil(t, err)
+	require.Equal(t, "version: 2", resource.ResourceContent)
+
+	_, err = store.GetResource(otherScope, WithCommitID(secondCommit))
+	require.ErrorIs(t, err, api.ResourceNotFoundError)
+
+	_, err = store.GetResource(scope, WithCommitID("unknown"))
+	require.ErrorIs(t, err, api.ResourceNotFoundError)
+}
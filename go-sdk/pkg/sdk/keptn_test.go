@@ -167,3 +167,40 @@ func newTestTaskBadTriggeredEvent() cloudevents.Event {
 
 type FakeTaskData struct {
 }
+
+func Test_SendStatusChangedEvent(t *testing.T) {
+	fakeKeptn := NewFakeKeptn("fake-service")
+	fakeKeptn.AddTaskHandler("sh.keptn.event.faketask.triggered", &TaskHandlerMock{
+		ExecuteFunc: func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+			if err := keptnHandle.SendStatusChangedEvent(event, "halfway there", 50); err != nil {
+				return nil, &Error{Err: err}
+			}
+			return FakeTaskData{}, nil
+		},
+	})
+	fakeKeptn.Start()
+
+	triggeredEvent := newTestTaskTriggeredEvent()
+	triggeredEvent.SetData(cloudevents.ApplicationJSON, map[string]interface{}{"project": "my-project", "stage": "my-stage", "service": "my-service"})
+	fakeKeptn.NewEvent(triggeredEvent)
+
+	sentEvents := fakeKeptn.GetEventSender().SentEvents
+	require.Len(t, sentEvents, 3)
+	statusChangedEvent := sentEvents[1]
+	require.Equal(t, "sh.keptn.event.faketask.status.changed", statusChangedEvent.Type())
+	require.Equal(t, triggeredEvent.ID(), statusChangedEvent.Extensions()[TriggeredIDCEExtension])
+	require.Equal(t, "keptncontext", statusChangedEvent.Extensions()[KeptnContextCEExtension])
+
+	eventData := StatusChangedEventData{}
+	require.Nil(t, statusChangedEvent.DataAs(&eventData))
+	require.Equal(t, "my-project", eventData.Project)
+	require.Equal(t, "my-stage", eventData.Stage)
+	require.Equal(t, "halfway there", eventData.Message)
+	require.Equal(t, 50, eventData.Progress)
+}
+
+func Test_WithCommitID(t *testing.T) {
+	// the resource-service expects the commit in the gitCommitID query parameter
+	require.Equal(t, "http://resource-service/v1/project/my-project/resource/shipyard.yaml?gitCommitID=my-commit",
+		WithCommitID("my-commit")("http://resource-service/v1/project/my-project/resource/shipyard.yaml"))
+}
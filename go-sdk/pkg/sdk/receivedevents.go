@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"sync"
+	"time"
+)
+
+// receivedEventsTTL is the duration an event received from the control plane is remembered
+const receivedEventsTTL = 10 * time.Second
+
+// receivedEventsCache remembers for a limited time which events have been received from the control plane.
+// The control plane passes an event once per matching subscription, but it must only be processed once
+type receivedEventsCache struct {
+	sync.Mutex
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+func newReceivedEventsCache() *receivedEventsCache {
+	return &receivedEventsCache{entries: map[string]time.Time{}, now: time.Now}
+}
+
+// add remembers the event and returns false if it has already been added before
+func (c *receivedEventsCache) add(eventID string) bool {
+	c.Lock()
+	defer c.Unlock()
+	c.removeExpired()
+	if _, ok := c.entries[eventID]; ok {
+		return false
+	}
+	c.entries[eventID] = c.now()
+	return true
+}
+
+func (c *receivedEventsCache) removeExpired() {
+	for eventID, added := range c.entries {
+		if c.now().Sub(added) > receivedEventsTTL {
+			delete(c.entries, eventID)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/keptn/go-utils/pkg/api/models"
+	api "github.com/keptn/go-utils/pkg/api/utils"
+	"github.com/keptn/keptn/cp-connector/pkg/controlplane"
+)
+
+// DefaultSubscriptionRefreshInterval is the default interval for fetching the subscriptions of the uniform registration
+const DefaultSubscriptionRefreshInterval = 10 * time.Second
+
+// SubscriptionFilterOption can be used to configure the subscription filters set with WithUniformSubscriptionFilters
+type SubscriptionFilterOption func(*subscriptionFilter)
+
+// WithSubscriptionRefreshInterval sets the interval for fetching the subscriptions of the uniform registration
+func WithSubscriptionRefreshInterval(interval time.Duration) SubscriptionFilterOption {
+	return func(f *subscriptionFilter) {
+		f.refreshInterval = interval
+	}
+}
+
+// WithIntegrationID sets the ID of the uniform registration to fetch the subscriptions from.
+// Per default the ID is derived from the K8S_DEPLOYMENT_NAME, K8S_NAMESPACE and K8S_NODE_NAME env variables,
+// in the same way as the distributor registers the integration
+func WithIntegrationID(integrationID string) SubscriptionFilterOption {
+	return func(f *subscriptionFilter) {
+		f.integrationID = integrationID
+	}
+}
+
+// WithUniformSubscriptionFilters configures keptn to fetch the event subscriptions of its uniform registration from the
+// control plane and to only handle events matching the project, stage and service filters of these subscriptions.
+// The subscriptions are refreshed periodically, so that changes made in the bridge take effect without a restart
+func WithUniformSubscriptionFilters(uniformAPI api.UniformV1Interface, opts ...SubscriptionFilterOption) KeptnOption {
+	return func(k *Keptn) {
+		k.subscriptionFilter = &subscriptionFilter{
+			uniformAPI:      uniformAPI,
+			refreshInterval: DefaultSubscriptionRefreshInterval,
+		}
+		for _, opt := range opts {
+			opt(k.subscriptionFilter)
+		}
+	}
+}
+
+// subscriptionFilter keeps the latest event subscriptions of the uniform registration of the integration
+type subscriptionFilter struct {
+	sync.RWMutex
+	uniformAPI      api.UniformV1Interface
+	integrationID   string
+	refreshInterval time.Duration
+	subscriptions   []models.EventSubscription
+	logger          Logger
+}
+
+// start fetches the subscriptions and keeps refreshing them until the context is done
+func (f *subscriptionFilter) start(ctx context.Context, logger Logger) {
+	f.logger = logger
+	if f.integrationID == "" {
+		integrationID, err := integrationIDFromEnv()
+		if err != nil {
+			f.logger.Errorf("Unable to determine integration ID, subscription filters will not be applied: %v", err)
+			return
+		}
+		f.integrationID = integrationID
+	}
+	f.refresh()
+	go func() {
+		ticker := time.NewTicker(f.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.refresh()
+			}
+		}
+	}()
+}
+
+func (f *subscriptionFilter) refresh() {
+	registrations, err := f.uniformAPI.GetRegistrations()
+	if err != nil {
+		f.logger.Errorf("Unable to fetch subscriptions of integration %s: %v", f.integrationID, err)
+		return
+	}
+	for _, registration := range registrations {
+		if registration != nil && registration.ID == f.integrationID {
+			f.Lock()
+			f.subscriptions = registration.Subscriptions
+			f.Unlock()
+			return
+		}
+	}
+	f.logger.Warnf("Integration %s is not registered at the control plane", f.integrationID)
+}
+
+// Matches checks whether the event matches the filters of at least one subscription to its event type.
+// Events are not filtered as long as no subscriptions have been fetched, or if there is no subscription to their type
+func (f *subscriptionFilter) Matches(event KeptnEvent) bool {
+	f.RLock()
+	defer f.RUnlock()
+	subscribed := false
+	for _, subscription := range f.subscriptions {
+		if !controlplane.SubjectMatches(subscription.Event, *event.Type) {
+			continue
+		}
+		subscribed = true
+		if controlplane.NewEventMatcherFromSubscription(subscription).Matches(models.KeptnContextExtendedCE(event)) {
+			return true
+		}
+	}
+	return !subscribed
+}
+
+func integrationIDFromEnv() (string, error) {
+	var env integrationEnvConfig
+	if err := envconfig.Process("", &env); err != nil {
+		return "", fmt.Errorf("failed to process env var: %w", err)
+	}
+	if env.K8sNodeName == "" {
+		env.K8sNodeName = "keptn-node"
+	}
+	return models.IntegrationID{
+		Name:      env.K8sDeploymentName,
+		Namespace: env.K8sNamespace,
+		NodeName:  env.K8sNodeName,
+	}.Hash()
+}
@@ -0,0 +1,159 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/require"
+)
+
+type UniformInterfaceMock struct {
+	GetRegistrationsFn func() ([]*models.Integration, error)
+}
+
+func (m *UniformInterfaceMock) Ping(integrationID string) (*models.Integration, error) {
+	panic("implement me")
+}
+
+func (m *UniformInterfaceMock) RegisterIntegration(integration models.Integration) (string, error) {
+	panic("implement me")
+}
+
+func (m *UniformInterfaceMock) CreateSubscription(integrationID string, subscription models.EventSubscription) (string, error) {
+	panic("implement me")
+}
+
+func (m *UniformInterfaceMock) UnregisterIntegration(integrationID string) error {
+	panic("implement me")
+}
+
+func (m *UniformInterfaceMock) GetRegistrations() ([]*models.Integration, error) {
+	if m.GetRegistrationsFn != nil {
+		return m.GetRegistrationsFn()
+	}
+	panic("GetRegistrations() not implemented")
+}
+
+func newTestTaskTriggeredEventForProject(project string) cloudevents.Event {
+	c := newTestTaskTriggeredEvent()
+	c.SetID(uuid.New().String())
+	c.SetData(cloudevents.ApplicationJSON, keptnv2.EventData{Project: project, Stage: "dev", Service: "my-service"})
+	return c
+}
+
+func toKeptnEvent(t *testing.T, ce cloudevents.Event) KeptnEvent {
+	keptnEvent := KeptnEvent{}
+	require.Nil(t, keptnv2.Decode(&ce, &keptnEvent))
+	return keptnEvent
+}
+
+func Test_SubscriptionFilterMatches(t *testing.T) {
+	tests := []struct {
+		name          string
+		subscriptions []models.EventSubscription
+		project       string
+		want          bool
+	}{
+		{
+			name:    "no subscriptions fetched",
+			project: "my-project",
+			want:    true,
+		},
+		{
+			name:          "no subscription to event type",
+			subscriptions: []models.EventSubscription{{Event: "sh.keptn.event.deployment.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"other-project"}}}},
+			project:       "my-project",
+			want:          true,
+		},
+		{
+			name:          "filter matches",
+			subscriptions: []models.EventSubscription{{Event: "sh.keptn.event.faketask.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"my-project"}, Stages: []string{"dev"}}}},
+			project:       "my-project",
+			want:          true,
+		},
+		{
+			name:          "filter does not match",
+			subscriptions: []models.EventSubscription{{Event: "sh.keptn.event.faketask.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"other-project"}}}},
+			project:       "my-project",
+			want:          false,
+		},
+		{
+			name: "one of multiple subscriptions matches",
+			subscriptions: []models.EventSubscription{
+				{Event: "sh.keptn.event.faketask.triggered", Filter: models.EventSubscriptionFilter{Projects: []string{"other-project"}}},
+				{Event: "sh.keptn.event.>", Filter: models.EventSubscriptionFilter{Projects: []string{"my-project"}}},
+			},
+			project: "my-project",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &subscriptionFilter{subscriptions: tt.subscriptions}
+			require.Equal(t, tt.want, filter.Matches(toKeptnEvent(t, newTestTaskTriggeredEventForProject(tt.project))))
+		})
+	}
+}
+
+func Test_WhenSubscriptionsAreUpdated_FiltersAreApplied(t *testing.T) {
+	var mtx sync.Mutex
+	subscribedProjects := []string{"other-project"}
+	uniformAPI := &UniformInterfaceMock{GetRegistrationsFn: func() ([]*models.Integration, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return []*models.Integration{
+			{ID: "other-integration"},
+			{ID: "my-integration", Subscriptions: []models.EventSubscription{{Event: "sh.keptn.event.faketask.triggered", Filter: models.EventSubscriptionFilter{Projects: subscribedProjects}}}},
+		}, nil
+	}}
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		return FakeTaskData{}, nil
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+
+	keptn := NewKeptn("fake-service",
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithUniformSubscriptionFilters(uniformAPI, WithIntegrationID("my-integration"), WithSubscriptionRefreshInterval(10*time.Millisecond)),
+	)
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+	keptn.syncProcessing = true
+	keptn.Start()
+
+	eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEventForProject("my-project"))
+	require.Empty(t, taskHandler.ExecuteCalls())
+
+	mtx.Lock()
+	subscribedProjects = []string{"other-project", "my-project"}
+	mtx.Unlock()
+	require.Eventually(t, func() bool {
+		eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEventForProject("my-project"))
+		return len(taskHandler.ExecuteCalls()) > 0
+	}, time.Second, 20*time.Millisecond)
+}
+
+func Test_WhenSubscriptionsCannotBeFetched_EventsAreNotFiltered(t *testing.T) {
+	uniformAPI := &UniformInterfaceMock{GetRegistrationsFn: func() ([]*models.Integration, error) {
+		return nil, errors.New("control plane not available")
+	}}
+	filter := &subscriptionFilter{uniformAPI: uniformAPI, integrationID: "my-integration", refreshInterval: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filter.start(ctx, NewDefaultLogger())
+
+	require.True(t, filter.Matches(toKeptnEvent(t, newTestTaskTriggeredEventForProject("my-project"))))
+}
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SequenceAbortedEventType is the type of the event which cancels all running tasks of a sequence.
+// It is sent by the shipyard-controller when a sequence is aborted
+const SequenceAbortedEventType = "sh.keptn.event.sequence.aborted"
+
+// DefaultShutdownTimeout is the time running tasks have to finish after a shutdown has been initiated,
+// before their context is cancelled
+const DefaultShutdownTimeout = 20 * time.Second
+
+//go:generate moq -out ./contexttaskhandler_mock.go . ContextTaskHandler
+type ContextTaskHandler interface {
+	// Execute is called whenever the actual business-logic of the service shall be executed.
+	// The context is cancelled if the sequence of the event is aborted, or if the service is shut down and
+	// the shutdown timeout expired. Long-running handlers should stop their work in this case.
+	//
+	// Note, that the contract of the method is to return the payload of the .finished event to be sent out as well as a Error Pointer
+	// or nil, if there was no error during execution.
+	Execute(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error)
+}
+
+// NewContextTaskHandler adapts a TaskHandler to a ContextTaskHandler, which ignores the context
+func NewContextTaskHandler(handler TaskHandler) ContextTaskHandler {
+	return taskHandlerAdapter{handler: handler}
+}
+
+type taskHandlerAdapter struct {
+	handler TaskHandler
+}
+
+func (a taskHandlerAdapter) Execute(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+	return a.handler.Execute(keptnHandle, event)
+}
+
+// WithContextTaskHandler registers a handler which is responsible for processing a .triggered event and
+// receives a context that is cancelled if the sequence is aborted or the service is shut down.
+// Note that the service needs to receive sh.keptn.event.sequence.aborted events for the cancellation on aborts
+func WithContextTaskHandler(eventType string, handler ContextTaskHandler, filters ...func(keptnHandle IKeptn, event KeptnEvent) bool) KeptnOption {
+	return func(k *Keptn) {
+		k.taskRegistry.Add(eventType, TaskEntry{ContextTaskHandler: handler, EventFilters: filters})
+	}
+}
+
+// WithShutdownTimeout sets the time running tasks have to finish after a shutdown has been initiated, before
+// their context is cancelled. Per default, DefaultShutdownTimeout is used
+func WithShutdownTimeout(timeout time.Duration) KeptnOption {
+	return func(k *Keptn) {
+		k.shutdownTimeout = timeout
+	}
+}
+
+// runningTasks keeps track of the cancel functions of the running tasks per keptnContext
+type runningTasks struct {
+	sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	nextID  int
+	byKeptn map[string]map[int]context.CancelFunc
+}
+
+func (r *runningTasks) init() {
+	if r.ctx == nil {
+		r.ctx, r.cancel = context.WithCancel(context.Background())
+		r.byKeptn = map[string]map[int]context.CancelFunc{}
+	}
+}
+
+// start returns the context of a new task of the given keptnContext, and a function to call once the task is finished
+func (r *runningTasks) start(keptnContext string) (context.Context, func()) {
+	r.Lock()
+	defer r.Unlock()
+	r.init()
+	ctx, cancel := context.WithCancel(r.ctx)
+	id := r.nextID
+	r.nextID++
+	if r.byKeptn[keptnContext] == nil {
+		r.byKeptn[keptnContext] = map[int]context.CancelFunc{}
+	}
+	r.byKeptn[keptnContext][id] = cancel
+	return ctx, func() {
+		r.Lock()
+		defer r.Unlock()
+		delete(r.byKeptn[keptnContext], id)
+		if len(r.byKeptn[keptnContext]) == 0 {
+			delete(r.byKeptn, keptnContext)
+		}
+		cancel()
+	}
+}
+
+// abort cancels the context of all running tasks of the given keptnContext and returns their number
+func (r *runningTasks) abort(keptnContext string) int {
+	r.Lock()
+	defer r.Unlock()
+	r.init()
+	tasks := r.byKeptn[keptnContext]
+	for _, cancel := range tasks {
+		cancel()
+	}
+	return len(tasks)
+}
+
+// cancelAll cancels the context of all running and future tasks
+func (r *runningTasks) cancelAll() {
+	r.Lock()
+	defer r.Unlock()
+	r.init()
+	r.cancel()
+}
+
+func (k *Keptn) abortTasks(keptnContext string) {
+	if k.taskPool != nil {
+		if dropped := k.taskPool.Drop(keptnContext); dropped > 0 {
+			k.logger.Infof("Dropped %d queued task(s) of aborted sequence with context %s", dropped, keptnContext)
+		}
+	}
+	if aborted := k.runningTasks.abort(keptnContext); aborted > 0 {
+		k.logger.Infof("Cancelled %d running task(s) of aborted sequence with context %s", aborted, keptnContext)
+	}
+}
+
+// cancelTasksOnShutdown cancels the context of the running tasks once the shutdown timeout expired
+func (k *Keptn) cancelTasksOnShutdown() {
+	if !k.gracefulShutdown {
+		k.runningTasks.cancelAll()
+		return
+	}
+	time.AfterFunc(k.shutdownTimeout, func() {
+		k.logger.Warnf("Shutdown timeout of %s expired, cancelling running tasks", k.shutdownTimeout)
+		k.runningTasks.cancelAll()
+	})
+}
@@ -0,0 +1,127 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSequenceAbortedEvent(keptnContext string) cloudevents.Event {
+	c := cloudevents.NewEvent()
+	c.SetID(uuid.New().String())
+	c.SetType(SequenceAbortedEventType)
+	c.SetDataContentType(cloudevents.ApplicationJSON)
+	c.SetExtension(KeptnContextCEExtension, keptnContext)
+	c.SetSource("shipyard-controller")
+	c.SetData(cloudevents.ApplicationJSON, FakeTaskData{})
+	return c
+}
+
+func Test_WhenSequenceIsAborted_TaskContextIsCancelled(t *testing.T) {
+	taskHandler := &ContextTaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		<-ctx.Done()
+		return nil, &Error{Message: "aborted", Err: ctx.Err()}
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+
+	keptn := NewKeptn("fake-service", WithContextTaskHandler("sh.keptn.event.faketask.triggered", taskHandler), WithAutomaticResponse(false))
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+	keptn.Start()
+
+	eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		return len(taskHandler.ExecuteCalls()) == 1
+	}, time.Second, 10*time.Millisecond)
+	taskCtx := taskHandler.ExecuteCalls()[0].Ctx
+
+	eventReceiver.NewEvent(context.Background(), newTestSequenceAbortedEvent("other-context"))
+	require.Nil(t, taskCtx.Err())
+
+	eventReceiver.NewEvent(context.Background(), newTestSequenceAbortedEvent("keptncontext"))
+	require.Eventually(t, func() bool {
+		return taskCtx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_WhenShutdownTimeoutExpires_TaskContextIsCancelled(t *testing.T) {
+	keptn := NewKeptn("fake-service", WithShutdownTimeout(50*time.Millisecond))
+	taskCtx, finishTask := keptn.runningTasks.start("keptncontext")
+	defer finishTask()
+
+	keptn.cancelTasksOnShutdown()
+	require.Nil(t, taskCtx.Err())
+	require.Eventually(t, func() bool {
+		return taskCtx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_RunningTasks_FinishedTasksAreRemoved(t *testing.T) {
+	tasks := runningTasks{}
+	taskCtx, finishTask := tasks.start("keptncontext")
+	finishTask()
+
+	require.NotNil(t, taskCtx.Err())
+	require.Equal(t, 0, tasks.abort("keptncontext"))
+}
+
+func Test_ContextTaskHandlerAdapter(t *testing.T) {
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		return FakeTaskData{}, nil
+	}
+
+	result, err := TaskEntry{TaskHandler: taskHandler}.GetContextTaskHandler().Execute(context.Background(), nil, KeptnEvent{})
+
+	require.Nil(t, err)
+	require.Equal(t, FakeTaskData{}, result)
+	require.Len(t, taskHandler.ExecuteCalls(), 1)
+}
+
+func Test_WhenSequenceIsAborted_QueuedTasksAreDropped(t *testing.T) {
+	taskHandler := &ContextTaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		<-ctx.Done()
+		return nil, &Error{Message: "aborted", Err: ctx.Err()}
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+
+	keptn := NewKeptn("fake-service",
+		WithContextTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithAutomaticResponse(false),
+		WithMaxConcurrentTasks(1),
+	)
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+	keptn.Start()
+
+	eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEvent())
+	eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		keptn.taskPool.Lock()
+		defer keptn.taskPool.Unlock()
+		return len(taskHandler.ExecuteCalls()) == 1 && len(keptn.taskPool.queue) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	eventReceiver.NewEvent(context.Background(), newTestSequenceAbortedEvent("keptncontext"))
+	require.Eventually(t, func() bool {
+		keptn.taskPool.Lock()
+		defer keptn.taskPool.Unlock()
+		return keptn.taskPool.running == 0 && len(keptn.taskPool.queue) == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, taskHandler.ExecuteCalls(), 1)
+}
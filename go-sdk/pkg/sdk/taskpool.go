@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"sync"
+)
+
+// unlimitedTaskQueue is the queue size used if no queue size is configured
+const unlimitedTaskQueue = -1
+
+// TaskMetrics describes the current task concurrency after a task has been started, queued, rejected or finished
+type TaskMetrics struct {
+	// EventType is the type of the event whose task changed the metrics
+	EventType string
+	// Running is the number of currently executed tasks
+	Running int
+	// RunningOfType is the number of currently executed tasks of the given EventType
+	RunningOfType int
+	// Queued is the number of tasks waiting for execution
+	Queued int
+	// Rejected is true if the task of the given EventType has been rejected because the queue was full
+	Rejected bool
+}
+
+// TaskMetricsHook is called whenever the task concurrency changes
+type TaskMetricsHook func(metrics TaskMetrics)
+
+// WithMaxConcurrentTasks limits the number of tasks which are executed concurrently.
+// Additional tasks are queued until a running task is finished.
+// Per default, the number of concurrently executed tasks is unlimited
+func WithMaxConcurrentTasks(maxTasks int) KeptnOption {
+	return func(k *Keptn) {
+		k.getTaskPool().maxTasks = maxTasks
+	}
+}
+
+// WithMaxConcurrentTasksPerType limits the number of tasks for the given event type which are executed concurrently.
+// The limit applies in addition to the one set with WithMaxConcurrentTasks
+func WithMaxConcurrentTasksPerType(eventType string, maxTasks int) KeptnOption {
+	return func(k *Keptn) {
+		k.getTaskPool().maxTasksPerType[eventType] = maxTasks
+	}
+}
+
+// WithTaskQueueSize limits the number of tasks waiting for execution if the concurrency limit is reached.
+// If the queue is full, the task is rejected and a .finished event with status 'errored' is sent for it.
+// A size of 0 rejects tasks right away. Per default, the queue is unlimited
+func WithTaskQueueSize(size int) KeptnOption {
+	return func(k *Keptn) {
+		k.getTaskPool().queueSize = size
+	}
+}
+
+// WithTaskMetricsHook registers a function which is called whenever a task is started, queued, rejected or finished.
+// Without any concurrency limits, the tasks are still executed right away
+func WithTaskMetricsHook(hook TaskMetricsHook) KeptnOption {
+	return func(k *Keptn) {
+		// the metrics are reported by the task pool, which is unbounded unless a limit is configured
+		k.getTaskPool()
+		k.taskMetricsHook = hook
+	}
+}
+
+func (k *Keptn) getTaskPool() *taskPool {
+	if k.taskPool == nil {
+		k.taskPool = newTaskPool()
+	}
+	return k.taskPool
+}
+
+func (k *Keptn) reportTaskMetrics(metrics TaskMetrics) {
+	k.logger.Debugf("Task concurrency for %s: %d running (%d of this type), %d queued, rejected: %t", metrics.EventType, metrics.Running, metrics.RunningOfType, metrics.Queued, metrics.Rejected)
+	if k.taskMetricsHook != nil {
+		k.taskMetricsHook(metrics)
+	}
+}
+
+type queuedTask struct {
+	eventType    string
+	keptnContext string
+	fn           func()
+	// drop is called instead of fn if the task is removed from the queue because its sequence has been aborted
+	drop func()
+}
+
+// taskPool executes tasks in goroutines while limiting the number of tasks running concurrently
+type taskPool struct {
+	sync.Mutex
+	maxTasks        int
+	maxTasksPerType map[string]int
+	queueSize       int
+	running         int
+	runningPerType  map[string]int
+	queue           []queuedTask
+	onUpdate        TaskMetricsHook
+}
+
+func newTaskPool() *taskPool {
+	return &taskPool{
+		maxTasksPerType: map[string]int{},
+		queueSize:       unlimitedTaskQueue,
+		runningPerType:  map[string]int{},
+		queue:           []queuedTask{},
+	}
+}
+
+// Submit executes the task right away if the concurrency limits allow it, or queues it otherwise.
+// It returns false if the task has been rejected because the queue is full
+func (p *taskPool) Submit(task queuedTask) bool {
+	p.Lock()
+	if p.canRun(task.eventType) {
+		p.start(task)
+		p.Unlock()
+		return true
+	}
+	if p.queueSize != unlimitedTaskQueue && len(p.queue) >= p.queueSize {
+		metrics := p.metrics(task.eventType)
+		metrics.Rejected = true
+		p.Unlock()
+		p.report(metrics)
+		return false
+	}
+	p.queue = append(p.queue, task)
+	metrics := p.metrics(task.eventType)
+	p.Unlock()
+	p.report(metrics)
+	return true
+}
+
+// Drop removes the queued tasks of the given keptnContext and returns their number
+func (p *taskPool) Drop(keptnContext string) int {
+	p.Lock()
+	dropped := []queuedTask{}
+	remaining := []queuedTask{}
+	for _, task := range p.queue {
+		if task.keptnContext == keptnContext {
+			dropped = append(dropped, task)
+		} else {
+			remaining = append(remaining, task)
+		}
+	}
+	p.queue = remaining
+	p.Unlock()
+	for _, task := range dropped {
+		if task.drop != nil {
+			task.drop()
+		}
+		p.Lock()
+		metrics := p.metrics(task.eventType)
+		p.Unlock()
+		p.report(metrics)
+	}
+	return len(dropped)
+}
+
+func (p *taskPool) canRun(eventType string) bool {
+	if p.maxTasks > 0 && p.running >= p.maxTasks {
+		return false
+	}
+	if maxOfType, ok := p.maxTasksPerType[eventType]; ok && maxOfType > 0 && p.runningPerType[eventType] >= maxOfType {
+		return false
+	}
+	return true
+}
+
+// start executes the task in a new goroutine. It must be called while holding the lock
+func (p *taskPool) start(task queuedTask) {
+	p.running++
+	p.runningPerType[task.eventType]++
+	metrics := p.metrics(task.eventType)
+	go func() {
+		p.report(metrics)
+		task.fn()
+		p.finish(task.eventType)
+	}()
+}
+
+func (p *taskPool) finish(eventType string) {
+	p.Lock()
+	p.running--
+	p.runningPerType[eventType]--
+	metrics := p.metrics(eventType)
+	// start the first queued tasks which are allowed to run now
+	for i := 0; i < len(p.queue); {
+		if !p.canRun(p.queue[i].eventType) {
+			i++
+			continue
+		}
+		task := p.queue[i]
+		p.queue = append(p.queue[:i], p.queue[i+1:]...)
+		p.start(task)
+	}
+	p.Unlock()
+	p.report(metrics)
+}
+
+func (p *taskPool) metrics(eventType string) TaskMetrics {
+	return TaskMetrics{
+		EventType:     eventType,
+		Running:       p.running,
+		RunningOfType: p.runningPerType[eventType],
+		Queued:        len(p.queue),
+	}
+}
+
+func (p *taskPool) report(metrics TaskMetrics) {
+	if p.onUpdate != nil {
+		p.onUpdate(metrics)
+	}
+}
@@ -0,0 +1,259 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/require"
+)
+
+type testMetricsCollector struct {
+	sync.Mutex
+	metrics []TaskMetrics
+}
+
+func (c *testMetricsCollector) hook(metrics TaskMetrics) {
+	c.Lock()
+	defer c.Unlock()
+	c.metrics = append(c.metrics, metrics)
+}
+
+func (c *testMetricsCollector) maxRunning() int {
+	c.Lock()
+	defer c.Unlock()
+	max := 0
+	for _, m := range c.metrics {
+		if m.Running > max {
+			max = m.Running
+		}
+	}
+	return max
+}
+
+func Test_TaskPool_LimitsConcurrentTasks(t *testing.T) {
+	collector := &testMetricsCollector{}
+	pool := newTaskPool()
+	pool.maxTasks = 2
+	pool.onUpdate = collector.hook
+
+	release := make(chan struct{})
+	done := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		done.Add(1)
+		require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() {
+			defer done.Done()
+			<-release
+		}}))
+	}
+
+	require.Eventually(t, func() bool {
+		pool.Lock()
+		defer pool.Unlock()
+		return pool.running == 2 && len(pool.queue) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	done.Wait()
+	require.Equal(t, 2, collector.maxRunning())
+}
+
+func Test_TaskPool_LimitsConcurrentTasksPerType(t *testing.T) {
+	pool := newTaskPool()
+	pool.maxTasksPerType["sh.keptn.event.test.triggered"] = 1
+
+	release := make(chan struct{})
+	otherTaskDone := make(chan struct{})
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() { <-release }}))
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() { <-release }}))
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.deployment.triggered", fn: func() { close(otherTaskDone) }}))
+
+	// the task of another type is not blocked by the queued task
+	<-otherTaskDone
+	pool.Lock()
+	require.Equal(t, 1, pool.runningPerType["sh.keptn.event.test.triggered"])
+	require.Len(t, pool.queue, 1)
+	pool.Unlock()
+	close(release)
+}
+
+func Test_TaskPool_RejectsTasksIfQueueIsFull(t *testing.T) {
+	collector := &testMetricsCollector{}
+	pool := newTaskPool()
+	pool.maxTasks = 1
+	pool.queueSize = 1
+	pool.onUpdate = collector.hook
+
+	release := make(chan struct{})
+	defer close(release)
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() { <-release }}))
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() { <-release }}))
+	require.False(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", fn: func() { <-release }}))
+
+	collector.Lock()
+	defer collector.Unlock()
+	require.True(t, collector.metrics[len(collector.metrics)-1].Rejected)
+}
+
+func Test_WhenMaxConcurrentTasksIsReached_ErroredFinishedEventIsSent(t *testing.T) {
+	release := make(chan struct{})
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		<-release
+		return FakeTaskData{}, nil
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+	collector := &testMetricsCollector{}
+
+	keptn := NewKeptn("fake-service",
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithMaxConcurrentTasks(1),
+		WithTaskQueueSize(0),
+		WithTaskMetricsHook(collector.hook),
+	)
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+
+	keptn.Start()
+	ctx := context.Background()
+	eventReceiver.NewEvent(ctx, newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	eventReceiver.NewEvent(ctx, newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	rejectedEvent := eventSender.SendEventCalls()[1].EventMoqParam
+	require.Equal(t, "sh.keptn.event.faketask.finished", rejectedEvent.Type())
+	eventData := keptnv2.EventData{}
+	require.Nil(t, rejectedEvent.DataAs(&eventData))
+	require.Equal(t, keptnv2.StatusErrored, eventData.Status)
+	require.Equal(t, keptnv2.ResultFailed, eventData.Result)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, collector.maxRunning())
+}
+
+func Test_TaskMetricsHookWithoutLimits(t *testing.T) {
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		return FakeTaskData{}, nil
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+	collector := &testMetricsCollector{}
+
+	keptn := NewKeptn("fake-service",
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler),
+		WithTaskMetricsHook(collector.hook),
+	)
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+
+	keptn.Start()
+	eventReceiver.NewEvent(context.Background(), newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		collector.Lock()
+		defer collector.Unlock()
+		return len(collector.metrics) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, collector.maxRunning())
+}
+
+func Test_TaskPool_DropsQueuedTasksOfKeptnContext(t *testing.T) {
+	pool := newTaskPool()
+	pool.maxTasks = 1
+
+	release := make(chan struct{})
+	defer close(release)
+	dropped := []string{}
+	require.True(t, pool.Submit(queuedTask{eventType: "sh.keptn.event.test.triggered", keptnContext: "my-context", fn: func() { <-release }}))
+	require.True(t, pool.Submit(queuedTask{
+		eventType:    "sh.keptn.event.test.triggered",
+		keptnContext: "my-context",
+		fn:           func() { <-release },
+		drop:         func() { dropped = append(dropped, "my-context") },
+	}))
+	require.True(t, pool.Submit(queuedTask{
+		eventType:    "sh.keptn.event.test.triggered",
+		keptnContext: "other-context",
+		fn:           func() { <-release },
+		drop:         func() { dropped = append(dropped, "other-context") },
+	}))
+
+	require.Equal(t, 1, pool.Drop("my-context"))
+	require.Equal(t, []string{"my-context"}, dropped)
+	pool.Lock()
+	require.Len(t, pool.queue, 1)
+	require.Equal(t, "other-context", pool.queue[0].keptnContext)
+	pool.Unlock()
+}
+
+func Test_WhenEventIsFiltered_NoTaskIsQueuedOrRejected(t *testing.T) {
+	release := make(chan struct{})
+	taskHandler := &TaskHandlerMock{}
+	taskHandler.ExecuteFunc = func(keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+		<-release
+		return FakeTaskData{}, nil
+	}
+	eventReceiver := &TestReceiver{}
+	eventSender := &EventSenderMock{}
+	eventSender.SendEventFunc = func(eventMoqParam event.Event) error {
+		return nil
+	}
+	collector := &testMetricsCollector{}
+
+	keptn := NewKeptn("fake-service",
+		WithTaskHandler("sh.keptn.event.faketask.triggered", taskHandler, func(keptnHandle IKeptn, event KeptnEvent) bool {
+			return event.ID != "filtered-event"
+		}),
+		WithMaxConcurrentTasks(1),
+		WithTaskQueueSize(0),
+		WithTaskMetricsHook(collector.hook),
+	)
+	keptn.eventReceiver = eventReceiver
+	keptn.eventSender = eventSender
+
+	keptn.Start()
+	ctx := context.Background()
+	eventReceiver.NewEvent(ctx, newTestTaskTriggeredEvent())
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	filteredEvent := newTestTaskTriggeredEvent()
+	filteredEvent.SetID("filtered-event")
+	eventReceiver.NewEvent(ctx, filteredEvent)
+
+	collector.Lock()
+	for _, metrics := range collector.metrics {
+		require.False(t, metrics.Rejected)
+	}
+	collector.Unlock()
+	require.Len(t, eventSender.SendEventCalls(), 1)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return len(eventSender.SendEventCalls()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, taskHandler.ExecuteCalls(), 1)
+}
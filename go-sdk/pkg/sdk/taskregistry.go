@@ -11,10 +11,20 @@ type TaskRegistry struct {
 
 type TaskEntry struct {
 	TaskHandler TaskHandler
+	// ContextTaskHandler is used instead of the TaskHandler, if set
+	ContextTaskHandler ContextTaskHandler
 	// EventFilters is a list of functions that are executed before a task is handled by the TaskHandler. Only if all functions return 'true', the task will be handled
 	EventFilters []func(keptnHandle IKeptn, event KeptnEvent) bool
 }
 
+// GetContextTaskHandler returns the ContextTaskHandler of the entry, or the adapted TaskHandler if none is set
+func (e TaskEntry) GetContextTaskHandler() ContextTaskHandler {
+	if e.ContextTaskHandler != nil {
+		return e.ContextTaskHandler
+	}
+	return NewContextTaskHandler(e.TaskHandler)
+}
+
 func NewTasksMap() *TaskRegistry {
 	return &TaskRegistry{
 		Entries: make(map[string]TaskEntry),
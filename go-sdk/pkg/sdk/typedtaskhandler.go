@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// TypedTaskHandler is a task handler which receives the data of the .triggered event decoded into T,
+// and returns the data of the .finished event as R
+type TypedTaskHandler[T any, R any] interface {
+	// Execute is called with the decoded and validated data of the .triggered event.
+	// The context is cancelled if the sequence of the event is aborted, or if the service is shut down.
+	Execute(ctx context.Context, keptnHandle IKeptn, event KeptnEvent, data T) (R, *Error)
+}
+
+// Validatable can be implemented by the event data of a TypedTaskHandler to validate the decoded data
+type Validatable interface {
+	Validate() error
+}
+
+// TypedTaskHandlerOption configures the validation of the event data of a TypedTaskHandler
+type TypedTaskHandlerOption func(*typedTaskHandlerConfig)
+
+type typedTaskHandlerConfig struct {
+	requireProject bool
+	requireStage   bool
+	requireService bool
+	filters        []func(keptnHandle IKeptn, event KeptnEvent) bool
+}
+
+// RequireProject rejects events without a project
+func RequireProject() TypedTaskHandlerOption {
+	return func(c *typedTaskHandlerConfig) {
+		c.requireProject = true
+	}
+}
+
+// RequireStage rejects events without a stage
+func RequireStage() TypedTaskHandlerOption {
+	return func(c *typedTaskHandlerConfig) {
+		c.requireStage = true
+	}
+}
+
+// RequireService rejects events without a service
+func RequireService() TypedTaskHandlerOption {
+	return func(c *typedTaskHandlerConfig) {
+		c.requireService = true
+	}
+}
+
+// WithTypedEventFilters adds filters which are executed before the event is decoded, see TaskEntry.EventFilters
+func WithTypedEventFilters(filters ...func(keptnHandle IKeptn, event KeptnEvent) bool) TypedTaskHandlerOption {
+	return func(c *typedTaskHandlerConfig) {
+		c.filters = append(c.filters, filters...)
+	}
+}
+
+// WithTypedTaskHandler registers a TypedTaskHandler for the .triggered event of the given task, e.g. "deployment".
+// The data of the event is decoded into T and validated using the given options and, if T implements Validatable,
+// its Validate method. If the data is invalid, an errored .finished event is sent without calling the handler
+func WithTypedTaskHandler[T any, R any](taskName string, handler TypedTaskHandler[T, R], opts ...TypedTaskHandlerOption) KeptnOption {
+	config := typedTaskHandlerConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return WithContextTaskHandler(keptnv2.GetTriggeredEventType(taskName), &typedTaskHandlerAdapter[T, R]{handler: handler, config: config}, config.filters...)
+}
+
+type typedTaskHandlerAdapter[T any, R any] struct {
+	handler TypedTaskHandler[T, R]
+	config  typedTaskHandlerConfig
+}
+
+func (a *typedTaskHandlerAdapter[T, R]) Execute(ctx context.Context, keptnHandle IKeptn, event KeptnEvent) (interface{}, *Error) {
+	var data T
+	if err := keptnv2.Decode(event.Data, &data); err != nil {
+		return nil, newValidationError(fmt.Errorf("could not decode event data: %w", err))
+	}
+	if err := a.validate(event, data); err != nil {
+		return nil, newValidationError(err)
+	}
+	result, err := a.handler.Execute(ctx, keptnHandle, event, data)
+	if err != nil {
+		// the data of the .triggered event is used for the errored .finished event, if no result is returned
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *typedTaskHandlerAdapter[T, R]) validate(event KeptnEvent, data T) error {
+	eventData := keptnv2.EventData{}
+	if err := keptnv2.Decode(event.Data, &eventData); err != nil {
+		return fmt.Errorf("could not decode event data: %w", err)
+	}
+	missing := []string{}
+	if a.config.requireProject && eventData.Project == "" {
+		missing = append(missing, "project")
+	}
+	if a.config.requireStage && eventData.Stage == "" {
+		missing = append(missing, "stage")
+	}
+	if a.config.requireService && eventData.Service == "" {
+		missing = append(missing, "service")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("event data is missing the required properties: %s", strings.Join(missing, ", "))
+	}
+	if validatable, ok := interface{}(data).(Validatable); ok {
+		return validatable.Validate()
+	}
+	if validatable, ok := interface{}(&data).(Validatable); ok {
+		return validatable.Validate()
+	}
+	return nil
+}
+
+func newValidationError(err error) *Error {
+	return &Error{
+		StatusType: keptnv2.StatusErrored,
+		ResultType: keptnv2.ResultFailed,
+		Message:    err.Error(),
+		Err:        err,
+	}
+}
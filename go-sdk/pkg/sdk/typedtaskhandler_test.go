@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTypedTriggeredData struct {
+	keptnv2.EventData
+	Replicas int `json:"replicas"`
+}
+
+func (d fakeTypedTriggeredData) Validate() error {
+	if d.Replicas < 0 {
+		return errors.New("replicas must not be negative")
+	}
+	return nil
+}
+
+type fakeTypedFinishedData struct {
+	keptnv2.EventData
+	DeployedReplicas int `json:"deployedReplicas"`
+}
+
+type fakeTypedTaskHandler struct {
+	receivedData []fakeTypedTriggeredData
+}
+
+func (h *fakeTypedTaskHandler) Execute(ctx context.Context, keptnHandle IKeptn, event KeptnEvent, data fakeTypedTriggeredData) (fakeTypedFinishedData, *Error) {
+	h.receivedData = append(h.receivedData, data)
+	return fakeTypedFinishedData{DeployedReplicas: data.Replicas}, nil
+}
+
+func newTypedTestTriggeredEvent(data interface{}) cloudevents.Event {
+	event := newTestTaskTriggeredEvent()
+	event.SetData(cloudevents.ApplicationJSON, data)
+	return event
+}
+
+func Test_TypedTaskHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         interface{}
+		wantHandled  bool
+		wantStatus   keptnv2.StatusType
+		wantMessage  string
+		wantDeployed int
+	}{
+		{
+			name:         "valid data",
+			data:         map[string]interface{}{"project": "my-project", "stage": "my-stage", "service": "my-service", "replicas": 3},
+			wantHandled:  true,
+			wantStatus:   keptnv2.StatusSucceeded,
+			wantDeployed: 3,
+		},
+		{
+			name:        "data cannot be decoded",
+			data:        map[string]interface{}{"project": "my-project", "stage": "my-stage", "service": "my-service", "replicas": "three"},
+			wantStatus:  keptnv2.StatusErrored,
+			wantMessage: "could not decode event data",
+		},
+		{
+			name:        "missing required properties",
+			data:        map[string]interface{}{"project": "my-project", "replicas": 3},
+			wantStatus:  keptnv2.StatusErrored,
+			wantMessage: "event data is missing the required properties: stage, service",
+		},
+		{
+			name:        "custom validation fails",
+			data:        map[string]interface{}{"project": "my-project", "stage": "my-stage", "service": "my-service", "replicas": -1},
+			wantStatus:  keptnv2.StatusErrored,
+			wantMessage: "replicas must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &fakeTypedTaskHandler{}
+			fakeKeptn := NewFakeKeptn("fake-service")
+			WithTypedTaskHandler[fakeTypedTriggeredData, fakeTypedFinishedData]("faketask", handler, RequireProject(), RequireStage(), RequireService())(fakeKeptn.Keptn)
+			fakeKeptn.Start()
+
+			fakeKeptn.NewEvent(newTypedTestTriggeredEvent(tt.data))
+
+			sentEvents := fakeKeptn.GetEventSender().SentEvents
+			require.Len(t, sentEvents, 2)
+			require.Equal(t, "sh.keptn.event.faketask.started", sentEvents[0].Type())
+			require.Equal(t, "sh.keptn.event.faketask.finished", sentEvents[1].Type())
+			finishedData := fakeTypedFinishedData{}
+			require.Nil(t, sentEvents[1].DataAs(&finishedData))
+			require.Equal(t, tt.wantStatus, finishedData.Status)
+			require.Contains(t, finishedData.Message, tt.wantMessage)
+
+			if !tt.wantHandled {
+				require.Empty(t, handler.receivedData)
+				require.Equal(t, keptnv2.ResultFailed, finishedData.Result)
+				require.Equal(t, "my-project", finishedData.Project)
+				return
+			}
+			require.Len(t, handler.receivedData, 1)
+			require.Equal(t, "my-service", handler.receivedData[0].Service)
+			require.Equal(t, tt.wantDeployed, finishedData.DeployedReplicas)
+		})
+	}
+}
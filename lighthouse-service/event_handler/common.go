@@ -1,7 +1,6 @@
 package event_handler
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -13,7 +12,6 @@ import (
 	apimodels "github.com/keptn/go-utils/pkg/api/models"
 	logger "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -198,51 +196,6 @@ func sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, commitID,
 	return sendEvent(shkeptncontext, triggeredID, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), commitID, keptnHandler, data)
 }
 
-// SLIProviderConfig godoc
-type SLIProviderConfig interface {
-	GetDefaultSLIProvider() (string, error)
-	GetSLIProvider(project string) (string, error)
-}
-
-// K8sSLIProviderConfig godoc
-type K8sSLIProviderConfig struct{}
-
-// GetDefaultSLIProvider godoc
-func (K8sSLIProviderConfig) GetDefaultSLIProvider() (string, error) {
-	kubeAPI, err := GetConfig().GetKubeAPI()
-	if err != nil {
-		return "", err
-	}
-
-	configMap, err := kubeAPI.CoreV1().ConfigMaps(namespace).Get(context.TODO(), "lighthouse-config", v1.GetOptions{})
-
-	if err != nil {
-		return "", errors.New("no default SLI provider specified")
-	}
-
-	sliProvider := configMap.Data["sli-provider"]
-
-	return sliProvider, nil
-}
-
-// GetSLIProvider godoc
-func (K8sSLIProviderConfig) GetSLIProvider(project string) (string, error) {
-	kubeAPI, err := GetConfig().GetKubeAPI()
-	if err != nil {
-		return "", err
-	}
-
-	configMap, err := kubeAPI.CoreV1().ConfigMaps(namespace).Get(context.TODO(), "lighthouse-config-"+project, v1.GetOptions{})
-
-	if err != nil {
-		return "", errors.New("no SLI provider specified for project " + project)
-	}
-
-	sliProvider := configMap.Data["sli-provider"]
-
-	return sliProvider, nil
-}
-
 type Config struct {
 	GetKubeAPI KubeAPIConfigFunc
 }
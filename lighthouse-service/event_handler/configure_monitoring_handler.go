@@ -2,31 +2,29 @@ package event_handler
 
 import (
 	"context"
-	"github.com/sirupsen/logrus"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"os"
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptnevents "github.com/keptn/go-utils/pkg/lib"
-	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/sirupsen/logrus"
 )
 
 type ConfigureMonitoringHandler struct {
-	Event     cloudevents.Event
-	Logger    *logrus.Logger
-	K8sClient kubernetes.Interface
+	Event             cloudevents.Event
+	Logger            *logrus.Logger
+	SLIProviderConfig SLIProviderConfig
 }
 
 var namespace = os.Getenv("POD_NAMESPACE")
 
 type ConfigureMonitoringHandlerOption func(cmh *ConfigureMonitoringHandler)
 
-func WithK8sClient(k8sClient kubernetes.Interface) ConfigureMonitoringHandlerOption {
+func WithSLIProviderConfig(sliProviderConfig SLIProviderConfig) ConfigureMonitoringHandlerOption {
 	return func(cmh *ConfigureMonitoringHandler) {
-		cmh.K8sClient = k8sClient
+		cmh.SLIProviderConfig = sliProviderConfig
 	}
 }
 
@@ -40,12 +38,16 @@ func NewConfigureMonitoringHandler(event cloudevents.Event, logger *logrus.Logge
 		opt(cmh)
 	}
 
-	if cmh.K8sClient == nil {
-		defaultK8sClient, err := GetConfig().GetKubeAPI()
+	if cmh.SLIProviderConfig == nil {
+		configurationServiceEndpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
+		if err != nil {
+			return nil, err
+		}
+		defaultSLIProviderConfig, err := NewSLIProviderConfig(keptnapi.NewResourceHandler(configurationServiceEndpoint.String()))
 		if err != nil {
 			return nil, err
 		}
-		cmh.K8sClient = defaultK8sClient
+		cmh.SLIProviderConfig = defaultSLIProviderConfig
 	}
 
 	return cmh, nil
@@ -77,32 +79,9 @@ func (eh *ConfigureMonitoringHandler) HandleEvent(ctx context.Context) error {
 		return err
 	}
 
-	configMap := getSLISourceConfigMap(e)
-
-	_, err = eh.K8sClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
-
-	if err != nil && k8serrors.IsAlreadyExists(err) {
-		_, err = eh.K8sClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
-		if err != nil {
-			eh.Logger.WithError(err).Error("could not update sli-provider ConfigMap")
-			return err
-		}
-	} else if err != nil {
-		eh.Logger.WithError(err).Error("could not create sli-provider ConfigMap")
+	if err := eh.SLIProviderConfig.SetSLIProvider(e.Project, e.Type); err != nil {
+		eh.Logger.WithError(err).Error("could not store SLI provider of project " + e.Project)
 		return err
 	}
 	return nil
 }
-
-func getSLISourceConfigMap(e *keptnevents.ConfigureMonitoringEventData) *v1.ConfigMap {
-	configMap := &v1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "lighthouse-config-" + e.Project,
-			Namespace: namespace,
-		},
-		Data: map[string]string{
-			"sli-provider": e.Type,
-		},
-	}
-	return configMap
-}
@@ -3,16 +3,14 @@ package event_handler
 import (
 	"context"
 	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes/fake"
-	k8stesting "k8s.io/client-go/testing"
-	"reflect"
-	"sync"
-	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	keptnevents "github.com/keptn/go-utils/pkg/lib"
@@ -22,7 +20,8 @@ import (
 
 func TestConfigureMonitoringHandler_getSLISourceConfigMap(t *testing.T) {
 	type args struct {
-		e *keptnevents.ConfigureMonitoringEventData
+		project     string
+		sliProvider string
 	}
 	tests := []struct {
 		name string
@@ -32,11 +31,8 @@ func TestConfigureMonitoringHandler_getSLISourceConfigMap(t *testing.T) {
 		{
 			name: "configure for prometheus monitoring",
 			args: args{
-				e: &keptnevents.ConfigureMonitoringEventData{
-					Type:    "prometheus",
-					Project: "sockshop",
-					Service: "",
-				},
+				project:     "sockshop",
+				sliProvider: "prometheus",
 			},
 			want: &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
@@ -51,11 +47,8 @@ func TestConfigureMonitoringHandler_getSLISourceConfigMap(t *testing.T) {
 		{
 			name: "configure for dynatrace monitoring",
 			args: args{
-				e: &keptnevents.ConfigureMonitoringEventData{
-					Type:    "dynatrace",
-					Project: "sockshop",
-					Service: "",
-				},
+				project:     "sockshop",
+				sliProvider: "dynatrace",
 			},
 			want: &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
@@ -73,17 +66,15 @@ func TestConfigureMonitoringHandler_getSLISourceConfigMap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getSLISourceConfigMap(tt.args.e); !reflect.DeepEqual(got, tt.want) {
+			if got := getSLISourceConfigMap(tt.args.project, tt.args.sliProvider); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getSLISourceConfigMap() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestConfigureMonitoringHandler_HandleEvent_ConfigMapDoesntExistYet(t *testing.T) {
+func getConfigureMonitoringEvent() cloudevents.Event {
 	ce := cloudevents.NewEvent()
-	wg := &sync.WaitGroup{}
-	ctx := cloudevents.WithEncodingStructured(context.WithValue(context.Background(), GracefulShutdownKey, wg))
 	configureMonitoringData := &keptnevents.ConfigureMonitoringEventData{
 		Project: "my-project",
 		Service: "my-service",
@@ -91,113 +82,40 @@ func TestConfigureMonitoringHandler_HandleEvent_ConfigMapDoesntExistYet(t *testi
 	}
 	ce.SetType(keptnv2.GetTriggeredEventType(keptnv2.ConfigureMonitoringTaskName))
 	ce.SetData(cloudevents.ApplicationJSON, configureMonitoringData)
-
-	logger, _ := test.NewNullLogger()
-
-	fakeK8sClient := fake.NewSimpleClientset()
-
-	handler, err := NewConfigureMonitoringHandler(ce, logger, WithK8sClient(fakeK8sClient))
-	require.Nil(t, err)
-
-	err = handler.HandleEvent(ctx)
-	require.Nil(t, err)
-	require.Len(t, fakeK8sClient.Actions(), 1)
-	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
+	return ce
 }
 
-func TestConfigureMonitoringHandler_HandleEvent_ConfigMapDoesntExistYetAndCreateFails(t *testing.T) {
-	ce := cloudevents.NewEvent()
+func TestConfigureMonitoringHandler_HandleEvent(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	ctx := cloudevents.WithEncodingStructured(context.WithValue(context.Background(), GracefulShutdownKey, wg))
-	configureMonitoringData := &keptnevents.ConfigureMonitoringEventData{
-		Project: "my-project",
-		Service: "my-service",
-		Type:    "my-sli-provider",
-	}
-	ce.SetType(keptnv2.GetTriggeredEventType(keptnv2.ConfigureMonitoringTaskName))
-	ce.SetData(cloudevents.ApplicationJSON, configureMonitoringData)
-
-	logger, hook := test.NewNullLogger()
-
-	fakeK8sClient := fake.NewSimpleClientset()
-
-	fakeK8sClient.PrependReactor("create", "configmaps", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, errors.New("oops")
-	})
-
-	handler, err := NewConfigureMonitoringHandler(ce, logger, WithK8sClient(fakeK8sClient))
-	require.Nil(t, err)
-
-	err = handler.HandleEvent(ctx)
-	require.NotNil(t, err)
-	require.Len(t, fakeK8sClient.Actions(), 1)
-	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
-
-	require.NotNil(t, hook)
-	require.NotEmpty(t, hook.Entries)
-	require.Contains(t, hook.LastEntry().Message, "could not create")
-	require.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
-}
-
-func TestConfigureMonitoringHandler_HandleEvent_ConfigMapAlreadyExists(t *testing.T) {
-	ce := cloudevents.NewEvent()
-	wg := &sync.WaitGroup{}
-	ctx := cloudevents.WithEncodingStructured(context.WithValue(context.Background(), GracefulShutdownKey, wg))
-	configureMonitoringData := &keptnevents.ConfigureMonitoringEventData{
-		Project: "my-project",
-		Service: "my-service",
-		Type:    "my-sli-provider",
-	}
-	ce.SetType(keptnv2.GetTriggeredEventType(keptnv2.ConfigureMonitoringTaskName))
-	ce.SetData(cloudevents.ApplicationJSON, configureMonitoringData)
 
 	logger, _ := test.NewNullLogger()
+	sliProviderConfig := &MockSLIProviderConfig{}
 
-	// initialize the fake k8s client with an already existing configmap for the project
-	fakeK8sClient := fake.NewSimpleClientset(getSLISourceConfigMap(configureMonitoringData))
-
-	handler, err := NewConfigureMonitoringHandler(ce, logger, WithK8sClient(fakeK8sClient))
+	handler, err := NewConfigureMonitoringHandler(getConfigureMonitoringEvent(), logger, WithSLIProviderConfig(sliProviderConfig))
 	require.Nil(t, err)
 
 	err = handler.HandleEvent(ctx)
 	require.Nil(t, err)
-	require.Len(t, fakeK8sClient.Actions(), 2)
-	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
-	require.Equal(t, "update", fakeK8sClient.Actions()[1].GetVerb())
+	require.Equal(t, "my-sli-provider", sliProviderConfig.ProjectSLIProvider.val)
 }
 
-func TestConfigureMonitoringHandler_HandleEvent_ConfigMapAlreadyExistsUpdateFails(t *testing.T) {
-	ce := cloudevents.NewEvent()
+func TestConfigureMonitoringHandler_HandleEvent_StoreFails(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	ctx := cloudevents.WithEncodingStructured(context.WithValue(context.Background(), GracefulShutdownKey, wg))
-	configureMonitoringData := &keptnevents.ConfigureMonitoringEventData{
-		Project: "my-project",
-		Service: "my-service",
-		Type:    "my-sli-provider",
-	}
-	ce.SetType(keptnv2.GetTriggeredEventType(keptnv2.ConfigureMonitoringTaskName))
-	ce.SetData(cloudevents.ApplicationJSON, configureMonitoringData)
 
 	logger, hook := test.NewNullLogger()
+	sliProviderConfig := &MockSLIProviderConfig{}
+	sliProviderConfig.ProjectSLIProvider.err = errors.New("oops")
 
-	// initialize the fake k8s client with an already existing configmap for the project
-	fakeK8sClient := fake.NewSimpleClientset(getSLISourceConfigMap(configureMonitoringData))
-
-	fakeK8sClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, errors.New("oops")
-	})
-
-	handler, err := NewConfigureMonitoringHandler(ce, logger, WithK8sClient(fakeK8sClient))
+	handler, err := NewConfigureMonitoringHandler(getConfigureMonitoringEvent(), logger, WithSLIProviderConfig(sliProviderConfig))
 	require.Nil(t, err)
 
 	err = handler.HandleEvent(ctx)
 	require.NotNil(t, err)
-	require.Len(t, fakeK8sClient.Actions(), 2)
-	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
-	require.Equal(t, "update", fakeK8sClient.Actions()[1].GetVerb())
 
 	require.NotNil(t, hook)
 	require.NotEmpty(t, hook.Entries)
-	require.Contains(t, hook.LastEntry().Message, "could not update")
+	require.Contains(t, hook.LastEntry().Message, "could not store SLI provider")
 	require.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
 }
@@ -0,0 +1,356 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/common/timeutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"gopkg.in/yaml.v3"
+)
+
+// errorBudgetPageSize is the number of events requested from the datastore per page
+const errorBudgetPageSize = 100
+
+// ErrNoErrorBudgetConfigured is returned if the slo.yaml of a service does not contain an error_budget section
+var ErrNoErrorBudgetConfigured = errors.New("no error budget configured")
+
+// ErrorBudget is the error budget section of an slo.yaml file, e.g.:
+//
+//   error_budget:
+//     target: 99.9
+//     period: 30d
+//     burn_rate:
+//       - short_window: 1h
+//         long_window: 6h
+//         max_burn_rate: 14.4
+type ErrorBudget struct {
+	// Target is the percentage of evaluations that have to pass within the period
+	Target float64 `yaml:"target" json:"target"`
+	// Period is the rolling window the budget is computed for
+	Period string `yaml:"period" json:"period"`
+	// BurnRate contains the multi-window burn rate criteria that fail the quality gate when violated
+	BurnRate []*BurnRateCriteria `yaml:"burn_rate" json:"burnRate,omitempty"`
+}
+
+// BurnRateCriteria is violated if the burn rate of both the short and the long window exceed MaxBurnRate
+type BurnRateCriteria struct {
+	ShortWindow string  `yaml:"short_window" json:"shortWindow"`
+	LongWindow  string  `yaml:"long_window" json:"longWindow"`
+	MaxBurnRate float64 `yaml:"max_burn_rate" json:"maxBurnRate"`
+}
+
+// ErrorBudgetStatus describes the state of the error budget of a service in a stage
+type ErrorBudgetStatus struct {
+	Project           string            `json:"project"`
+	Stage             string            `json:"stage"`
+	Service           string            `json:"service"`
+	Target            float64           `json:"target"`
+	Period            string            `json:"period"`
+	TotalEvaluations  int               `json:"totalEvaluations"`
+	FailedEvaluations int               `json:"failedEvaluations"`
+	ConsumedBudget    float64           `json:"consumedBudget"`
+	RemainingBudget   float64           `json:"remainingBudget"`
+	BurnRates         []*BurnRateResult `json:"burnRates,omitempty"`
+}
+
+// BurnRateResult is the outcome of a single BurnRateCriteria
+type BurnRateResult struct {
+	BurnRateCriteria
+	ShortBurnRate float64 `json:"shortBurnRate"`
+	LongBurnRate  float64 `json:"longBurnRate"`
+	Violated      bool    `json:"violated"`
+}
+
+// Violated returns the first violated burn rate criteria, or nil if none of the criteria is violated
+func (s *ErrorBudgetStatus) Violated() *BurnRateResult {
+	for _, burnRate := range s.BurnRates {
+		if burnRate.Violated {
+			return burnRate
+		}
+	}
+	return nil
+}
+
+type evaluationRecord struct {
+	Time   time.Time
+	Failed bool
+}
+
+type errorBudgetDatastoreEvent struct {
+	Data        json.RawMessage `json:"data"`
+	Time        string          `json:"time"`
+	Triggeredid string          `json:"triggeredid"`
+}
+
+type errorBudgetDatastoreResult struct {
+	Events      []errorBudgetDatastoreEvent `json:"events"`
+	NextPageKey string                      `json:"nextPageKey"`
+}
+
+func parseErrorBudget(sloFileContent []byte) (*ErrorBudget, error) {
+	slo := struct {
+		ErrorBudget *ErrorBudget `yaml:"error_budget"`
+	}{}
+	if err := yaml.Unmarshal(sloFileContent, &slo); err != nil {
+		return nil, err
+	}
+	if slo.ErrorBudget == nil {
+		return nil, ErrNoErrorBudgetConfigured
+	}
+	if err := slo.ErrorBudget.validate(); err != nil {
+		return nil, err
+	}
+	return slo.ErrorBudget, nil
+}
+
+func (eb *ErrorBudget) validate() error {
+	if eb.Target <= 0 || eb.Target >= 100 {
+		return fmt.Errorf("invalid error budget target %v: must be between 0 and 100", eb.Target)
+	}
+	if _, err := parseBudgetDuration(eb.Period); err != nil {
+		return fmt.Errorf("invalid error budget period: %w", err)
+	}
+	for _, burnRate := range eb.BurnRate {
+		if burnRate == nil {
+			continue
+		}
+		if _, err := parseBudgetDuration(burnRate.ShortWindow); err != nil {
+			return fmt.Errorf("invalid burn rate short window: %w", err)
+		}
+		if _, err := parseBudgetDuration(burnRate.LongWindow); err != nil {
+			return fmt.Errorf("invalid burn rate long window: %w", err)
+		}
+		if burnRate.MaxBurnRate <= 0 {
+			return fmt.Errorf("invalid max burn rate %v: must be greater than 0", burnRate.MaxBurnRate)
+		}
+	}
+	return nil
+}
+
+// parseBudgetDuration parses a duration that, in addition to the units supported by time.ParseDuration, may use days (e.g. '30d')
+func parseBudgetDuration(duration string) (time.Duration, error) {
+	if strings.HasSuffix(duration, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(duration, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("could not parse duration %s", duration)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %s must be positive", duration)
+	}
+	return d, nil
+}
+
+// ErrorBudgetCalculator computes the error budget of a service based on the evaluation.finished events stored in the mongodb-datastore
+type ErrorBudgetCalculator struct {
+	HTTPClient *http.Client
+}
+
+// GetStatus computes the error budget status at the given time. An optional additional evaluation result can be passed,
+// which is taken into account without having been stored in the datastore yet
+func (c *ErrorBudgetCalculator) GetStatus(project, stage, service string, errorBudget *ErrorBudget, now time.Time, current ...*evaluationRecord) (*ErrorBudgetStatus, error) {
+	period, err := parseBudgetDuration(errorBudget.Period)
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.getEvaluationRecords(project, stage, service, now.Add(-period))
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range current {
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+	status := computeErrorBudgetStatus(errorBudget, records, now)
+	status.Project = project
+	status.Stage = stage
+	status.Service = service
+	return status, nil
+}
+
+func (c *ErrorBudgetCalculator) getEvaluationRecords(project, stage, service string, from time.Time) ([]evaluationRecord, error) {
+	query := url.Values{}
+	query.Add("project", project)
+	query.Add("stage", stage)
+	query.Add("service", service)
+	query.Add("fromTime", timeutils.GetKeptnTimeStamp(from))
+
+	invalidated, err := c.getInvalidatedEvaluations(query)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationQuery := cloneQuery(query)
+	evaluationQuery.Add("source", "lighthouse-service")
+	events, err := c.getEvents(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []evaluationRecord{}
+	for _, event := range events {
+		if invalidated[event.Triggeredid] {
+			continue
+		}
+		data := keptnv2.EvaluationFinishedEventData{}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			continue
+		}
+		if data.Status == keptnv2.StatusErrored {
+			// errored evaluations do not tell anything about the quality of the service
+			continue
+		}
+		timestamp := data.Evaluation.TimeEnd
+		if timestamp == "" {
+			timestamp = event.Time
+		}
+		recordTime, err := timeutils.ParseTimestamp(timestamp)
+		if err != nil {
+			continue
+		}
+		records = append(records, evaluationRecord{
+			Time:   *recordTime,
+			Failed: data.Result == keptnv2.ResultFailed,
+		})
+	}
+	return records, nil
+}
+
+// getInvalidatedEvaluations returns the triggeredids of the evaluations whose latest invalidated/revalidated event is an invalidation.
+// Since the paginated event endpoint of the datastore does not support excluding invalidated evaluations, this is done here
+func (c *ErrorBudgetCalculator) getInvalidatedEvaluations(query url.Values) (map[string]bool, error) {
+	latestChange := map[string]time.Time{}
+	invalidated := map[string]bool{}
+	for _, eventType := range []string{keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName), EvaluationRevalidatedEventType} {
+		events, err := c.getEvents(eventType, cloneQuery(query))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			eventTime, err := timeutils.ParseTimestamp(event.Time)
+			if err != nil || event.Triggeredid == "" {
+				continue
+			}
+			if latest, ok := latestChange[event.Triggeredid]; ok && !eventTime.After(latest) {
+				continue
+			}
+			latestChange[event.Triggeredid] = *eventTime
+			invalidated[event.Triggeredid] = eventType != EvaluationRevalidatedEventType
+		}
+	}
+	return invalidated, nil
+}
+
+// getEvents retrieves all events of the given type matching the query, following the pages returned by the datastore
+func (c *ErrorBudgetCalculator) getEvents(eventType string, query url.Values) ([]errorBudgetDatastoreEvent, error) {
+	query.Set("type", eventType)
+	query.Set("pageSize", strconv.Itoa(errorBudgetPageSize))
+
+	events := []errorBudgetDatastoreEvent{}
+	for {
+		req, err := http.NewRequest("GET", getDatastoreURL()+"/event?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("could not retrieve %s events for error budget", eventType)
+		}
+		result := &errorBudgetDatastoreResult{}
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, err
+		}
+		events = append(events, result.Events...)
+
+		if result.NextPageKey == "" || result.NextPageKey == "0" || len(result.Events) == 0 {
+			return events, nil
+		}
+		query.Set("nextPageKey", result.NextPageKey)
+	}
+}
+
+func cloneQuery(query url.Values) url.Values {
+	clone := url.Values{}
+	for key, values := range query {
+		clone[key] = append([]string{}, values...)
+	}
+	return clone
+}
+
+func computeErrorBudgetStatus(errorBudget *ErrorBudget, records []evaluationRecord, now time.Time) *ErrorBudgetStatus {
+	status := &ErrorBudgetStatus{
+		Target:          errorBudget.Target,
+		Period:          errorBudget.Period,
+		RemainingBudget: 100,
+	}
+	allowedFailureRatio := (100 - errorBudget.Target) / 100
+
+	period, _ := parseBudgetDuration(errorBudget.Period)
+	status.TotalEvaluations, status.FailedEvaluations = countEvaluations(records, now.Add(-period))
+	if status.TotalEvaluations > 0 {
+		failureRatio := float64(status.FailedEvaluations) / float64(status.TotalEvaluations)
+		status.ConsumedBudget = 100 * failureRatio / allowedFailureRatio
+		status.RemainingBudget = 100 - status.ConsumedBudget
+	}
+
+	for _, criteria := range errorBudget.BurnRate {
+		if criteria == nil {
+			continue
+		}
+		shortWindow, _ := parseBudgetDuration(criteria.ShortWindow)
+		longWindow, _ := parseBudgetDuration(criteria.LongWindow)
+		result := &BurnRateResult{
+			BurnRateCriteria: *criteria,
+			ShortBurnRate:    burnRate(records, now.Add(-shortWindow), allowedFailureRatio),
+			LongBurnRate:     burnRate(records, now.Add(-longWindow), allowedFailureRatio),
+		}
+		result.Violated = result.ShortBurnRate > criteria.MaxBurnRate && result.LongBurnRate > criteria.MaxBurnRate
+		status.BurnRates = append(status.BurnRates, result)
+	}
+	return status
+}
+
+func countEvaluations(records []evaluationRecord, from time.Time) (int, int) {
+	total := 0
+	failed := 0
+	for _, record := range records {
+		if record.Time.Before(from) {
+			continue
+		}
+		total++
+		if record.Failed {
+			failed++
+		}
+	}
+	return total, failed
+}
+
+// burnRate returns how fast the budget is consumed within the window, relative to the rate that would exhaust it at the end of the period
+func burnRate(records []evaluationRecord, from time.Time, allowedFailureRatio float64) float64 {
+	total, failed := countEvaluations(records, from)
+	if total == 0 {
+		return 0
+	}
+	return (float64(failed) / float64(total)) / allowedFailureRatio
+}
@@ -0,0 +1,86 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	logger "github.com/sirupsen/logrus"
+)
+
+// ErrorBudgetEndpoint is the path of the endpoint that returns the error budget of a service
+const ErrorBudgetEndpoint = "/v1/errorbudget"
+
+// ErrorBudgetHandler serves the error budget status of a service, e.g. GET /v1/errorbudget?project=sockshop&stage=dev&service=carts
+type ErrorBudgetHandler struct {
+	SLOFileRetriever SLOFileRetriever
+	Calculator       *ErrorBudgetCalculator
+}
+
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewErrorBudgetHandler() (*ErrorBudgetHandler, error) {
+	configurationServiceEndpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorBudgetHandler{
+		SLOFileRetriever: SLOFileRetriever{
+			ResourceHandler: keptnapi.NewResourceHandler(configurationServiceEndpoint.String()),
+			ServiceHandler:  keptnapi.NewServiceHandler(configurationServiceEndpoint.String()),
+		},
+		Calculator: &ErrorBudgetCalculator{HTTPClient: &http.Client{}},
+	}, nil
+}
+
+func (h *ErrorBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+	stage := r.URL.Query().Get("stage")
+	service := r.URL.Query().Get("service")
+	if project == "" || stage == "" || service == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "project, stage and service must be set")
+		return
+	}
+
+	_, sloFileContent, err := h.SLOFileRetriever.GetSLOs(project, stage, service, "")
+	if err != nil {
+		if err == ErrConfigService {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	errorBudget, err := parseErrorBudget(sloFileContent)
+	if err == ErrNoErrorBudgetConfigured {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "could not parse error budget: "+err.Error())
+		return
+	}
+
+	status, err := h.Calculator.GetStatus(project, stage, service, errorBudget, time.Now().UTC())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		logger.WithError(err).Error("could not write error budget response")
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(&errorResponse{Code: code, Message: message})
+}
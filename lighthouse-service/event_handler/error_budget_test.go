@@ -0,0 +1,335 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	"github.com/keptn/go-utils/pkg/common/timeutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	event_handler_mock "github.com/keptn/keptn/lighthouse-service/event_handler/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sloWithErrorBudget = `---
+spec_version: '0.1.0'
+comparison:
+  compare_with: "single_result"
+objectives:
+  - sli: "response_time_p95"
+    pass:
+      - criteria:
+          - "<=500"
+total_score:
+  pass: "90%"
+error_budget:
+  target: 90
+  period: 30d
+  burn_rate:
+    - short_window: 1h
+      long_window: 6h
+      max_burn_rate: 2
+`
+
+func Test_parseErrorBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *ErrorBudget
+		wantErr error
+	}{
+		{
+			name:    "error budget with burn rate",
+			content: sloWithErrorBudget,
+			want: &ErrorBudget{
+				Target:   90,
+				Period:   "30d",
+				BurnRate: []*BurnRateCriteria{{ShortWindow: "1h", LongWindow: "6h", MaxBurnRate: 2}},
+			},
+		},
+		{
+			name:    "no error budget",
+			content: "objectives: []",
+			wantErr: ErrNoErrorBudgetConfigured,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseErrorBudget([]byte(tt.content))
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseErrorBudget_Invalid(t *testing.T) {
+	invalid := []string{
+		"error_budget:\n  target: 100\n  period: 30d",
+		"error_budget:\n  target: 99\n  period: 30x",
+		"error_budget:\n  target: 99\n  period: 1h\n  burn_rate:\n    - short_window: 5m\n      long_window: 1h\n      max_burn_rate: 0",
+	}
+	for _, content := range invalid {
+		_, err := parseErrorBudget([]byte(content))
+		assert.Error(t, err)
+		assert.NotEqual(t, ErrNoErrorBudgetConfigured, err)
+	}
+}
+
+func Test_computeErrorBudgetStatus(t *testing.T) {
+	now := time.Date(2022, 4, 20, 12, 0, 0, 0, time.UTC)
+	errorBudget := &ErrorBudget{
+		Target:   90,
+		Period:   "30d",
+		BurnRate: []*BurnRateCriteria{{ShortWindow: "1h", LongWindow: "6h", MaxBurnRate: 2}},
+	}
+
+	tests := []struct {
+		name              string
+		records           []evaluationRecord
+		wantTotal         int
+		wantFailed        int
+		wantRemaining     float64
+		wantShortBurnRate float64
+		wantLongBurnRate  float64
+		wantViolated      bool
+	}{
+		{
+			name:          "no evaluations",
+			wantRemaining: 100,
+		},
+		{
+			name: "evaluations outside of the period are ignored",
+			records: []evaluationRecord{
+				{Time: now.Add(-31 * 24 * time.Hour), Failed: true},
+				{Time: now.Add(-2 * 24 * time.Hour), Failed: false},
+			},
+			wantTotal:     1,
+			wantRemaining: 100,
+		},
+		{
+			name: "budget partially consumed",
+			records: []evaluationRecord{
+				{Time: now.Add(-10 * 24 * time.Hour), Failed: true},
+				{Time: now.Add(-9 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-8 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-7 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-6 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-5 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-4 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-3 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-2 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-1 * 24 * time.Hour), Failed: false},
+				{Time: now.Add(-10 * time.Minute), Failed: false},
+				{Time: now.Add(-11 * time.Minute), Failed: false},
+				{Time: now.Add(-12 * time.Minute), Failed: false},
+				{Time: now.Add(-13 * time.Minute), Failed: false},
+				{Time: now.Add(-14 * time.Minute), Failed: false},
+				{Time: now.Add(-15 * time.Minute), Failed: false},
+				{Time: now.Add(-16 * time.Minute), Failed: false},
+				{Time: now.Add(-17 * time.Minute), Failed: false},
+				{Time: now.Add(-18 * time.Minute), Failed: false},
+				{Time: now.Add(-19 * time.Minute), Failed: false},
+			},
+			wantTotal:     20,
+			wantFailed:    1,
+			wantRemaining: 50,
+		},
+		{
+			name: "only the short window burns too fast",
+			records: []evaluationRecord{
+				{Time: now.Add(-5 * time.Hour), Failed: false},
+				{Time: now.Add(-4 * time.Hour), Failed: false},
+				{Time: now.Add(-3 * time.Hour), Failed: false},
+				{Time: now.Add(-2 * time.Hour), Failed: false},
+				{Time: now.Add(-10 * time.Minute), Failed: true},
+			},
+			wantTotal:         5,
+			wantFailed:        1,
+			wantRemaining:     -100,
+			wantShortBurnRate: 10,
+			wantLongBurnRate:  2,
+			wantViolated:      false,
+		},
+		{
+			name: "short and long window burn too fast",
+			records: []evaluationRecord{
+				{Time: now.Add(-5 * time.Hour), Failed: true},
+				{Time: now.Add(-4 * time.Hour), Failed: false},
+				{Time: now.Add(-3 * time.Hour), Failed: false},
+				{Time: now.Add(-10 * time.Minute), Failed: true},
+			},
+			wantTotal:         4,
+			wantFailed:        2,
+			wantRemaining:     -400,
+			wantShortBurnRate: 10,
+			wantLongBurnRate:  5,
+			wantViolated:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := computeErrorBudgetStatus(errorBudget, tt.records, now)
+			assert.Equal(t, tt.wantTotal, status.TotalEvaluations)
+			assert.Equal(t, tt.wantFailed, status.FailedEvaluations)
+			assert.InDelta(t, tt.wantRemaining, status.RemainingBudget, 0.0001)
+			require.Len(t, status.BurnRates, 1)
+			assert.InDelta(t, tt.wantShortBurnRate, status.BurnRates[0].ShortBurnRate, 0.0001)
+			assert.InDelta(t, tt.wantLongBurnRate, status.BurnRates[0].LongBurnRate, 0.0001)
+			assert.Equal(t, tt.wantViolated, status.BurnRates[0].Violated)
+			assert.Equal(t, tt.wantViolated, status.Violated() != nil)
+		})
+	}
+}
+
+// newErrorBudgetDatastore serves the given evaluation.finished events, which get the triggeredids triggered-<index>,
+// and additional invalidated/revalidated events page by page
+func newErrorBudgetDatastore(t *testing.T, events []keptnv2.EvaluationFinishedEventData, validityEvents ...map[string]interface{}) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/event", r.URL.Path)
+		assert.NotEmpty(t, r.URL.Query().Get("fromTime"))
+		assert.Equal(t, "sockshop", r.URL.Query().Get("project"))
+		assert.Equal(t, "dev", r.URL.Query().Get("stage"))
+		assert.Equal(t, "carts", r.URL.Query().Get("service"))
+
+		resultEvents := []map[string]interface{}{}
+		eventType := r.URL.Query().Get("type")
+		if eventType == keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName) {
+			assert.Equal(t, "lighthouse-service", r.URL.Query().Get("source"))
+			for i, event := range events {
+				resultEvents = append(resultEvents, map[string]interface{}{"data": event, "triggeredid": fmt.Sprintf("triggered-%d", i)})
+			}
+		} else {
+			for _, event := range validityEvents {
+				if event["type"] == eventType {
+					resultEvents = append(resultEvents, event)
+				}
+			}
+		}
+
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("nextPageKey"))
+		result := map[string]interface{}{}
+		if end := offset + pageSize; end < len(resultEvents) {
+			result["nextPageKey"] = strconv.Itoa(end)
+			resultEvents = resultEvents[offset:end]
+		} else {
+			resultEvents = resultEvents[offset:]
+		}
+		result["events"] = resultEvents
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	_ = os.Setenv("MONGODB_DATASTORE", strings.TrimPrefix(ts.URL, "http://"))
+	return ts
+}
+
+func TestErrorBudgetCalculator_GetStatus(t *testing.T) {
+	now := time.Now().UTC()
+	events := []keptnv2.EvaluationFinishedEventData{
+		{
+			EventData:  keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded},
+			Evaluation: keptnv2.EvaluationDetails{TimeEnd: timeutils.GetKeptnTimeStamp(now.Add(-2 * time.Hour))},
+		},
+		{
+			EventData:  keptnv2.EventData{Result: keptnv2.ResultFailed, Status: keptnv2.StatusSucceeded},
+			Evaluation: keptnv2.EvaluationDetails{TimeEnd: timeutils.GetKeptnTimeStamp(now.Add(-3 * time.Hour))},
+		},
+		{
+			// errored evaluations are not taken into account
+			EventData:  keptnv2.EventData{Result: keptnv2.ResultFailed, Status: keptnv2.StatusErrored},
+			Evaluation: keptnv2.EvaluationDetails{TimeEnd: timeutils.GetKeptnTimeStamp(now.Add(-3 * time.Hour))},
+		},
+	}
+	ts := newErrorBudgetDatastore(t, events)
+	defer ts.Close()
+
+	calculator := &ErrorBudgetCalculator{HTTPClient: &http.Client{}}
+	errorBudget, err := parseErrorBudget([]byte(sloWithErrorBudget))
+	require.Nil(t, err)
+
+	status, err := calculator.GetStatus("sockshop", "dev", "carts", errorBudget, now, &evaluationRecord{Time: now, Failed: false})
+	require.Nil(t, err)
+	assert.Equal(t, "sockshop", status.Project)
+	assert.Equal(t, 3, status.TotalEvaluations)
+	assert.Equal(t, 1, status.FailedEvaluations)
+	assert.False(t, status.BurnRates[0].Violated)
+}
+
+func TestErrorBudgetCalculator_GetStatus_MultiplePagesAndInvalidatedEvaluations(t *testing.T) {
+	now := time.Now().UTC()
+	events := []keptnv2.EvaluationFinishedEventData{}
+	for i := 0; i < 2*errorBudgetPageSize+10; i++ {
+		result := keptnv2.ResultPass
+		if i < 3 {
+			result = keptnv2.ResultFailed
+		}
+		events = append(events, keptnv2.EvaluationFinishedEventData{
+			EventData:  keptnv2.EventData{Result: result, Status: keptnv2.StatusSucceeded},
+			Evaluation: keptnv2.EvaluationDetails{TimeEnd: timeutils.GetKeptnTimeStamp(now.Add(-time.Duration(i+1) * time.Minute))},
+		})
+	}
+	invalidatedType := keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName)
+	ts := newErrorBudgetDatastore(t, events,
+		// triggered-0 is invalidated, triggered-1 is invalidated and re-validated, triggered-2 is re-validated and invalidated again
+		map[string]interface{}{"type": invalidatedType, "triggeredid": "triggered-0", "time": timeutils.GetKeptnTimeStamp(now.Add(-20 * time.Second))},
+		map[string]interface{}{"type": invalidatedType, "triggeredid": "triggered-1", "time": timeutils.GetKeptnTimeStamp(now.Add(-20 * time.Second))},
+		map[string]interface{}{"type": EvaluationRevalidatedEventType, "triggeredid": "triggered-1", "time": timeutils.GetKeptnTimeStamp(now.Add(-10 * time.Second))},
+		map[string]interface{}{"type": EvaluationRevalidatedEventType, "triggeredid": "triggered-2", "time": timeutils.GetKeptnTimeStamp(now.Add(-20 * time.Second))},
+		map[string]interface{}{"type": invalidatedType, "triggeredid": "triggered-2", "time": timeutils.GetKeptnTimeStamp(now.Add(-10 * time.Second))},
+	)
+	defer ts.Close()
+
+	calculator := &ErrorBudgetCalculator{HTTPClient: &http.Client{}}
+	errorBudget, err := parseErrorBudget([]byte(sloWithErrorBudget))
+	require.Nil(t, err)
+
+	status, err := calculator.GetStatus("sockshop", "dev", "carts", errorBudget, now)
+	require.Nil(t, err)
+	assert.Equal(t, 2*errorBudgetPageSize+8, status.TotalEvaluations)
+	assert.Equal(t, 1, status.FailedEvaluations)
+}
+
+func TestErrorBudgetHandler_ServeHTTP(t *testing.T) {
+	now := time.Now().UTC()
+	ts := newErrorBudgetDatastore(t, []keptnv2.EvaluationFinishedEventData{
+		{
+			EventData:  keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded},
+			Evaluation: keptnv2.EvaluationDetails{TimeEnd: timeutils.GetKeptnTimeStamp(now.Add(-2 * time.Hour))},
+		},
+	})
+	defer ts.Close()
+
+	handler := &ErrorBudgetHandler{
+		SLOFileRetriever: SLOFileRetriever{
+			ResourceHandler: &event_handler_mock.ResourceHandlerMock{
+				GetResourceFunc: func(scope keptnapi.ResourceScope, options ...keptnapi.URIOption) (*models.Resource, error) {
+					return &models.Resource{ResourceContent: sloWithErrorBudget}, nil
+				},
+			},
+		},
+		Calculator: &ErrorBudgetCalculator{HTTPClient: &http.Client{}},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ErrorBudgetEndpoint+"?project=sockshop&stage=dev&service=carts", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	status := &ErrorBudgetStatus{}
+	require.Nil(t, json.NewDecoder(rec.Body).Decode(status))
+	assert.Equal(t, 1, status.TotalEvaluations)
+	assert.Equal(t, float64(100), status.RemainingBudget)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ErrorBudgetEndpoint+"?project=sockshop", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/types"
 	logger "github.com/sirupsen/logrus"
@@ -105,8 +106,11 @@ func (eh *EvaluateSLIHandler) processGetSliFinishedEvent(ctx context.Context, sh
 		logger.Error(msg)
 		return sendErroredFinishedEventWithMessage(shkeptncontext, "", commitID, msg, "", eh.KeptnHandler, e)
 	}
-	triggeredID := triggeredEvents[0].ID
+	return eh.evaluateSLIs(shkeptncontext, triggeredEvents[0].ID, commitID, e)
+}
 
+// evaluateSLIs evaluates the retrieved SLI values against the SLO of the service and sends the evaluation.finished event
+func (eh *EvaluateSLIHandler) evaluateSLIs(shkeptncontext string, triggeredID string, commitID string, e *keptnv2.GetSLIFinishedEventData) error {
 	logger.Debug("Start to evaluate SLIs")
 
 	evaluationDetails := keptnv2.EvaluationDetails{
@@ -188,11 +192,46 @@ func (eh *EvaluateSLIHandler) processGetSliFinishedEvent(ctx context.Context, sh
 	if err != nil {
 		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, commitID, err.Error(), string(sloFileContent), eh.KeptnHandler, e)
 	}
+
+	// apply the burn rate criteria of the error budget, if one has been configured
+	err = eh.evaluateErrorBudget(e, sloFileContent, evaluationResult)
+	if err != nil {
+		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, commitID, err.Error(), string(sloFileContent), eh.KeptnHandler, e)
+	}
 	logger.Debug("Evaluation result: " + string(evaluationResult.Result))
 
 	evaluationResult.Evaluation.SLOFileContent = base64.StdEncoding.EncodeToString(sloFileContent)
 
-	return sendEvent(shkeptncontext, triggeredEvents[0].ID, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), commitID, eh.KeptnHandler, evaluationResult)
+	return sendEvent(shkeptncontext, triggeredID, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), commitID, eh.KeptnHandler, evaluationResult)
+}
+
+// evaluateErrorBudget fails the evaluation if one of the burn rate criteria of the error budget is violated
+func (eh *EvaluateSLIHandler) evaluateErrorBudget(e *keptnv2.GetSLIFinishedEventData, sloFileContent []byte, evaluationResult *keptnv2.EvaluationFinishedEventData) error {
+	errorBudget, err := parseErrorBudget(sloFileContent)
+	if err == ErrNoErrorBudgetConfigured {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not parse error budget: %w", err)
+	}
+
+	calculator := &ErrorBudgetCalculator{HTTPClient: eh.HTTPClient}
+	now := time.Now().UTC()
+	current := &evaluationRecord{
+		Time:   now,
+		Failed: evaluationResult.Result == keptnv2.ResultFailed,
+	}
+	status, err := calculator.GetStatus(e.Project, e.Stage, e.Service, errorBudget, now, current)
+	if err != nil {
+		return fmt.Errorf("could not calculate error budget: %w", err)
+	}
+
+	if violated := status.Violated(); violated != nil {
+		evaluationResult.Evaluation.Result = string(keptnv2.ResultFailed)
+		evaluationResult.Result = keptnv2.ResultFailed
+		evaluationResult.Message = fmt.Sprintf("Evaluation failed since the error budget burn rate of %v (%s) and %v (%s) exceeds the maximum burn rate of %v. Remaining error budget: %.2f%%",
+			violated.ShortBurnRate, violated.ShortWindow, violated.LongBurnRate, violated.LongWindow, violated.MaxBurnRate, status.RemainingBudget)
+	}
+	return nil
 }
 
 func evaluateObjectives(e *keptnv2.GetSLIFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, previousEvaluationEvents []*keptnv2.EvaluationFinishedEventData) (*keptnv2.EvaluationFinishedEventData, float64, bool) {
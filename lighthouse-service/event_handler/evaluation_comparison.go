@@ -0,0 +1,285 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// ErrEvaluationNotFound is returned if no evaluation.finished event is available for a keptnContext
+var ErrEvaluationNotFound = errors.New("evaluation not found")
+
+const (
+	sloChangeAdded    = "added"
+	sloChangeRemoved  = "removed"
+	sloChangeModified = "modified"
+)
+
+// EvaluationComparison is the per-SLI diff of two evaluations
+type EvaluationComparison struct {
+	From           *EvaluationSummary `json:"from"`
+	To             *EvaluationSummary `json:"to"`
+	IndicatorDiffs []*IndicatorDiff   `json:"indicatorDiffs"`
+	SLOChanges     []*SLOChange       `json:"sloChanges"`
+	// Regressions contains the names of the SLIs that got worse, as well as 'total_score' if the overall result got worse
+	Regressions []string `json:"regressions"`
+}
+
+// EvaluationSummary describes one of the compared evaluations
+type EvaluationSummary struct {
+	KeptnContext string  `json:"keptnContext"`
+	EventID      string  `json:"eventId"`
+	GitCommitID  string  `json:"gitCommitId"`
+	Project      string  `json:"project"`
+	Stage        string  `json:"stage"`
+	Service      string  `json:"service"`
+	Result       string  `json:"result"`
+	Score        float64 `json:"score"`
+	TimeStart    string  `json:"timeStart"`
+	TimeEnd      string  `json:"timeEnd"`
+	// Annotations and Invalidation are only set if the comparison has been requested including annotations
+	Annotations  []*EvaluationAnnotation `json:"annotations,omitempty"`
+	Invalidation *EvaluationValidity     `json:"invalidation,omitempty"`
+
+	indicatorResults []*keptnv2.SLIEvaluationResult
+}
+
+// IndicatorDiff describes the change of a single SLI between two evaluations. Values that are not available in one of the evaluations are nil
+type IndicatorDiff struct {
+	Metric      string   `json:"metric"`
+	DisplayName string   `json:"displayName,omitempty"`
+	FromValue   *float64 `json:"fromValue,omitempty"`
+	ToValue     *float64 `json:"toValue,omitempty"`
+	ValueChange *float64 `json:"valueChange,omitempty"`
+	FromStatus  string   `json:"fromStatus,omitempty"`
+	ToStatus    string   `json:"toStatus,omitempty"`
+	FromScore   float64  `json:"fromScore"`
+	ToScore     float64  `json:"toScore"`
+	Regression  bool     `json:"regression"`
+}
+
+// SLOChange describes the change of an objective between the slo.yaml files used by two evaluations
+type SLOChange struct {
+	SLI    string     `json:"sli"`
+	Change string     `json:"change"`
+	From   *keptn.SLO `json:"from,omitempty"`
+	To     *keptn.SLO `json:"to,omitempty"`
+}
+
+// EvaluationComparer compares the results of two evaluations stored in the mongodb-datastore
+type EvaluationComparer struct {
+	EventStore       EventStore
+	SLOFileRetriever SLOFileRetriever
+}
+
+// Compare compares the latest evaluation of the fromContext with the latest evaluation of the toContext.
+// If includeAnnotations is set, the annotations and the invalidation state of both evaluations are added to the comparison
+func (c *EvaluationComparer) Compare(fromContext, toContext string, includeAnnotations bool) (*EvaluationComparison, error) {
+	from, err := c.getEvaluation(fromContext)
+	if err != nil {
+		return nil, err
+	}
+	to, err := c.getEvaluation(toContext)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &EvaluationComparison{
+		From:           from,
+		To:             to,
+		IndicatorDiffs: compareIndicatorResults(from.indicatorResults, to.indicatorResults),
+		SLOChanges:     []*SLOChange{},
+		Regressions:    []string{},
+	}
+
+	for _, diff := range comparison.IndicatorDiffs {
+		if diff.Regression {
+			comparison.Regressions = append(comparison.Regressions, diff.Metric)
+		}
+	}
+	if resultRank(to.Result) < resultRank(from.Result) || to.Score < from.Score {
+		comparison.Regressions = append(comparison.Regressions, "total_score")
+	}
+
+	if from.GitCommitID != "" && to.GitCommitID != "" && from.GitCommitID != to.GitCommitID {
+		fromSLO, _, err := c.SLOFileRetriever.GetSLOs(from.Project, from.Stage, from.Service, from.GitCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve SLO of commit %s: %w", from.GitCommitID, err)
+		}
+		toSLO, _, err := c.SLOFileRetriever.GetSLOs(to.Project, to.Stage, to.Service, to.GitCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve SLO of commit %s: %w", to.GitCommitID, err)
+		}
+		comparison.SLOChanges = compareSLOs(fromSLO, toSLO)
+	}
+
+	if includeAnnotations {
+		for _, summary := range []*EvaluationSummary{from, to} {
+			if err := c.addAnnotations(summary); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return comparison, nil
+}
+
+func (c *EvaluationComparer) addAnnotations(summary *EvaluationSummary) error {
+	validityManager := &EvaluationValidityManager{EventStore: c.EventStore}
+	annotations, err := validityManager.GetAnnotations(summary.Project, summary.KeptnContext, summary.EventID)
+	if err != nil {
+		return err
+	}
+	invalidation, err := validityManager.GetInvalidation(summary.Project, summary.KeptnContext, summary.EventID)
+	if err != nil {
+		return err
+	}
+	summary.Annotations = annotations
+	summary.Invalidation = invalidation
+	return nil
+}
+
+func (c *EvaluationComparer) getEvaluation(keptnContext string) (*EvaluationSummary, error) {
+	events, errObj := c.EventStore.GetEvents(&keptnapi.EventFilter{
+		KeptnContext: keptnContext,
+		EventType:    keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName),
+	})
+	if errObj != nil {
+		return nil, fmt.Errorf("could not retrieve evaluation of context %s: %s", keptnContext, errObj.GetMessage())
+	}
+	for _, event := range events {
+		if event.Source == nil || *event.Source != "lighthouse-service" {
+			continue
+		}
+		data := &keptnv2.EvaluationFinishedEventData{}
+		bytes, err := json.Marshal(event.Data)
+		if err != nil {
+			continue
+		}
+		if err := json.Unmarshal(bytes, data); err != nil {
+			continue
+		}
+		return &EvaluationSummary{
+			KeptnContext:     keptnContext,
+			EventID:          event.ID,
+			GitCommitID:      event.GitCommitID,
+			Project:          data.Project,
+			Stage:            data.Stage,
+			Service:          data.Service,
+			Result:           data.Evaluation.Result,
+			Score:            data.Evaluation.Score,
+			TimeStart:        data.Evaluation.TimeStart,
+			TimeEnd:          data.Evaluation.TimeEnd,
+			indicatorResults: data.Evaluation.IndicatorResults,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for context %s", ErrEvaluationNotFound, keptnContext)
+}
+
+func compareIndicatorResults(from, to []*keptnv2.SLIEvaluationResult) []*IndicatorDiff {
+	diffs := []*IndicatorDiff{}
+	fromResults := map[string]*keptnv2.SLIEvaluationResult{}
+	for _, result := range from {
+		if result != nil && result.Value != nil {
+			fromResults[result.Value.Metric] = result
+		}
+	}
+
+	for _, toResult := range to {
+		if toResult == nil || toResult.Value == nil {
+			continue
+		}
+		diff := &IndicatorDiff{
+			Metric:      toResult.Value.Metric,
+			DisplayName: toResult.DisplayName,
+			ToStatus:    toResult.Status,
+			ToScore:     toResult.Score,
+		}
+		if toResult.Value.Success {
+			diff.ToValue = floatp(toResult.Value.Value)
+		}
+		if fromResult, ok := fromResults[diff.Metric]; ok {
+			diff.FromStatus = fromResult.Status
+			diff.FromScore = fromResult.Score
+			if fromResult.Value.Success {
+				diff.FromValue = floatp(fromResult.Value.Value)
+			}
+			diff.Regression = resultRank(diff.ToStatus) < resultRank(diff.FromStatus) || diff.ToScore < diff.FromScore
+			delete(fromResults, diff.Metric)
+		}
+		if diff.FromValue != nil && diff.ToValue != nil {
+			diff.ValueChange = floatp(*diff.ToValue - *diff.FromValue)
+		}
+		diffs = append(diffs, diff)
+	}
+
+	// SLIs that are only part of the first evaluation
+	for _, fromResult := range from {
+		if fromResult == nil || fromResult.Value == nil {
+			continue
+		}
+		if _, ok := fromResults[fromResult.Value.Metric]; !ok {
+			continue
+		}
+		diff := &IndicatorDiff{
+			Metric:      fromResult.Value.Metric,
+			DisplayName: fromResult.DisplayName,
+			FromStatus:  fromResult.Status,
+			FromScore:   fromResult.Score,
+		}
+		if fromResult.Value.Success {
+			diff.FromValue = floatp(fromResult.Value.Value)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func compareSLOs(from, to *keptn.ServiceLevelObjectives) []*SLOChange {
+	changes := []*SLOChange{}
+	fromObjectives := map[string]*keptn.SLO{}
+	for _, objective := range from.Objectives {
+		fromObjectives[objective.SLI] = objective
+	}
+	for _, toObjective := range to.Objectives {
+		fromObjective, ok := fromObjectives[toObjective.SLI]
+		if !ok {
+			changes = append(changes, &SLOChange{SLI: toObjective.SLI, Change: sloChangeAdded, To: toObjective})
+			continue
+		}
+		if !reflect.DeepEqual(fromObjective, toObjective) {
+			changes = append(changes, &SLOChange{SLI: toObjective.SLI, Change: sloChangeModified, From: fromObjective, To: toObjective})
+		}
+		delete(fromObjectives, toObjective.SLI)
+	}
+	for _, fromObjective := range from.Objectives {
+		if _, ok := fromObjectives[fromObjective.SLI]; ok {
+			changes = append(changes, &SLOChange{SLI: fromObjective.SLI, Change: sloChangeRemoved, From: fromObjective})
+		}
+	}
+	return changes
+}
+
+// resultRank orders evaluation results from worst to best
+func resultRank(result string) int {
+	switch result {
+	case string(keptnv2.ResultPass):
+		return 3
+	case string(keptnv2.ResultWarning):
+		return 2
+	case "info":
+		return 2
+	case string(keptnv2.ResultFailed):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func floatp(f float64) *float64 {
+	return &f
+}
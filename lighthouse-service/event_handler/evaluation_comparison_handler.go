@@ -0,0 +1,64 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	logger "github.com/sirupsen/logrus"
+)
+
+// EvaluationComparisonEndpoint is the path of the endpoint that compares two evaluations
+const EvaluationComparisonEndpoint = "/v1/evaluation/compare"
+
+// EvaluationComparisonHandler serves the comparison of two evaluations, e.g. GET /v1/evaluation/compare?from=<keptnContext>&to=<keptnContext>.
+// Annotations and invalidations of the evaluations are included with the query parameter annotations=true
+type EvaluationComparisonHandler struct {
+	Comparer *EvaluationComparer
+}
+
+func NewEvaluationComparisonHandler() (*EvaluationComparisonHandler, error) {
+	configurationServiceEndpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
+	if err != nil {
+		return nil, err
+	}
+	return &EvaluationComparisonHandler{
+		Comparer: &EvaluationComparer{
+			EventStore: keptnapi.NewEventHandler(getDatastoreURL()),
+			SLOFileRetriever: SLOFileRetriever{
+				ResourceHandler: keptnapi.NewResourceHandler(configurationServiceEndpoint.String()),
+				ServiceHandler:  keptnapi.NewServiceHandler(configurationServiceEndpoint.String()),
+			},
+		},
+	}, nil
+}
+
+func (h *EvaluationComparisonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "from and to must be set")
+		return
+	}
+
+	includeAnnotations, _ := strconv.ParseBool(r.URL.Query().Get("annotations"))
+
+	comparison, err := h.Comparer.Compare(from, to, includeAnnotations)
+	if err != nil {
+		if errors.Is(err, ErrEvaluationNotFound) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(comparison); err != nil {
+		logger.WithError(err).Error("could not write evaluation comparison response")
+	}
+}
@@ -0,0 +1,166 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	"github.com/keptn/go-utils/pkg/common/strutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	event_handler_mock "github.com/keptn/keptn/lighthouse-service/event_handler/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const comparisonSLOv1 = `objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=500"
+  - sli: throughput
+total_score:
+  pass: "90%"`
+
+const comparisonSLOv2 = `objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=400"
+  - sli: error_rate
+    pass:
+      - criteria:
+          - "=0"
+total_score:
+  pass: "90%"`
+
+func getComparisonEvents() map[string][]*models.KeptnContextExtendedCE {
+	return map[string][]*models.KeptnContextExtendedCE{
+		"build-41": {
+			{
+				ID:          "evaluation-41",
+				Source:      strutils.Stringp("lighthouse-service"),
+				GitCommitID: "commit-41",
+				Data: keptnv2.EvaluationFinishedEventData{
+					EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts"},
+					Evaluation: keptnv2.EvaluationDetails{
+						Result: "pass",
+						Score:  100,
+						IndicatorResults: []*keptnv2.SLIEvaluationResult{
+							{Value: &keptnv2.SLIResult{Metric: "response_time_p95", Value: 300, Success: true}, Status: "pass", Score: 1},
+							{Value: &keptnv2.SLIResult{Metric: "throughput", Value: 1000, Success: true}, Status: "info"},
+						},
+					},
+				},
+			},
+		},
+		"build-42": {
+			{
+				ID:          "evaluation-42",
+				Source:      strutils.Stringp("lighthouse-service"),
+				GitCommitID: "commit-42",
+				Data: keptnv2.EvaluationFinishedEventData{
+					EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts"},
+					Evaluation: keptnv2.EvaluationDetails{
+						Result: "fail",
+						Score:  50,
+						IndicatorResults: []*keptnv2.SLIEvaluationResult{
+							{Value: &keptnv2.SLIResult{Metric: "response_time_p95", Value: 450, Success: true}, Status: "fail", Score: 0},
+							{Value: &keptnv2.SLIResult{Metric: "error_rate", Value: 0, Success: true}, Status: "pass", Score: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newEvaluationComparer() *EvaluationComparer {
+	events := getComparisonEvents()
+	sloCalls := 0
+	return &EvaluationComparer{
+		EventStore: &event_handler_mock.EventStoreMock{
+			GetEventsFunc: func(filter *keptnapi.EventFilter) ([]*models.KeptnContextExtendedCE, *models.Error) {
+				return events[filter.KeptnContext], nil
+			},
+		},
+		SLOFileRetriever: SLOFileRetriever{
+			ResourceHandler: &event_handler_mock.ResourceHandlerMock{
+				GetResourceFunc: func(scope keptnapi.ResourceScope, options ...keptnapi.URIOption) (*models.Resource, error) {
+					// the SLO of the first evaluation is retrieved first
+					sloCalls++
+					if sloCalls == 1 {
+						return &models.Resource{ResourceContent: comparisonSLOv1}, nil
+					}
+					return &models.Resource{ResourceContent: comparisonSLOv2}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestEvaluationComparer_Compare(t *testing.T) {
+	comparer := newEvaluationComparer()
+
+	comparison, err := comparer.Compare("build-41", "build-42", false)
+	require.Nil(t, err)
+
+	assert.Equal(t, "evaluation-41", comparison.From.EventID)
+	assert.Equal(t, "commit-41", comparison.From.GitCommitID)
+	assert.Equal(t, "evaluation-42", comparison.To.EventID)
+	assert.Equal(t, "fail", comparison.To.Result)
+
+	require.Len(t, comparison.IndicatorDiffs, 3)
+	assert.Equal(t, &IndicatorDiff{
+		Metric:      "response_time_p95",
+		FromValue:   floatp(300),
+		ToValue:     floatp(450),
+		ValueChange: floatp(150),
+		FromStatus:  "pass",
+		ToStatus:    "fail",
+		FromScore:   1,
+		ToScore:     0,
+		Regression:  true,
+	}, comparison.IndicatorDiffs[0])
+	assert.Equal(t, &IndicatorDiff{Metric: "error_rate", ToValue: floatp(0), ToStatus: "pass", ToScore: 1}, comparison.IndicatorDiffs[1])
+	assert.Equal(t, &IndicatorDiff{Metric: "throughput", FromValue: floatp(1000), FromStatus: "info"}, comparison.IndicatorDiffs[2])
+
+	assert.Equal(t, []string{"response_time_p95", "total_score"}, comparison.Regressions)
+
+	require.Len(t, comparison.SLOChanges, 3)
+	assert.Equal(t, "response_time_p95", comparison.SLOChanges[0].SLI)
+	assert.Equal(t, sloChangeModified, comparison.SLOChanges[0].Change)
+	assert.Equal(t, "<=500", comparison.SLOChanges[0].From.Pass[0].Criteria[0])
+	assert.Equal(t, "<=400", comparison.SLOChanges[0].To.Pass[0].Criteria[0])
+	assert.Equal(t, &SLOChange{SLI: "error_rate", Change: sloChangeAdded, To: comparison.SLOChanges[1].To}, comparison.SLOChanges[1])
+	assert.Equal(t, "throughput", comparison.SLOChanges[2].SLI)
+	assert.Equal(t, sloChangeRemoved, comparison.SLOChanges[2].Change)
+}
+
+func TestEvaluationComparer_CompareNotFound(t *testing.T) {
+	comparer := newEvaluationComparer()
+
+	_, err := comparer.Compare("build-41", "unknown", false)
+	assert.ErrorIs(t, err, ErrEvaluationNotFound)
+}
+
+func TestEvaluationComparisonHandler_ServeHTTP(t *testing.T) {
+	handler := &EvaluationComparisonHandler{Comparer: newEvaluationComparer()}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EvaluationComparisonEndpoint+"?from=build-41&to=build-42", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	comparison := &EvaluationComparison{}
+	require.Nil(t, json.NewDecoder(rec.Body).Decode(comparison))
+	assert.Equal(t, []string{"response_time_p95", "total_score"}, comparison.Regressions)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EvaluationComparisonEndpoint+"?from=build-41&to=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EvaluationComparisonEndpoint+"?from=build-41", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
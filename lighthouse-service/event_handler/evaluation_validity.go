@@ -0,0 +1,312 @@
+package event_handler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	apimodels "github.com/keptn/go-utils/pkg/api/models"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	"github.com/keptn/go-utils/pkg/common/timeutils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	logger "github.com/sirupsen/logrus"
+)
+
+// EvaluationRevalidatedEventType is the type of the event that reverts the invalidation of an evaluation
+const EvaluationRevalidatedEventType = "sh.keptn.event.evaluation.revalidated"
+
+// EvaluationAnnotatedEventType is the type of the event that attaches an annotation to an evaluation
+const EvaluationAnnotatedEventType = "sh.keptn.event.evaluation.annotated"
+
+// ErrInvalidEvaluationSelection is returned if a request does not select any evaluations
+var ErrInvalidEvaluationSelection = errors.New("invalid evaluation selection")
+
+// EvaluationValidityRequest selects the evaluations of a service that should be invalidated or re-validated,
+// either by the IDs of their evaluation.finished events or by a time range
+type EvaluationValidityRequest struct {
+	Project  string   `json:"project"`
+	Stage    string   `json:"stage"`
+	Service  string   `json:"service"`
+	EventIDs []string `json:"eventIds,omitempty"`
+	// From and To limit the time range of the selected evaluations. To defaults to the current time
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Reason string `json:"reason"`
+	Author string `json:"author"`
+}
+
+// EvaluationValidityResponse contains the IDs of the evaluation.finished events affected by an EvaluationValidityRequest
+type EvaluationValidityResponse struct {
+	Evaluations []string `json:"evaluations"`
+}
+
+// EvaluationAnnotationRequest attaches a free-text annotation to an evaluation
+type EvaluationAnnotationRequest struct {
+	Project string `json:"project"`
+	Stage   string `json:"stage"`
+	Service string `json:"service"`
+	EventID string `json:"eventId"`
+	Text    string `json:"text"`
+	Author  string `json:"author"`
+}
+
+// EvaluationValidityEventData is the payload of the evaluation.invalidated and evaluation.revalidated events
+type EvaluationValidityEventData struct {
+	keptnv2.EventData
+	Evaluation EvaluationValidity `json:"evaluation"`
+}
+
+// EvaluationValidity describes why and by whom an evaluation has been invalidated or re-validated
+type EvaluationValidity struct {
+	EvaluationID string `json:"evaluationId"`
+	Reason       string `json:"reason"`
+	Author       string `json:"author,omitempty"`
+}
+
+// EvaluationAnnotatedEventData is the payload of the evaluation.annotated event
+type EvaluationAnnotatedEventData struct {
+	keptnv2.EventData
+	Annotation EvaluationAnnotation `json:"annotation"`
+}
+
+// EvaluationAnnotation is a free-text note on an evaluation
+type EvaluationAnnotation struct {
+	EvaluationID string `json:"evaluationId"`
+	Text         string `json:"text"`
+	Author       string `json:"author"`
+	Time         string `json:"time,omitempty"`
+}
+
+// EvaluationValidityManager invalidates, re-validates and annotates evaluations by sending the respective events.
+// The mongodb-datastore keeps track of invalidated evaluations in a dedicated collection, which is considered when retrieving
+// evaluations with excludeInvalidated=true
+type EvaluationValidityManager struct {
+	EventStore  EventStore
+	EventSender keptncommon.EventSender
+}
+
+// Invalidate invalidates the selected evaluations, i.e. they are not considered as comparison baselines anymore
+func (m *EvaluationValidityManager) Invalidate(request *EvaluationValidityRequest) (*EvaluationValidityResponse, error) {
+	return m.setValidity(request, keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName))
+}
+
+// Revalidate reverts the invalidation of the selected evaluations
+func (m *EvaluationValidityManager) Revalidate(request *EvaluationValidityRequest) (*EvaluationValidityResponse, error) {
+	return m.setValidity(request, EvaluationRevalidatedEventType)
+}
+
+func (m *EvaluationValidityManager) setValidity(request *EvaluationValidityRequest, eventType string) (*EvaluationValidityResponse, error) {
+	if request.Reason == "" || request.Author == "" {
+		return nil, fmt.Errorf("%w: reason and author must be set", ErrInvalidEvaluationSelection)
+	}
+	evaluations, err := m.getEvaluations(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EvaluationValidityResponse{Evaluations: []string{}}
+	for _, evaluation := range evaluations {
+		data := EvaluationValidityEventData{
+			EventData: keptnv2.EventData{
+				Project: request.Project,
+				Stage:   request.Stage,
+				Service: request.Service,
+			},
+			Evaluation: EvaluationValidity{
+				EvaluationID: evaluation.ID,
+				Reason:       request.Reason,
+				Author:       request.Author,
+			},
+		}
+		if err := m.sendEvent(evaluation, eventType, data); err != nil {
+			return response, fmt.Errorf("could not send %s event for evaluation %s: %w", eventType, evaluation.ID, err)
+		}
+		response.Evaluations = append(response.Evaluations, evaluation.ID)
+	}
+	return response, nil
+}
+
+// Annotate attaches a free-text annotation to an evaluation
+func (m *EvaluationValidityManager) Annotate(request *EvaluationAnnotationRequest) error {
+	if request.EventID == "" || request.Text == "" || request.Author == "" {
+		return fmt.Errorf("%w: eventId, text and author must be set", ErrInvalidEvaluationSelection)
+	}
+	evaluations, err := m.getEvaluations(&EvaluationValidityRequest{
+		Project:  request.Project,
+		Stage:    request.Stage,
+		Service:  request.Service,
+		EventIDs: []string{request.EventID},
+	})
+	if err != nil {
+		return err
+	}
+	data := EvaluationAnnotatedEventData{
+		EventData: keptnv2.EventData{
+			Project: request.Project,
+			Stage:   request.Stage,
+			Service: request.Service,
+		},
+		Annotation: EvaluationAnnotation{
+			EvaluationID: request.EventID,
+			Text:         request.Text,
+			Author:       request.Author,
+		},
+	}
+	return m.sendEvent(evaluations[0], EvaluationAnnotatedEventType, data)
+}
+
+func (m *EvaluationValidityManager) getEvaluations(request *EvaluationValidityRequest) ([]*apimodels.KeptnContextExtendedCE, error) {
+	if request.Project == "" || request.Stage == "" || request.Service == "" {
+		return nil, fmt.Errorf("%w: project, stage and service must be set", ErrInvalidEvaluationSelection)
+	}
+	filter := &keptnapi.EventFilter{
+		Project:   request.Project,
+		Stage:     request.Stage,
+		Service:   request.Service,
+		EventType: keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName),
+	}
+
+	if len(request.EventIDs) > 0 {
+		evaluations := []*apimodels.KeptnContextExtendedCE{}
+		for _, eventID := range request.EventIDs {
+			filter.EventID = eventID
+			events, errObj := m.EventStore.GetEvents(filter)
+			if errObj != nil {
+				return nil, fmt.Errorf("could not retrieve evaluation %s: %s", eventID, errObj.GetMessage())
+			}
+			events = filterLighthouseEvents(events)
+			if len(events) == 0 {
+				return nil, fmt.Errorf("%w: %s", ErrEvaluationNotFound, eventID)
+			}
+			evaluations = append(evaluations, events[0])
+		}
+		return evaluations, nil
+	}
+
+	if request.From == "" {
+		return nil, fmt.Errorf("%w: either eventIds or from must be set", ErrInvalidEvaluationSelection)
+	}
+	from, err := timeutils.ParseTimestamp(request.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not parse from: %s", ErrInvalidEvaluationSelection, err.Error())
+	}
+	to := time.Now().UTC()
+	if request.To != "" {
+		parsedTo, err := timeutils.ParseTimestamp(request.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not parse to: %s", ErrInvalidEvaluationSelection, err.Error())
+		}
+		to = *parsedTo
+	}
+	if to.Before(*from) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidEvaluationSelection)
+	}
+
+	filter.FromTime = timeutils.GetKeptnTimeStamp(*from)
+	events, errObj := m.EventStore.GetEvents(filter)
+	if errObj != nil {
+		return nil, fmt.Errorf("could not retrieve evaluations: %s", errObj.GetMessage())
+	}
+	evaluations := []*apimodels.KeptnContextExtendedCE{}
+	for _, event := range filterLighthouseEvents(events) {
+		eventTime := event.Time
+		if eventTime.Before(*from) || eventTime.After(to) {
+			continue
+		}
+		evaluations = append(evaluations, event)
+	}
+	return evaluations, nil
+}
+
+// GetAnnotations returns the annotations of an evaluation, ordered by their creation time
+func (m *EvaluationValidityManager) GetAnnotations(project, keptnContext, evaluationID string) ([]*EvaluationAnnotation, error) {
+	events, errObj := m.EventStore.GetEvents(&keptnapi.EventFilter{
+		Project:      project,
+		KeptnContext: keptnContext,
+		EventType:    EvaluationAnnotatedEventType,
+	})
+	if errObj != nil {
+		return nil, fmt.Errorf("could not retrieve annotations of evaluation %s: %s", evaluationID, errObj.GetMessage())
+	}
+	annotations := []*EvaluationAnnotation{}
+	for _, event := range events {
+		data := &EvaluationAnnotatedEventData{}
+		if err := keptnv2.Decode(event.Data, data); err != nil || data.Annotation.EvaluationID != evaluationID {
+			continue
+		}
+		data.Annotation.Time = timeutils.GetKeptnTimeStamp(event.Time)
+		annotations = append(annotations, &data.Annotation)
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].Time < annotations[j].Time
+	})
+	return annotations, nil
+}
+
+// GetInvalidation returns the latest invalidation of an evaluation, or nil if the evaluation is valid
+func (m *EvaluationValidityManager) GetInvalidation(project, keptnContext, evaluationID string) (*EvaluationValidity, error) {
+	var latest *EvaluationValidity
+	var latestTime time.Time
+	for _, eventType := range []string{keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName), EvaluationRevalidatedEventType} {
+		events, errObj := m.EventStore.GetEvents(&keptnapi.EventFilter{
+			Project:      project,
+			KeptnContext: keptnContext,
+			EventType:    eventType,
+		})
+		if errObj != nil {
+			return nil, fmt.Errorf("could not retrieve validity of evaluation %s: %s", evaluationID, errObj.GetMessage())
+		}
+		for _, event := range events {
+			data := &EvaluationValidityEventData{}
+			if err := keptnv2.Decode(event.Data, data); err != nil {
+				continue
+			}
+			// invalidations sent via the API or the bridge only reference the triggeredid of the evaluation
+			if data.Evaluation.EvaluationID != "" && data.Evaluation.EvaluationID != evaluationID {
+				continue
+			}
+			eventTime := event.Time
+			if latest != nil && !eventTime.After(latestTime) {
+				continue
+			}
+			latestTime = eventTime
+			if eventType == EvaluationRevalidatedEventType {
+				latest = nil
+				continue
+			}
+			latest = &data.Evaluation
+		}
+	}
+	return latest, nil
+}
+
+func (m *EvaluationValidityManager) sendEvent(evaluation *apimodels.KeptnContextExtendedCE, eventType string, data interface{}) error {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetType(eventType)
+	event.SetSource("lighthouse-service")
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", evaluation.Shkeptncontext)
+	event.SetExtension("triggeredid", evaluation.Triggeredid)
+	event.SetExtension("gitcommitid", evaluation.GitCommitID)
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return err
+	}
+
+	logger.Debugf("Send event %s for evaluation %s", eventType, evaluation.ID)
+	return m.EventSender.SendEvent(event)
+}
+
+func filterLighthouseEvents(events []*apimodels.KeptnContextExtendedCE) []*apimodels.KeptnContextExtendedCE {
+	result := []*apimodels.KeptnContextExtendedCE{}
+	for _, event := range events {
+		if event.Source != nil && *event.Source == "lighthouse-service" {
+			result = append(result, event)
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	logger "github.com/sirupsen/logrus"
+)
+
+// EvaluationInvalidationEndpoint is the path of the endpoint that invalidates evaluations
+const EvaluationInvalidationEndpoint = "/v1/evaluation/invalidate"
+
+// EvaluationRevalidationEndpoint is the path of the endpoint that reverts the invalidation of evaluations
+const EvaluationRevalidationEndpoint = "/v1/evaluation/revalidate"
+
+// EvaluationAnnotationEndpoint is the path of the endpoint that annotates an evaluation
+const EvaluationAnnotationEndpoint = "/v1/evaluation/annotate"
+
+// EvaluationValidityHandler serves the POST endpoints for invalidating, re-validating and annotating evaluations
+type EvaluationValidityHandler struct {
+	Manager *EvaluationValidityManager
+}
+
+func NewEvaluationValidityHandler() (*EvaluationValidityHandler, error) {
+	eventSender, err := keptnv2.NewHTTPEventSender("")
+	if err != nil {
+		return nil, err
+	}
+	return &EvaluationValidityHandler{
+		Manager: &EvaluationValidityManager{
+			EventStore:  keptnapi.NewEventHandler(getDatastoreURL()),
+			EventSender: eventSender,
+		},
+	}, nil
+}
+
+func (h *EvaluationValidityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var response interface{}
+	var err error
+	switch r.URL.Path {
+	case EvaluationAnnotationEndpoint:
+		request := &EvaluationAnnotationRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "could not parse request: "+err.Error())
+			return
+		}
+		err = h.Manager.Annotate(request)
+		response = request
+	case EvaluationInvalidationEndpoint, EvaluationRevalidationEndpoint:
+		request := &EvaluationValidityRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "could not parse request: "+err.Error())
+			return
+		}
+		if r.URL.Path == EvaluationInvalidationEndpoint {
+			response, err = h.Manager.Invalidate(request)
+		} else {
+			response, err = h.Manager.Revalidate(request)
+		}
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidEvaluationSelection):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrEvaluationNotFound):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.WithError(err).Error("could not write evaluation validity response")
+	}
+}
@@ -0,0 +1,237 @@
+package event_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	"github.com/keptn/go-utils/pkg/common/strutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	keptnfake "github.com/keptn/go-utils/pkg/lib/v0_2_0/fake"
+	event_handler_mock "github.com/keptn/keptn/lighthouse-service/event_handler/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getValidityTestEvaluation(id string, eventTime time.Time) *models.KeptnContextExtendedCE {
+	return &models.KeptnContextExtendedCE{
+		ID:             id,
+		Source:         strutils.Stringp("lighthouse-service"),
+		Shkeptncontext: "context-" + id,
+		Triggeredid:    "triggered-" + id,
+		Time:           eventTime,
+		Type:           strutils.Stringp(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName)),
+		Data:           keptnv2.EvaluationFinishedEventData{EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts"}},
+	}
+}
+
+func newEvaluationValidityManager(events ...*models.KeptnContextExtendedCE) (*EvaluationValidityManager, *keptnfake.EventSender) {
+	sender := &keptnfake.EventSender{}
+	return &EvaluationValidityManager{
+		EventStore: &event_handler_mock.EventStoreMock{
+			GetEventsFunc: func(filter *keptnapi.EventFilter) ([]*models.KeptnContextExtendedCE, *models.Error) {
+				result := []*models.KeptnContextExtendedCE{}
+				for _, event := range events {
+					if *event.Type != filter.EventType {
+						continue
+					}
+					if filter.EventID != "" && event.ID != filter.EventID {
+						continue
+					}
+					if filter.KeptnContext != "" && event.Shkeptncontext != filter.KeptnContext {
+						continue
+					}
+					result = append(result, event)
+				}
+				return result, nil
+			},
+		},
+		EventSender: sender,
+	}, sender
+}
+
+func TestEvaluationValidityManager_Invalidate(t *testing.T) {
+	now := time.Now().UTC()
+	manager, sender := newEvaluationValidityManager(
+		getValidityTestEvaluation("evaluation-1", now.Add(-3*time.Hour)),
+		getValidityTestEvaluation("evaluation-2", now.Add(-90*time.Minute)),
+		getValidityTestEvaluation("evaluation-3", now.Add(-30*time.Minute)),
+	)
+
+	tests := []struct {
+		name    string
+		request *EvaluationValidityRequest
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "by event ID",
+			request: &EvaluationValidityRequest{EventIDs: []string{"evaluation-2"}},
+			want:    []string{"evaluation-2"},
+		},
+		{
+			name: "by time range",
+			request: &EvaluationValidityRequest{
+				From: now.Add(-2 * time.Hour).Format(time.RFC3339),
+				To:   now.Add(-time.Hour).Format(time.RFC3339),
+			},
+			want: []string{"evaluation-2"},
+		},
+		{
+			name:    "open time range",
+			request: &EvaluationValidityRequest{From: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+			want:    []string{"evaluation-2", "evaluation-3"},
+		},
+		{
+			name:    "unknown event ID",
+			request: &EvaluationValidityRequest{EventIDs: []string{"unknown"}},
+			wantErr: ErrEvaluationNotFound,
+		},
+		{
+			name:    "no selection",
+			request: &EvaluationValidityRequest{},
+			wantErr: ErrInvalidEvaluationSelection,
+		},
+		{
+			name:    "no reason",
+			request: &EvaluationValidityRequest{EventIDs: []string{"evaluation-2"}, Reason: "-"},
+			wantErr: ErrInvalidEvaluationSelection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender.SentEvents = nil
+			tt.request.Project, tt.request.Stage, tt.request.Service = "sockshop", "dev", "carts"
+			if tt.request.Reason == "" {
+				tt.request.Reason = "load test was misconfigured"
+				tt.request.Author = "jane"
+			}
+
+			got, err := manager.Invalidate(tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, sender.SentEvents)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, got.Evaluations)
+			require.Len(t, sender.SentEvents, len(tt.want))
+
+			sentEvent := sender.SentEvents[0]
+			assert.Equal(t, keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName), sentEvent.Type())
+			assert.Equal(t, "context-"+tt.want[0], sentEvent.Extensions()["shkeptncontext"])
+			assert.Equal(t, "triggered-"+tt.want[0], sentEvent.Extensions()["triggeredid"])
+			data := &EvaluationValidityEventData{}
+			require.Nil(t, sentEvent.DataAs(data))
+			assert.Equal(t, EvaluationValidity{EvaluationID: tt.want[0], Reason: "load test was misconfigured", Author: "jane"}, data.Evaluation)
+		})
+	}
+}
+
+func TestEvaluationValidityManager_GetInvalidation(t *testing.T) {
+	now := time.Now().UTC()
+	invalidated := &models.KeptnContextExtendedCE{
+		Shkeptncontext: "context-evaluation-1",
+		Time:           now.Add(-time.Hour),
+		Type:           strutils.Stringp(keptnv2.GetInvalidatedEventType(keptnv2.EvaluationTaskName)),
+		Data:           EvaluationValidityEventData{Evaluation: EvaluationValidity{EvaluationID: "evaluation-1", Reason: "outage", Author: "jane"}},
+	}
+	revalidated := &models.KeptnContextExtendedCE{
+		Shkeptncontext: "context-evaluation-1",
+		Time:           now,
+		Type:           strutils.Stringp(EvaluationRevalidatedEventType),
+		Data:           EvaluationValidityEventData{Evaluation: EvaluationValidity{EvaluationID: "evaluation-1", Reason: "false alarm", Author: "john"}},
+	}
+
+	manager, _ := newEvaluationValidityManager(invalidated)
+	invalidation, err := manager.GetInvalidation("sockshop", "context-evaluation-1", "evaluation-1")
+	require.Nil(t, err)
+	assert.Equal(t, &EvaluationValidity{EvaluationID: "evaluation-1", Reason: "outage", Author: "jane"}, invalidation)
+
+	manager, _ = newEvaluationValidityManager(invalidated, revalidated)
+	invalidation, err = manager.GetInvalidation("sockshop", "context-evaluation-1", "evaluation-1")
+	require.Nil(t, err)
+	assert.Nil(t, invalidation)
+}
+
+func TestEvaluationValidityManager_Annotate(t *testing.T) {
+	manager, sender := newEvaluationValidityManager(getValidityTestEvaluation("evaluation-1", time.Now()))
+
+	err := manager.Annotate(&EvaluationAnnotationRequest{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+		EventID: "evaluation-1",
+		Text:    "new database version",
+		Author:  "jane",
+	})
+	require.Nil(t, err)
+	require.Len(t, sender.SentEvents, 1)
+	assert.Equal(t, EvaluationAnnotatedEventType, sender.SentEvents[0].Type())
+	data := &EvaluationAnnotatedEventData{}
+	require.Nil(t, sender.SentEvents[0].DataAs(data))
+	assert.Equal(t, EvaluationAnnotation{EvaluationID: "evaluation-1", Text: "new database version", Author: "jane"}, data.Annotation)
+
+	err = manager.Annotate(&EvaluationAnnotationRequest{Project: "sockshop", Stage: "dev", Service: "carts", EventID: "evaluation-1"})
+	assert.ErrorIs(t, err, ErrInvalidEvaluationSelection)
+}
+
+func TestEvaluationComparer_CompareWithAnnotations(t *testing.T) {
+	comparer := newEvaluationComparer()
+	store := comparer.EventStore.(*event_handler_mock.EventStoreMock)
+	getEvents := store.GetEventsFunc
+	store.GetEventsFunc = func(filter *keptnapi.EventFilter) ([]*models.KeptnContextExtendedCE, *models.Error) {
+		if filter.EventType == EvaluationAnnotatedEventType && filter.KeptnContext == "build-42" {
+			return []*models.KeptnContextExtendedCE{
+				{Data: EvaluationAnnotatedEventData{Annotation: EvaluationAnnotation{EvaluationID: "evaluation-42", Text: "new database version", Author: "jane"}}},
+				{Data: EvaluationAnnotatedEventData{Annotation: EvaluationAnnotation{EvaluationID: "other-evaluation", Text: "other"}}},
+			}, nil
+		}
+		if filter.EventType == keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName) {
+			return getEvents(filter)
+		}
+		return nil, nil
+	}
+
+	comparison, err := comparer.Compare("build-41", "build-42", true)
+	require.Nil(t, err)
+	assert.Empty(t, comparison.From.Annotations)
+	require.Len(t, comparison.To.Annotations, 1)
+	assert.Equal(t, "new database version", comparison.To.Annotations[0].Text)
+	assert.Nil(t, comparison.To.Invalidation)
+}
+
+func TestEvaluationValidityHandler_ServeHTTP(t *testing.T) {
+	manager, sender := newEvaluationValidityManager(getValidityTestEvaluation("evaluation-1", time.Now()))
+	handler := &EvaluationValidityHandler{Manager: manager}
+
+	body, _ := json.Marshal(&EvaluationValidityRequest{
+		Project:  "sockshop",
+		Stage:    "dev",
+		Service:  "carts",
+		EventIDs: []string{"evaluation-1"},
+		Reason:   "false alarm",
+		Author:   "jane",
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, EvaluationRevalidationEndpoint, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	response := &EvaluationValidityResponse{}
+	require.Nil(t, json.NewDecoder(rec.Body).Decode(response))
+	assert.Equal(t, []string{"evaluation-1"}, response.Evaluations)
+	require.Len(t, sender.SentEvents, 1)
+	assert.Equal(t, EvaluationRevalidatedEventType, sender.SentEvents[0].Type())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, EvaluationInvalidationEndpoint, bytes.NewReader([]byte(`{"project":"sockshop"}`))))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EvaluationInvalidationEndpoint, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
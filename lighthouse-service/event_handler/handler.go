@@ -31,17 +31,22 @@ func NewEventHandler(event cloudevents.Event) (EvaluationEventHandler, error) {
 	}
 	resourceHandler := keptnapi.NewResourceHandler(configurationServiceEndpoint.String())
 	serviceHandler := keptnapi.NewServiceHandler(configurationServiceEndpoint.String())
+	sliProviderConfig, err := NewSLIProviderConfig(resourceHandler)
+	if err != nil {
+		return nil, err
+	}
 
 	switch event.Type() {
 	case keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName):
 		return &StartEvaluationHandler{
 			Event:             event,
 			KeptnHandler:      keptnHandler,
-			SLIProviderConfig: K8sSLIProviderConfig{},
+			SLIProviderConfig: sliProviderConfig,
 			SLOFileRetriever: SLOFileRetriever{
 				ResourceHandler: resourceHandler,
 				ServiceHandler:  serviceHandler,
 			},
+			SLIProviders: NewLocalSLIProviders(resourceHandler, &http.Client{}),
 		}, nil
 	case keptnv2.GetFinishedEventType(keptnv2.GetSLITaskName):
 		return &EvaluateSLIHandler{
@@ -55,7 +60,7 @@ func NewEventHandler(event cloudevents.Event) (EvaluationEventHandler, error) {
 			EventStore: keptnHandler.EventHandler,
 		}, nil
 	case keptn.ConfigureMonitoringEventType:
-		return NewConfigureMonitoringHandler(event, logger.StandardLogger())
+		return NewConfigureMonitoringHandler(event, logger.StandardLogger(), WithSLIProviderConfig(sliProviderConfig))
 	default:
 		logger.Info("received unhandled event type")
 		return nil, nil
@@ -11,6 +11,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-test/deep"
 
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptn "github.com/keptn/go-utils/pkg/lib"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
@@ -22,6 +23,12 @@ func TestNewEventHandler(t *testing.T) {
 	incomingEvent.SetSource("my-source")
 
 	keptnHandler, _ := keptnv2.NewKeptn(&incomingEvent, keptncommon.KeptnOpts{})
+	sliProviderConfig := &MigratingSLIProviderConfig{
+		Target: &ResourceSLIProviderConfig{
+			ResourceHandler: keptnapi.NewResourceHandler(configurationServiceURL),
+		},
+		Source: &K8sSLIProviderConfig{},
+	}
 
 	type args struct {
 		event cloudevents.Event
@@ -42,7 +49,7 @@ func TestNewEventHandler(t *testing.T) {
 			want: &StartEvaluationHandler{
 				Event:             incomingEvent,
 				KeptnHandler:      keptnHandler,
-				SLIProviderConfig: K8sSLIProviderConfig{},
+				SLIProviderConfig: sliProviderConfig,
 			},
 			wantErr: false,
 		},
@@ -67,9 +74,9 @@ func TestNewEventHandler(t *testing.T) {
 			},
 			eventType: keptn.ConfigureMonitoringEventType,
 			want: &ConfigureMonitoringHandler{
-				Event:     incomingEvent,
-				Logger:    logrus.New(),
-				K8sClient: fake.NewSimpleClientset(),
+				Event:             incomingEvent,
+				Logger:            logrus.New(),
+				SLIProviderConfig: sliProviderConfig,
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,285 @@
+package event_handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keptn/go-utils/pkg/common/timeutils"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"gopkg.in/yaml.v3"
+
+	utils "github.com/keptn/go-utils/pkg/api/utils"
+)
+
+// FileSLIProviderName is the name of the built-in SLI provider that reads SLI values from a file stored in the resource-service
+const FileSLIProviderName = "lighthouse-file"
+
+// PrometheusSLIProviderName is the name of the built-in SLI provider that queries a Prometheus-compatible HTTP API
+const PrometheusSLIProviderName = "lighthouse-prometheus"
+
+const prometheusEndpointEnvVar = "PROMETHEUS_ENDPOINT"
+
+// sliValueFiles are the resources the FileSLIProvider looks for, in this order
+var sliValueFiles = []string{"sli-values.yaml", "sli-values.json", "sli-values.csv"}
+
+// ErrNoSLIValuesFile is returned if none of the sliValueFiles is available for a service
+var ErrNoSLIValuesFile = errors.New("no SLI values file available")
+
+// SLIProvider retrieves SLI values in-process, without sending a get-sli.triggered event to an external SLI provider
+type SLIProvider interface {
+	GetSLIValues(request *keptnv2.GetSLITriggeredEventData) ([]*keptnv2.SLIResult, error)
+}
+
+// SLIProviders maps the names of SLI providers to their in-process implementation
+type SLIProviders map[string]SLIProvider
+
+// NewLocalSLIProviders returns the built-in SLI providers. The Prometheus provider is only available if the PROMETHEUS_ENDPOINT env var is set
+func NewLocalSLIProviders(resourceHandler ResourceHandler, httpClient *http.Client) SLIProviders {
+	providers := SLIProviders{
+		FileSLIProviderName: &FileSLIProvider{ResourceHandler: resourceHandler},
+	}
+	if endpoint := os.Getenv(prometheusEndpointEnvVar); endpoint != "" {
+		providers[PrometheusSLIProviderName] = &PrometheusSLIProvider{
+			Endpoint:        endpoint,
+			HTTPClient:      httpClient,
+			ResourceHandler: resourceHandler,
+		}
+	}
+	return providers
+}
+
+// FileSLIProvider reads static SLI values from a YAML, JSON or CSV file of the service, e.g. sli-values.yaml:
+//
+//   response_time_p95: 250
+//   error_rate: 0
+//
+// or sli-values.csv:
+//
+//   response_time_p95,250
+//   error_rate,0
+type FileSLIProvider struct {
+	ResourceHandler ResourceHandler
+}
+
+func (p *FileSLIProvider) GetSLIValues(request *keptnv2.GetSLITriggeredEventData) ([]*keptnv2.SLIResult, error) {
+	for _, file := range sliValueFiles {
+		content, err := getServiceResource(p.ResourceHandler, request.Project, request.Stage, request.Service, file)
+		if err != nil || content == "" {
+			continue
+		}
+		values, err := parseSLIValues(file, content)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", file, err)
+		}
+		return toSLIResults(request.GetSLI.Indicators, values), nil
+	}
+	return nil, ErrNoSLIValuesFile
+}
+
+func parseSLIValues(file string, content string) (map[string]float64, error) {
+	values := map[string]float64{}
+	if path.Ext(file) != ".csv" {
+		// YAML is a superset of JSON, so both formats can be parsed the same way
+		if err := yaml.Unmarshal([]byte(content), &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("invalid record %v: expected metric and value", record)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			// allow a header line
+			continue
+		}
+		values[strings.TrimSpace(record[0])] = value
+	}
+	return values, nil
+}
+
+func toSLIResults(indicators []string, values map[string]float64) []*keptnv2.SLIResult {
+	results := []*keptnv2.SLIResult{}
+	for _, indicator := range indicators {
+		value, ok := values[indicator]
+		if !ok {
+			results = append(results, &keptnv2.SLIResult{
+				Metric:  indicator,
+				Success: false,
+				Message: "no value available for SLI " + indicator,
+			})
+			continue
+		}
+		results = append(results, &keptnv2.SLIResult{
+			Metric:  indicator,
+			Value:   value,
+			Success: true,
+		})
+	}
+	return results
+}
+
+// PrometheusSLIProvider executes the queries defined in the sli.yaml file of the service against a Prometheus-compatible HTTP API:
+//
+//   spec_version: '1.0'
+//   indicators:
+//     response_time_p95: histogram_quantile(0.95, sum(rate(http_response_time_bucket{job='$SERVICE-$PROJECT-$STAGE'}[$DURATION_SECONDS])) by (le))
+type PrometheusSLIProvider struct {
+	Endpoint        string
+	HTTPClient      *http.Client
+	ResourceHandler ResourceHandler
+}
+
+type sliConfig struct {
+	Indicators map[string]string `yaml:"indicators"`
+}
+
+type prometheusQueryResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (p *PrometheusSLIProvider) GetSLIValues(request *keptnv2.GetSLITriggeredEventData) ([]*keptnv2.SLIResult, error) {
+	content, err := getServiceResource(p.ResourceHandler, request.Project, request.Stage, request.Service, "sli.yaml")
+	if err != nil || content == "" {
+		return nil, errors.New("no sli.yaml file available")
+	}
+	config := &sliConfig{}
+	if err := yaml.Unmarshal([]byte(content), config); err != nil {
+		return nil, fmt.Errorf("could not parse sli.yaml: %w", err)
+	}
+
+	start, err := timeutils.ParseTimestamp(request.GetSLI.Start)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse start timestamp: %w", err)
+	}
+	end, err := timeutils.ParseTimestamp(request.GetSLI.End)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse end timestamp: %w", err)
+	}
+
+	placeholders := map[string]string{
+		"$PROJECT":          request.Project,
+		"$STAGE":            request.Stage,
+		"$SERVICE":          request.Service,
+		"$DEPLOYMENT":       request.Deployment,
+		"$DURATION_SECONDS": fmt.Sprintf("%.0fs", end.Sub(*start).Seconds()),
+	}
+	for _, filter := range request.GetSLI.CustomFilters {
+		placeholders["$"+strings.ToUpper(filter.Key)] = filter.Value
+	}
+	replacer := newPlaceholderReplacer(placeholders)
+
+	results := []*keptnv2.SLIResult{}
+	for _, indicator := range request.GetSLI.Indicators {
+		query, ok := config.Indicators[indicator]
+		if !ok {
+			results = append(results, &keptnv2.SLIResult{
+				Metric:  indicator,
+				Success: false,
+				Message: "no query defined for SLI " + indicator,
+			})
+			continue
+		}
+		value, err := p.query(replacer.Replace(query), end.Unix())
+		if err != nil {
+			results = append(results, &keptnv2.SLIResult{
+				Metric:  indicator,
+				Success: false,
+				Message: err.Error(),
+			})
+			continue
+		}
+		results = append(results, &keptnv2.SLIResult{
+			Metric:  indicator,
+			Value:   value,
+			Success: true,
+		})
+	}
+	return results, nil
+}
+
+// newPlaceholderReplacer replaces longer placeholders first, so that e.g. $STAGE_NAME is not replaced as $STAGE
+func newPlaceholderReplacer(placeholders map[string]string) *strings.Replacer {
+	keys := make([]string, 0, len(placeholders))
+	for placeholder := range placeholders {
+		keys = append(keys, placeholder)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	oldnew := make([]string, 0, 2*len(keys))
+	for _, placeholder := range keys {
+		oldnew = append(oldnew, placeholder, placeholders[placeholder])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+func (p *PrometheusSLIProvider) query(query string, timestamp int64) (float64, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("time", strconv.FormatInt(timestamp, 10))
+
+	resp, err := p.HTTPClient.Get(strings.TrimSuffix(p.Endpoint, "/") + "/api/v1/query?" + params.Encode())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	result := &prometheusQueryResult{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return 0, fmt.Errorf("could not parse query result: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Status != "success" {
+		return 0, fmt.Errorf("query failed with status %d: %s", resp.StatusCode, result.Error)
+	}
+	if len(result.Data.Result) != 1 {
+		return 0, fmt.Errorf("query returned %d results, expected exactly one", len(result.Data.Result))
+	}
+	if len(result.Data.Result[0].Value) != 2 {
+		return 0, errors.New("query returned an invalid value")
+	}
+	value, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, errors.New("query returned an invalid value")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func getServiceResource(resourceHandler ResourceHandler, project, stage, service, resourceURI string) (string, error) {
+	resourceScope := *utils.NewResourceScope().Project(project).Stage(stage).Service(service).Resource(resourceURI)
+	resource, err := resourceHandler.GetResource(resourceScope)
+	if err != nil {
+		return "", err
+	}
+	if resource == nil {
+		return "", nil
+	}
+	return resource.ResourceContent, nil
+}
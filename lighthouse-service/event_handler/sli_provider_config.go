@@ -0,0 +1,332 @@
+package event_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	apimodels "github.com/keptn/go-utils/pkg/api/models"
+	utils "github.com/keptn/go-utils/pkg/api/utils"
+	logger "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ResourceSLIProviderConfigBackend stores the SLI provider of a project in a project-level resource of the resource-service
+	ResourceSLIProviderConfigBackend = "resource-service"
+	// FileSLIProviderConfigBackend stores the SLI providers of all projects in a local file
+	FileSLIProviderConfigBackend = "file"
+	// K8sSLIProviderConfigBackend stores the SLI provider of a project in the ConfigMap lighthouse-config-<project>
+	K8sSLIProviderConfigBackend = "kubernetes"
+)
+
+const sliProviderConfigBackendEnvVar = "SLI_PROVIDER_CONFIG_BACKEND"
+const sliProviderConfigFileEnvVar = "SLI_PROVIDER_CONFIG_FILE"
+const defaultSLIProviderEnvVar = "DEFAULT_SLI_PROVIDER"
+
+// sliProviderConfigResource is the project-level resource used by the ResourceSLIProviderConfig
+const sliProviderConfigResource = "lighthouse-config.yaml"
+
+const sliProviderKey = "sli-provider"
+
+// ErrSLIProviderNotConfigured is returned if no SLI provider has been configured for a project
+var ErrSLIProviderNotConfigured = errors.New("no SLI provider configured")
+
+// SLIProviderConfig stores the SLI provider that has been configured for a project via the configure-monitoring event
+type SLIProviderConfig interface {
+	GetDefaultSLIProvider() (string, error)
+	GetSLIProvider(project string) (string, error)
+	SetSLIProvider(project string, sliProvider string) error
+}
+
+//go:generate moq -pkg event_handler_mock -skip-ensure -out ./fake/project_resource_handler_mock.go . ProjectResourceHandler
+type ProjectResourceHandler interface {
+	GetProjectResource(project string, resourceURI string) (*apimodels.Resource, error)
+	UpdateProjectResource(project string, resource *apimodels.Resource) (string, error)
+}
+
+// NewSLIProviderConfig returns the SLIProviderConfig backend selected by the SLI_PROVIDER_CONFIG_BACKEND env var (default: resource-service).
+// SLI providers that are only stored in a ConfigMap are migrated to the selected backend once they are read
+func NewSLIProviderConfig(resourceHandler ProjectResourceHandler) (SLIProviderConfig, error) {
+	var sliProviderConfig SLIProviderConfig
+	switch backend := os.Getenv(sliProviderConfigBackendEnvVar); backend {
+	case "", ResourceSLIProviderConfigBackend:
+		sliProviderConfig = &ResourceSLIProviderConfig{
+			ResourceHandler:    resourceHandler,
+			DefaultSLIProvider: os.Getenv(defaultSLIProviderEnvVar),
+		}
+	case FileSLIProviderConfigBackend:
+		path := os.Getenv(sliProviderConfigFileEnvVar)
+		if path == "" {
+			return nil, fmt.Errorf("%s must be set for the %s backend", sliProviderConfigFileEnvVar, FileSLIProviderConfigBackend)
+		}
+		sliProviderConfig = &FileSLIProviderConfig{Path: path}
+	case K8sSLIProviderConfigBackend:
+		return &K8sSLIProviderConfig{}, nil
+	default:
+		return nil, fmt.Errorf("unknown SLI provider config backend %s", backend)
+	}
+	return &MigratingSLIProviderConfig{Target: sliProviderConfig, Source: &K8sSLIProviderConfig{}}, nil
+}
+
+// ResourceSLIProviderConfig stores the SLI provider of a project in the project-level resource lighthouse-config.yaml:
+//
+//   sli-provider: prometheus
+type ResourceSLIProviderConfig struct {
+	ResourceHandler ProjectResourceHandler
+	// DefaultSLIProvider is used for projects without a configured SLI provider
+	DefaultSLIProvider string
+}
+
+func (c *ResourceSLIProviderConfig) GetDefaultSLIProvider() (string, error) {
+	if c.DefaultSLIProvider == "" {
+		return "", errors.New("no default SLI provider specified")
+	}
+	return c.DefaultSLIProvider, nil
+}
+
+func (c *ResourceSLIProviderConfig) GetSLIProvider(project string) (string, error) {
+	resource, err := c.ResourceHandler.GetProjectResource(project, sliProviderConfigResource)
+	if err != nil {
+		if errors.Is(err, utils.ResourceNotFoundError) {
+			return "", fmt.Errorf("%w for project %s", ErrSLIProviderNotConfigured, project)
+		}
+		return "", fmt.Errorf("could not retrieve %s of project %s: %w", sliProviderConfigResource, project, err)
+	}
+
+	config := map[string]string{}
+	if err := yaml.Unmarshal([]byte(resource.ResourceContent), &config); err != nil {
+		return "", fmt.Errorf("could not parse %s of project %s: %w", sliProviderConfigResource, project, err)
+	}
+	if config[sliProviderKey] == "" {
+		return "", fmt.Errorf("%w for project %s", ErrSLIProviderNotConfigured, project)
+	}
+	return config[sliProviderKey], nil
+}
+
+func (c *ResourceSLIProviderConfig) SetSLIProvider(project string, sliProvider string) error {
+	content, err := yaml.Marshal(map[string]string{sliProviderKey: sliProvider})
+	if err != nil {
+		return err
+	}
+	resourceURI := sliProviderConfigResource
+	_, err = c.ResourceHandler.UpdateProjectResource(project, &apimodels.Resource{
+		ResourceURI:     &resourceURI,
+		ResourceContent: string(content),
+	})
+	if err != nil {
+		return fmt.Errorf("could not store %s of project %s: %w", sliProviderConfigResource, project, err)
+	}
+	return nil
+}
+
+// FileSLIProviderConfig stores the SLI providers in a local YAML file, which allows running the lighthouse-service outside of a cluster:
+//
+//   sli-provider: prometheus
+//   projects:
+//     sockshop: dynatrace
+type FileSLIProviderConfig struct {
+	Path string
+}
+
+type sliProviderConfigFile struct {
+	DefaultSLIProvider string            `yaml:"sli-provider,omitempty"`
+	Projects           map[string]string `yaml:"projects"`
+}
+
+// sliProviderConfigFileMutex synchronizes the access to the file, since a FileSLIProviderConfig is created per event
+var sliProviderConfigFileMutex sync.Mutex
+
+func (c *FileSLIProviderConfig) GetDefaultSLIProvider() (string, error) {
+	sliProviderConfigFileMutex.Lock()
+	defer sliProviderConfigFileMutex.Unlock()
+
+	config, err := c.read()
+	if err != nil {
+		return "", err
+	}
+	if config.DefaultSLIProvider == "" {
+		return "", errors.New("no default SLI provider specified")
+	}
+	return config.DefaultSLIProvider, nil
+}
+
+func (c *FileSLIProviderConfig) GetSLIProvider(project string) (string, error) {
+	sliProviderConfigFileMutex.Lock()
+	defer sliProviderConfigFileMutex.Unlock()
+
+	config, err := c.read()
+	if err != nil {
+		return "", err
+	}
+	if config.Projects[project] == "" {
+		return "", fmt.Errorf("%w for project %s", ErrSLIProviderNotConfigured, project)
+	}
+	return config.Projects[project], nil
+}
+
+func (c *FileSLIProviderConfig) SetSLIProvider(project string, sliProvider string) error {
+	sliProviderConfigFileMutex.Lock()
+	defer sliProviderConfigFileMutex.Unlock()
+
+	config, err := c.read()
+	if err != nil {
+		return err
+	}
+	config.Projects[project] = sliProvider
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(c.Path, content, 0600); err != nil {
+		return fmt.Errorf("could not write %s: %w", c.Path, err)
+	}
+	return nil
+}
+
+func (c *FileSLIProviderConfig) read() (*sliProviderConfigFile, error) {
+	config := &sliProviderConfigFile{}
+	content, err := ioutil.ReadFile(c.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read %s: %w", c.Path, err)
+	}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", c.Path, err)
+	}
+	if config.Projects == nil {
+		config.Projects = map[string]string{}
+	}
+	return config, nil
+}
+
+// K8sSLIProviderConfig stores the SLI provider of a project in the ConfigMap lighthouse-config-<project>,
+// and reads the default SLI provider from the ConfigMap lighthouse-config
+type K8sSLIProviderConfig struct {
+	K8sClient kubernetes.Interface
+}
+
+func (c *K8sSLIProviderConfig) kubeAPI() (kubernetes.Interface, error) {
+	if c.K8sClient != nil {
+		return c.K8sClient, nil
+	}
+	return GetConfig().GetKubeAPI()
+}
+
+// GetDefaultSLIProvider godoc
+func (c *K8sSLIProviderConfig) GetDefaultSLIProvider() (string, error) {
+	kubeAPI, err := c.kubeAPI()
+	if err != nil {
+		return "", err
+	}
+
+	configMap, err := kubeAPI.CoreV1().ConfigMaps(namespace).Get(context.TODO(), "lighthouse-config", metav1.GetOptions{})
+
+	if err != nil {
+		return "", errors.New("no default SLI provider specified")
+	}
+
+	sliProvider := configMap.Data[sliProviderKey]
+
+	return sliProvider, nil
+}
+
+// GetSLIProvider godoc
+func (c *K8sSLIProviderConfig) GetSLIProvider(project string) (string, error) {
+	kubeAPI, err := c.kubeAPI()
+	if err != nil {
+		return "", err
+	}
+
+	configMap, err := kubeAPI.CoreV1().ConfigMaps(namespace).Get(context.TODO(), "lighthouse-config-"+project, metav1.GetOptions{})
+
+	if err != nil {
+		return "", fmt.Errorf("%w for project %s", ErrSLIProviderNotConfigured, project)
+	}
+
+	sliProvider := configMap.Data[sliProviderKey]
+
+	return sliProvider, nil
+}
+
+// SetSLIProvider creates or updates the ConfigMap of the project
+func (c *K8sSLIProviderConfig) SetSLIProvider(project string, sliProvider string) error {
+	kubeAPI, err := c.kubeAPI()
+	if err != nil {
+		return err
+	}
+
+	configMap := getSLISourceConfigMap(project, sliProvider)
+
+	_, err = kubeAPI.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+
+	if err != nil && k8serrors.IsAlreadyExists(err) {
+		_, err = kubeAPI.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("could not update sli-provider ConfigMap: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("could not create sli-provider ConfigMap: %w", err)
+	}
+	return nil
+}
+
+func getSLISourceConfigMap(project string, sliProvider string) *v1.ConfigMap {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lighthouse-config-" + project,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			sliProviderKey: sliProvider,
+		},
+	}
+	return configMap
+}
+
+// MigratingSLIProviderConfig reads SLI providers from the Target backend. SLI providers that are not available in the Target
+// are looked up in the Source (i.e. the ConfigMaps used by previous versions of the lighthouse-service) and copied to the Target
+type MigratingSLIProviderConfig struct {
+	Target SLIProviderConfig
+	Source SLIProviderConfig
+}
+
+func (c *MigratingSLIProviderConfig) GetDefaultSLIProvider() (string, error) {
+	sliProvider, err := c.Target.GetDefaultSLIProvider()
+	if err == nil {
+		return sliProvider, nil
+	}
+	if sourceSLIProvider, sourceErr := c.Source.GetDefaultSLIProvider(); sourceErr == nil && sourceSLIProvider != "" {
+		return sourceSLIProvider, nil
+	}
+	return "", err
+}
+
+func (c *MigratingSLIProviderConfig) GetSLIProvider(project string) (string, error) {
+	sliProvider, err := c.Target.GetSLIProvider(project)
+	if err == nil || !errors.Is(err, ErrSLIProviderNotConfigured) {
+		return sliProvider, err
+	}
+
+	sourceSLIProvider, sourceErr := c.Source.GetSLIProvider(project)
+	if sourceErr != nil || sourceSLIProvider == "" {
+		return "", err
+	}
+
+	logger.Infof("Migrating SLI provider %s of project %s", sourceSLIProvider, project)
+	if err := c.Target.SetSLIProvider(project, sourceSLIProvider); err != nil {
+		logger.WithError(err).Errorf("could not migrate SLI provider of project %s", project)
+	}
+	return sourceSLIProvider, nil
+}
+
+func (c *MigratingSLIProviderConfig) SetSLIProvider(project string, sliProvider string) error {
+	return c.Target.SetSLIProvider(project, sliProvider)
+}
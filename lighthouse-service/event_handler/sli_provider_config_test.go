@@ -0,0 +1,252 @@
+package event_handler
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	apimodels "github.com/keptn/go-utils/pkg/api/models"
+	utils "github.com/keptn/go-utils/pkg/api/utils"
+	event_handler_mock "github.com/keptn/keptn/lighthouse-service/event_handler/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newProjectResourceHandlerMock(resources map[string]string) *event_handler_mock.ProjectResourceHandlerMock {
+	return &event_handler_mock.ProjectResourceHandlerMock{
+		GetProjectResourceFunc: func(project string, resourceURI string) (*apimodels.Resource, error) {
+			content, ok := resources[project+"/"+resourceURI]
+			if !ok {
+				return nil, utils.ResourceNotFoundError
+			}
+			return &apimodels.Resource{ResourceURI: &resourceURI, ResourceContent: content}, nil
+		},
+		UpdateProjectResourceFunc: func(project string, resource *apimodels.Resource) (string, error) {
+			resources[project+"/"+*resource.ResourceURI] = resource.ResourceContent
+			return "", nil
+		},
+	}
+}
+
+func TestResourceSLIProviderConfig(t *testing.T) {
+	resources := map[string]string{"broken/lighthouse-config.yaml": "sli-provider: ["}
+	sliProviderConfig := &ResourceSLIProviderConfig{
+		ResourceHandler:    newProjectResourceHandlerMock(resources),
+		DefaultSLIProvider: "prometheus",
+	}
+
+	_, err := sliProviderConfig.GetSLIProvider("sockshop")
+	assert.ErrorIs(t, err, ErrSLIProviderNotConfigured)
+
+	_, err = sliProviderConfig.GetSLIProvider("broken")
+	require.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrSLIProviderNotConfigured)
+
+	require.Nil(t, sliProviderConfig.SetSLIProvider("sockshop", "dynatrace"))
+	assert.Equal(t, "sli-provider: dynatrace\n", resources["sockshop/lighthouse-config.yaml"])
+
+	sliProvider, err := sliProviderConfig.GetSLIProvider("sockshop")
+	require.Nil(t, err)
+	assert.Equal(t, "dynatrace", sliProvider)
+
+	sliProvider, err = sliProviderConfig.GetDefaultSLIProvider()
+	require.Nil(t, err)
+	assert.Equal(t, "prometheus", sliProvider)
+}
+
+func TestFileSLIProviderConfig(t *testing.T) {
+	sliProviderConfig := &FileSLIProviderConfig{Path: filepath.Join(t.TempDir(), "sli-providers.yaml")}
+
+	_, err := sliProviderConfig.GetSLIProvider("sockshop")
+	assert.ErrorIs(t, err, ErrSLIProviderNotConfigured)
+	_, err = sliProviderConfig.GetDefaultSLIProvider()
+	assert.NotNil(t, err)
+
+	require.Nil(t, sliProviderConfig.SetSLIProvider("sockshop", "dynatrace"))
+	require.Nil(t, sliProviderConfig.SetSLIProvider("podtato-head", "prometheus"))
+
+	sliProvider, err := sliProviderConfig.GetSLIProvider("sockshop")
+	require.Nil(t, err)
+	assert.Equal(t, "dynatrace", sliProvider)
+
+	sliProvider, err = sliProviderConfig.GetSLIProvider("podtato-head")
+	require.Nil(t, err)
+	assert.Equal(t, "prometheus", sliProvider)
+}
+
+func TestK8sSLIProviderConfig_SetSLIProvider_ConfigMapDoesntExistYet(t *testing.T) {
+	fakeK8sClient := fake.NewSimpleClientset()
+	sliProviderConfig := &K8sSLIProviderConfig{K8sClient: fakeK8sClient}
+
+	err := sliProviderConfig.SetSLIProvider("my-project", "my-sli-provider")
+	require.Nil(t, err)
+	require.Len(t, fakeK8sClient.Actions(), 1)
+	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
+
+	sliProvider, err := sliProviderConfig.GetSLIProvider("my-project")
+	require.Nil(t, err)
+	require.Equal(t, "my-sli-provider", sliProvider)
+}
+
+func TestK8sSLIProviderConfig_SetSLIProvider_ConfigMapDoesntExistYetAndCreateFails(t *testing.T) {
+	fakeK8sClient := fake.NewSimpleClientset()
+
+	fakeK8sClient.PrependReactor("create", "configmaps", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, errors.New("oops")
+	})
+
+	sliProviderConfig := &K8sSLIProviderConfig{K8sClient: fakeK8sClient}
+
+	err := sliProviderConfig.SetSLIProvider("my-project", "my-sli-provider")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "could not create")
+	require.Len(t, fakeK8sClient.Actions(), 1)
+	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
+}
+
+func TestK8sSLIProviderConfig_SetSLIProvider_ConfigMapAlreadyExists(t *testing.T) {
+	// initialize the fake k8s client with an already existing configmap for the project
+	fakeK8sClient := fake.NewSimpleClientset(getSLISourceConfigMap("my-project", "my-old-sli-provider"))
+	sliProviderConfig := &K8sSLIProviderConfig{K8sClient: fakeK8sClient}
+
+	err := sliProviderConfig.SetSLIProvider("my-project", "my-sli-provider")
+	require.Nil(t, err)
+	require.Len(t, fakeK8sClient.Actions(), 2)
+	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
+	require.Equal(t, "update", fakeK8sClient.Actions()[1].GetVerb())
+}
+
+func TestK8sSLIProviderConfig_SetSLIProvider_ConfigMapAlreadyExistsUpdateFails(t *testing.T) {
+	// initialize the fake k8s client with an already existing configmap for the project
+	fakeK8sClient := fake.NewSimpleClientset(getSLISourceConfigMap("my-project", "my-old-sli-provider"))
+
+	fakeK8sClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, errors.New("oops")
+	})
+
+	sliProviderConfig := &K8sSLIProviderConfig{K8sClient: fakeK8sClient}
+
+	err := sliProviderConfig.SetSLIProvider("my-project", "my-sli-provider")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "could not update")
+	require.Len(t, fakeK8sClient.Actions(), 2)
+	require.Equal(t, "create", fakeK8sClient.Actions()[0].GetVerb())
+	require.Equal(t, "update", fakeK8sClient.Actions()[1].GetVerb())
+}
+
+func TestMigratingSLIProviderConfig_GetDefaultSLIProvider(t *testing.T) {
+	namespace = "keptn"
+	defaultConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "lighthouse-config", Namespace: namespace},
+		Data:       map[string]string{sliProviderKey: "dynatrace"},
+	}
+	sliProviderConfig := &MigratingSLIProviderConfig{
+		Target: &ResourceSLIProviderConfig{ResourceHandler: newProjectResourceHandlerMock(map[string]string{})},
+		Source: &K8sSLIProviderConfig{K8sClient: fake.NewSimpleClientset(defaultConfigMap)},
+	}
+
+	// the default ConfigMap is used as long as the target backend has no default SLI provider
+	sliProvider, err := sliProviderConfig.GetDefaultSLIProvider()
+	require.Nil(t, err)
+	assert.Equal(t, "dynatrace", sliProvider)
+
+	sliProviderConfig.Target = &ResourceSLIProviderConfig{DefaultSLIProvider: "prometheus"}
+	sliProvider, err = sliProviderConfig.GetDefaultSLIProvider()
+	require.Nil(t, err)
+	assert.Equal(t, "prometheus", sliProvider)
+}
+
+func TestMigratingSLIProviderConfig_GetSLIProvider(t *testing.T) {
+	namespace = "keptn"
+	resources := map[string]string{}
+	resourceHandler := newProjectResourceHandlerMock(resources)
+	sliProviderConfig := &MigratingSLIProviderConfig{
+		Target: &ResourceSLIProviderConfig{ResourceHandler: resourceHandler},
+		Source: &K8sSLIProviderConfig{K8sClient: fake.NewSimpleClientset(getSLISourceConfigMap("sockshop", "dynatrace"))},
+	}
+
+	// the SLI provider of the project is only available in the ConfigMap and is copied to the resource-service
+	sliProvider, err := sliProviderConfig.GetSLIProvider("sockshop")
+	require.Nil(t, err)
+	assert.Equal(t, "dynatrace", sliProvider)
+	assert.Equal(t, "sli-provider: dynatrace\n", resources["sockshop/lighthouse-config.yaml"])
+
+	sliProvider, err = sliProviderConfig.GetSLIProvider("sockshop")
+	require.Nil(t, err)
+	assert.Equal(t, "dynatrace", sliProvider)
+	assert.Len(t, resourceHandler.UpdateProjectResourceCalls(), 1)
+
+	_, err = sliProviderConfig.GetSLIProvider("unknown")
+	assert.ErrorIs(t, err, ErrSLIProviderNotConfigured)
+}
+
+func TestMigratingSLIProviderConfig_GetSLIProviderTargetFails(t *testing.T) {
+	sliProviderConfig := &MigratingSLIProviderConfig{
+		Target: &ResourceSLIProviderConfig{ResourceHandler: &event_handler_mock.ProjectResourceHandlerMock{
+			GetProjectResourceFunc: func(project string, resourceURI string) (*apimodels.Resource, error) {
+				return nil, errors.New("oops")
+			},
+		}},
+		Source: &K8sSLIProviderConfig{K8sClient: fake.NewSimpleClientset(getSLISourceConfigMap("sockshop", "dynatrace"))},
+	}
+
+	// unexpected errors of the target backend are not hidden by the migration
+	_, err := sliProviderConfig.GetSLIProvider("sockshop")
+	require.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrSLIProviderNotConfigured)
+}
+
+func TestNewSLIProviderConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		file    string
+		want    SLIProviderConfig
+		wantErr bool
+	}{
+		{
+			name: "resource-service is the default",
+			want: &MigratingSLIProviderConfig{Target: &ResourceSLIProviderConfig{}, Source: &K8sSLIProviderConfig{}},
+		},
+		{
+			name:    "file",
+			backend: FileSLIProviderConfigBackend,
+			file:    "/tmp/sli-providers.yaml",
+			want:    &MigratingSLIProviderConfig{Target: &FileSLIProviderConfig{Path: "/tmp/sli-providers.yaml"}, Source: &K8sSLIProviderConfig{}},
+		},
+		{
+			name:    "file without path",
+			backend: FileSLIProviderConfigBackend,
+			wantErr: true,
+		},
+		{
+			name:    "kubernetes",
+			backend: K8sSLIProviderConfigBackend,
+			want:    &K8sSLIProviderConfig{},
+		},
+		{
+			name:    "unknown backend",
+			backend: "etcd",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(sliProviderConfigBackendEnvVar, tt.backend)
+			t.Setenv(sliProviderConfigFileEnvVar, tt.file)
+
+			got, err := NewSLIProviderConfig(nil)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
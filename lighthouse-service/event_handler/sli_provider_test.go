@@ -0,0 +1,230 @@
+package event_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	keptnfake "github.com/keptn/go-utils/pkg/lib/v0_2_0/fake"
+	event_handler_mock "github.com/keptn/keptn/lighthouse-service/event_handler/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResourceHandlerMock(resources map[string]string) *event_handler_mock.ResourceHandlerMock {
+	return &event_handler_mock.ResourceHandlerMock{
+		GetResourceFunc: func(scope keptnapi.ResourceScope, options ...keptnapi.URIOption) (*models.Resource, error) {
+			content, ok := resources[strings.TrimPrefix(scope.GetResourcePath(), "/resource/")]
+			if !ok {
+				return nil, errors.New("resource not found")
+			}
+			return &models.Resource{ResourceContent: content}, nil
+		},
+	}
+}
+
+func getSLIRequest(indicators ...string) *keptnv2.GetSLITriggeredEventData {
+	return &keptnv2.GetSLITriggeredEventData{
+		EventData: keptnv2.EventData{
+			Project: "sockshop",
+			Stage:   "dev",
+			Service: "carts",
+		},
+		GetSLI: keptnv2.GetSLI{
+			SLIProvider: FileSLIProviderName,
+			Start:       "2022-04-20T12:00:00.000Z",
+			End:         "2022-04-20T12:05:00.000Z",
+			Indicators:  indicators,
+			CustomFilters: []*keptnv2.SLIFilter{
+				{Key: "handler", Value: "ItemsController.addToCart"},
+			},
+		},
+	}
+}
+
+func TestFileSLIProvider_GetSLIValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]string
+		want      []*keptnv2.SLIResult
+		wantErr   bool
+	}{
+		{
+			name:      "yaml file",
+			resources: map[string]string{"sli-values.yaml": "response_time_p95: 250\nerror_rate: 0.1"},
+			want: []*keptnv2.SLIResult{
+				{Metric: "response_time_p95", Value: 250, Success: true},
+				{Metric: "error_rate", Value: 0.1, Success: true},
+			},
+		},
+		{
+			name:      "json file",
+			resources: map[string]string{"sli-values.json": `{"response_time_p95": 250}`},
+			want: []*keptnv2.SLIResult{
+				{Metric: "response_time_p95", Value: 250, Success: true},
+				{Metric: "error_rate", Success: false, Message: "no value available for SLI error_rate"},
+			},
+		},
+		{
+			name:      "csv file with header",
+			resources: map[string]string{"sli-values.csv": "metric,value\nresponse_time_p95,250\nerror_rate, 0"},
+			want: []*keptnv2.SLIResult{
+				{Metric: "response_time_p95", Value: 250, Success: true},
+				{Metric: "error_rate", Value: 0, Success: true},
+			},
+		},
+		{
+			name:      "invalid csv file",
+			resources: map[string]string{"sli-values.csv": "response_time_p95,250,ms"},
+			wantErr:   true,
+		},
+		{
+			name:      "no file",
+			resources: map[string]string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &FileSLIProvider{ResourceHandler: newResourceHandlerMock(tt.resources)}
+			got, err := p.GetSLIValues(getSLIRequest("response_time_p95", "error_rate"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrometheusSLIProvider_GetSLIValues(t *testing.T) {
+	receivedQueries := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "1650456300", r.URL.Query().Get("time"))
+		query := r.URL.Query().Get("query")
+		receivedQueries = append(receivedQueries, query)
+
+		w.Header().Set("Content-Type", "application/json")
+		if query == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"parse error"}`))
+			return
+		}
+		result := map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"resultType": "vector",
+				"result": []interface{}{
+					map[string]interface{}{"metric": map[string]string{}, "value": []interface{}{1650456300, "0.42"}},
+				},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer ts.Close()
+
+	p := &PrometheusSLIProvider{
+		Endpoint:   ts.URL,
+		HTTPClient: &http.Client{},
+		ResourceHandler: newResourceHandlerMock(map[string]string{
+			"sli.yaml": "spec_version: '1.0'\nindicators:\n  error_rate: rate(errors{job='$SERVICE-$PROJECT-$STAGE',handler='$HANDLER'}[$DURATION_SECONDS])\n  broken: invalid",
+		}),
+	}
+
+	got, err := p.GetSLIValues(getSLIRequest("error_rate", "broken", "unknown"))
+	require.Nil(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, &keptnv2.SLIResult{Metric: "error_rate", Value: 0.42, Success: true}, got[0])
+	assert.False(t, got[1].Success)
+	assert.Contains(t, got[1].Message, "parse error")
+	assert.Equal(t, &keptnv2.SLIResult{Metric: "unknown", Success: false, Message: "no query defined for SLI unknown"}, got[2])
+	assert.Equal(t, "rate(errors{job='carts-sockshop-dev',handler='ItemsController.addToCart'}[300s])", receivedQueries[0])
+}
+
+func Test_newPlaceholderReplacer(t *testing.T) {
+	placeholders := map[string]string{
+		"$STAGE":         "dev",
+		"$STAGE_NAME":    "development",
+		"$SERVICE":       "carts",
+		"$SERVICE_GROUP": "shop",
+	}
+	// the result must not depend on the iteration order of the map
+	for i := 0; i < 20; i++ {
+		got := newPlaceholderReplacer(placeholders).Replace("stage='$STAGE',stage_name='$STAGE_NAME',group='$SERVICE_GROUP-$SERVICE'")
+		require.Equal(t, "stage='dev',stage_name='development',group='shop-carts'", got)
+	}
+}
+
+func TestNewLocalSLIProviders(t *testing.T) {
+	_ = os.Unsetenv(prometheusEndpointEnvVar)
+	providers := NewLocalSLIProviders(newResourceHandlerMock(nil), &http.Client{})
+	assert.Contains(t, providers, FileSLIProviderName)
+	assert.NotContains(t, providers, PrometheusSLIProviderName)
+
+	_ = os.Setenv(prometheusEndpointEnvVar, "http://prometheus:9090")
+	defer os.Unsetenv(prometheusEndpointEnvVar)
+	providers = NewLocalSLIProviders(newResourceHandlerMock(nil), &http.Client{})
+	assert.Contains(t, providers, PrometheusSLIProviderName)
+}
+
+func TestStartEvaluationHandler_evaluateWithLocalSLIProvider(t *testing.T) {
+	datastore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"events":[]}`))
+	}))
+	defer datastore.Close()
+	_ = os.Setenv("MONGODB_DATASTORE", strings.TrimPrefix(datastore.URL, "http://"))
+
+	incomingEvent := getStartEvaluationEvent()
+	incomingEvent.SetID("my-triggered-id")
+	fakeSender := &keptnfake.EventSender{}
+	keptnHandler, _ := keptnv2.NewKeptn(&incomingEvent, keptncommon.KeptnOpts{EventSender: fakeSender})
+
+	resourceHandler := newResourceHandlerMock(map[string]string{
+		"slo.yaml":        "objectives:\n  - sli: response_time_p95\n    pass:\n      - criteria:\n          - \"<=500\"\ntotal_score:\n  pass: \"90%\"",
+		"sli-values.yaml": "response_time_p95: 250",
+	})
+
+	eh := &StartEvaluationHandler{
+		Event:        incomingEvent,
+		KeptnHandler: keptnHandler,
+		SLIProviderConfig: &MockSLIProviderConfig{
+			ProjectSLIProvider: struct {
+				val string
+				err error
+			}{val: FileSLIProviderName},
+		},
+		SLOFileRetriever: SLOFileRetriever{ResourceHandler: resourceHandler},
+		SLIProviders:     NewLocalSLIProviders(resourceHandler, &http.Client{}),
+	}
+
+	e := &keptnv2.EvaluationTriggeredEventData{}
+	require.Nil(t, incomingEvent.DataAs(e))
+
+	err := eh.sendGetSliCloudEvent(context.Background(), "my-context", "", e, "2022-04-20T12:00:00.000Z", "2022-04-20T12:05:00.000Z")
+	require.Nil(t, err)
+
+	// the evaluation is finished without sending a get-sli.triggered event
+	require.Len(t, fakeSender.SentEvents, 1)
+	sentEvent := fakeSender.SentEvents[0]
+	assert.Equal(t, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), sentEvent.Type())
+	assert.Equal(t, "my-triggered-id", sentEvent.Extensions()["triggeredid"])
+
+	finishedData := &keptnv2.EvaluationFinishedEventData{}
+	require.Nil(t, sentEvent.DataAs(finishedData))
+	assert.Equal(t, keptnv2.ResultPass, finishedData.Result)
+	require.Len(t, finishedData.Evaluation.IndicatorResults, 1)
+	assert.Equal(t, float64(250), finishedData.Evaluation.IndicatorResults[0].Value.Value)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/keptn/go-utils/pkg/common/timeutils"
 	logger "github.com/sirupsen/logrus"
+	"net/http"
 	"net/url"
 	"sync"
 
@@ -19,6 +20,7 @@ type StartEvaluationHandler struct {
 	KeptnHandler      *keptnv2.Keptn
 	SLIProviderConfig SLIProviderConfig
 	SLOFileRetriever  SLOFileRetriever `deep:"-"`
+	SLIProviders      SLIProviders     `deep:"-"`
 }
 
 func (eh *StartEvaluationHandler) HandleEvent(ctx context.Context) error {
@@ -117,8 +119,11 @@ func (eh *StartEvaluationHandler) sendGetSliCloudEvent(ctx context.Context, kept
 			return sendEvent(keptnContext, eh.Event.ID(), keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), commitID, eh.KeptnHandler, &evaluationFinishedData)
 		}
 	}
-	// send a new event to trigger the SLI retrieval
 	logger.Debug("SLI provider for project " + e.Project + " is: " + sliProvider)
+	if provider, ok := eh.SLIProviders[sliProvider]; ok {
+		return eh.evaluateWithLocalSLIProvider(keptnContext, commitID, e, sliProvider, provider, indicators, evaluationStartTimestamp, evaluationEndTimestamp, filters)
+	}
+	// send a new event to trigger the SLI retrieval
 	err = eh.sendInternalGetSLIEvent(keptnContext, commitID, e, sliProvider, indicators, evaluationStartTimestamp, evaluationEndTimestamp, filters)
 	return nil
 }
@@ -205,6 +210,59 @@ func getEvaluationTimestamps(e *keptnv2.EvaluationTriggeredEventData) (string, s
 func (eh *StartEvaluationHandler) sendInternalGetSLIEvent(shkeptncontext string, commitID string, e *keptnv2.EvaluationTriggeredEventData, sliProvider string, indicators []string, start string, end string, filters []*keptnv2.SLIFilter) error {
 	source, _ := url.Parse("lighthouse-service")
 
+	getSLITriggeredEventData := getSLITriggeredEventData(e, sliProvider, indicators, start, end, filters)
+
+	event := cloudevents.NewEvent()
+	event.SetType(keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", shkeptncontext)
+	event.SetExtension("gitcommitid", commitID)
+	_ = event.SetData(cloudevents.ApplicationJSON, getSLITriggeredEventData)
+
+	logger.Debug("Send event: " + keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
+	return eh.KeptnHandler.SendCloudEvent(event)
+}
+
+// evaluateWithLocalSLIProvider retrieves the SLI values from an in-process SLI provider and directly evaluates them,
+// without sending a get-sli.triggered event
+func (eh *StartEvaluationHandler) evaluateWithLocalSLIProvider(shkeptncontext string, commitID string, e *keptnv2.EvaluationTriggeredEventData, sliProvider string, provider SLIProvider, indicators []string, start string, end string, filters []*keptnv2.SLIFilter) error {
+	request := getSLITriggeredEventData(e, sliProvider, indicators, start, end, filters)
+
+	getSLIFinishedEventData := &keptnv2.GetSLIFinishedEventData{
+		EventData: keptnv2.EventData{
+			Project: e.Project,
+			Stage:   e.Stage,
+			Service: e.Service,
+			Labels:  e.Labels,
+			Status:  keptnv2.StatusSucceeded,
+			Result:  keptnv2.ResultPass,
+		},
+		GetSLI: keptnv2.GetSLIFinished{
+			Start: start,
+			End:   end,
+		},
+	}
+
+	indicatorValues, err := provider.GetSLIValues(&request)
+	if err != nil {
+		logger.WithError(err).Errorf("could not retrieve SLI values from SLI provider %s", sliProvider)
+		getSLIFinishedEventData.Status = keptnv2.StatusErrored
+		getSLIFinishedEventData.Result = keptnv2.ResultFailed
+		getSLIFinishedEventData.Message = fmt.Sprintf("could not retrieve SLI values from SLI provider %s: %s", sliProvider, err.Error())
+	}
+	getSLIFinishedEventData.GetSLI.IndicatorValues = indicatorValues
+
+	evaluateSLIHandler := &EvaluateSLIHandler{
+		Event:            eh.Event,
+		HTTPClient:       &http.Client{},
+		KeptnHandler:     eh.KeptnHandler,
+		SLOFileRetriever: eh.SLOFileRetriever,
+	}
+	return evaluateSLIHandler.evaluateSLIs(shkeptncontext, eh.Event.ID(), commitID, getSLIFinishedEventData)
+}
+
+func getSLITriggeredEventData(e *keptnv2.EvaluationTriggeredEventData, sliProvider string, indicators []string, start string, end string, filters []*keptnv2.SLIFilter) keptnv2.GetSLITriggeredEventData {
 	getSLITriggeredEventData := keptnv2.GetSLITriggeredEventData{
 		EventData: keptnv2.EventData{
 			Project: e.Project,
@@ -224,15 +282,5 @@ func (eh *StartEvaluationHandler) sendInternalGetSLIEvent(shkeptncontext string,
 	if e.Deployment.DeploymentNames != nil && len(e.Deployment.DeploymentNames) > 0 {
 		getSLITriggeredEventData.Deployment = e.Deployment.DeploymentNames[0]
 	}
-
-	event := cloudevents.NewEvent()
-	event.SetType(keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
-	event.SetSource(source.String())
-	event.SetDataContentType(cloudevents.ApplicationJSON)
-	event.SetExtension("shkeptncontext", shkeptncontext)
-	event.SetExtension("gitcommitid", commitID)
-	_ = event.SetData(cloudevents.ApplicationJSON, getSLITriggeredEventData)
-
-	logger.Debug("Send event: " + keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
-	return eh.KeptnHandler.SendCloudEvent(event)
+	return getSLITriggeredEventData
 }
@@ -62,6 +62,11 @@ func (m *MockSLIProviderConfig) GetSLIProvider(project string) (string, error) {
 	return m.ProjectSLIProvider.val, m.DefaultSLIProvider.err
 }
 
+func (m *MockSLIProviderConfig) SetSLIProvider(project string, sliProvider string) error {
+	m.ProjectSLIProvider.val = sliProvider
+	return m.ProjectSLIProvider.err
+}
+
 func TestStartEvaluationHandler_HandleEvent(t *testing.T) {
 	type ceTypeEvent struct {
 		Type string `json:"type"`
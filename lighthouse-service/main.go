@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 
@@ -43,7 +45,35 @@ func main() {
 func _main(args []string, env envConfig) int {
 	ctx := getGracefulContext()
 
-	p, err := cloudevents.NewHTTP(cloudevents.WithPath(env.Path), cloudevents.WithPort(env.Port), cloudevents.WithGetHandlerFunc(keptnapi.HealthEndpointHandler))
+	errorBudgetHandler, err := event_handler.NewErrorBudgetHandler()
+	if err != nil {
+		logger.Fatalf("failed to create error budget handler, %v", err)
+	}
+
+	evaluationComparisonHandler, err := event_handler.NewEvaluationComparisonHandler()
+	if err != nil {
+		logger.Fatalf("failed to create evaluation comparison handler, %v", err)
+	}
+
+	evaluationValidityHandler, err := event_handler.NewEvaluationValidityHandler()
+	if err != nil {
+		logger.Fatalf("failed to create evaluation validity handler, %v", err)
+	}
+
+	apiHandlers := map[string]http.Handler{
+		event_handler.ErrorBudgetEndpoint:            errorBudgetHandler,
+		event_handler.EvaluationComparisonEndpoint:   evaluationComparisonHandler,
+		event_handler.EvaluationInvalidationEndpoint: evaluationValidityHandler,
+		event_handler.EvaluationRevalidationEndpoint: evaluationValidityHandler,
+		event_handler.EvaluationAnnotationEndpoint:   evaluationValidityHandler,
+	}
+
+	p, err := cloudevents.NewHTTP(
+		cloudevents.WithPath(env.Path),
+		cloudevents.WithPort(env.Port),
+		cloudevents.WithMiddleware(apiMiddleware(apiHandlers)),
+		cloudevents.WithGetHandlerFunc(keptnapi.HealthEndpointHandler),
+	)
 	if err != nil {
 		logger.Fatalf("failed to create client, %v", err)
 	}
@@ -77,6 +107,27 @@ func gotEvent(ctx context.Context, event cloudevents.Event) error {
 	return nil
 }
 
+// apiPathPrefix is the prefix of all API endpoints of the lighthouse-service
+const apiPathPrefix = "/v1/"
+
+// apiMiddleware serves the API endpoints of the lighthouse-service and passes all other requests on to the CloudEvents receiver.
+// Unknown paths below apiPathPrefix are rejected, so that events can not be sent to the receiver via the API gateway
+func apiMiddleware(handlers map[string]http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if handler, ok := handlers[r.URL.Path]; ok {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, apiPathPrefix) {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // storing wait group into context to sync before shutdown
 func getGracefulContext() context.Context {
 
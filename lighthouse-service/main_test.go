@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_apiMiddleware(t *testing.T) {
+	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	receiver := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := apiMiddleware(map[string]http.Handler{"/v1/errorbudget": apiHandler})(receiver)
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: "/v1/errorbudget", wantCode: http.StatusOK},
+		{path: "/v1/unknown", wantCode: http.StatusNotFound},
+		{path: "/v1/evaluation/unknown", wantCode: http.StatusNotFound},
+		{path: "/", wantCode: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
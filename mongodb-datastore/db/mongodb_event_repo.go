@@ -83,6 +83,14 @@ func (mr *MongoDBEventRepo) InsertEvent(event models.KeptnContextExtendedCE) err
 		}
 	}
 
+	// "revalidated" events revert all previous invalidations of the same triggeredid
+	if strings.HasSuffix(string(*event.Type), ".revalidated") {
+		if err := mr.deleteInvalidatedEvents(ctx, collection, event.Triggeredid); err != nil {
+			logger.WithError(err).Error("could not process .revalidated event")
+			return err
+		}
+	}
+
 	for _, indexName := range projectEventsIndexes {
 		mr.ensureIndexExistsOnCollection(
 			ctx,
@@ -266,6 +274,23 @@ func (mr *MongoDBEventRepo) storeEvaluationInvalidatedEvent(ctx context.Context,
 	return nil
 }
 
+func (mr *MongoDBEventRepo) deleteInvalidatedEvents(ctx context.Context, collection *mongo.Collection, triggeredID string) error {
+	invalidatedCollectionName := getInvalidatedCollectionName(collection.Name())
+	logger.Debugf("Deleting invalidated events with triggeredid %s from collection %s", triggeredID, invalidatedCollectionName)
+
+	mdbClient, err := mr.DBConnection.GetClient()
+	if err != nil {
+		return err
+	}
+	invalidatedCollection := mdbClient.Database(getDatabaseName()).Collection(invalidatedCollectionName)
+
+	_, err = invalidatedCollection.DeleteMany(ctx, bson.M{triggeredIDPropertyPath: triggeredID})
+	if err != nil {
+		return fmt.Errorf("failed to delete from collection: %v", err)
+	}
+	return nil
+}
+
 func (mr *MongoDBEventRepo) getCollectionAndContext(collectionName string) (*mongo.Collection, context.Context, context.CancelFunc, error) {
 	mdbClient, err := mr.DBConnection.GetClient()
 	if err != nil {
@@ -146,6 +146,27 @@ func TestMongoDBEventRepo_InsertAndRetrieve(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, eventsByType)
 	require.Empty(t, eventsByType.Events)
+
+	revalidatedEvent := invalidatedEvent
+	revalidatedEvent.ID = "my-revalidated-id"
+	revalidatedEvent.Type = stringp("sh.keptn.event.evaluation.revalidated")
+
+	err = repo.InsertEvent(revalidatedEvent)
+	require.Nil(t, err)
+
+	// after re-validating, the evaluation is returned again
+	eventsByType, err = repo.GetEventsByType(
+		event.GetEventsByTypeParams{
+			EventType:          evaluationEventType,
+			ExcludeInvalidated: &excludeInvalidated,
+			Filter:             filter,
+			Limit:              &pageSize,
+		},
+	)
+
+	require.Nil(t, err)
+	require.NotNil(t, eventsByType)
+	require.Len(t, eventsByType.Events, 1)
 }
 
 func TestMongoDBEventRepo_Retrieve_NoProjectOrKeptnContext(t *testing.T) {
@@ -18,6 +18,8 @@ const (
 	StartedEvent EventStatus = "started"
 	// FinishedEvent describes a 'finished' event
 	FinishedEvent EventStatus = "finished"
+	// StatusChangedEvent describes a 'status.changed' event. Since the kind of an event type is its last element, it is parsed as 'changed'
+	StatusChangedEvent EventStatus = "changed"
 	// WaitingEvent describes a 'waiting' event
 	WaitingEvent EventStatus = "waiting"
 	// RootEvent indicates that an event triggered a task sequence execution
@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/keptn/go-utils/pkg/api/models"
+	apimodels "github.com/keptn/go-utils/pkg/api/models"
+	"github.com/keptn/keptn/shipyard-controller/models"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -56,7 +57,7 @@ func (mdbrepo *MongoDBStateRepo) CreateSequenceState(state models.SequenceState)
 	return nil
 }
 
-func (mdbrepo *MongoDBStateRepo) FindSequenceStates(filter models.StateFilter) (*models.SequenceStates, error) {
+func (mdbrepo *MongoDBStateRepo) FindSequenceStates(filter apimodels.StateFilter) (*models.SequenceStates, error) {
 	if filter.Project == "" {
 		return nil, errors.New("project must be set")
 	}
@@ -111,7 +112,7 @@ func (mdbrepo *MongoDBStateRepo) FindSequenceStates(filter models.StateFilter) (
 	return result, nil
 }
 
-func (mdbrepo *MongoDBStateRepo) getSearchOptions(filter models.StateFilter) bson.M {
+func (mdbrepo *MongoDBStateRepo) getSearchOptions(filter apimodels.StateFilter) bson.M {
 	searchOptions := bson.M{
 		"project": filter.Project,
 	}
@@ -178,7 +179,7 @@ func (mdbrepo *MongoDBStateRepo) UpdateSequenceState(state models.SequenceState)
 	return nil
 }
 
-func (mdbrepo *MongoDBStateRepo) DeleteSequenceStates(filter models.StateFilter) error {
+func (mdbrepo *MongoDBStateRepo) DeleteSequenceStates(filter apimodels.StateFilter) error {
 	if filter.Project == "" {
 		return errors.New("project must be set")
 	}
@@ -6,6 +6,7 @@ import (
 	apimodels "github.com/keptn/go-utils/pkg/api/models"
 	"github.com/keptn/go-utils/pkg/common/timeutils"
 	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"github.com/tryvium-travels/memongo"
@@ -60,7 +61,7 @@ func TestMongoDBStateRepo_FindSequenceStates(t *testing.T) {
 
 	mdbrepo := db.NewMongoDBStateRepo(db.GetMongoDBConnectionInstance())
 
-	state := apimodels.SequenceState{
+	state := models.SequenceState{
 		Name:           "my-sequence",
 		Service:        "my-service",
 		Project:        "my-project",
@@ -69,7 +70,7 @@ func TestMongoDBStateRepo_FindSequenceStates(t *testing.T) {
 		State:          "triggered",
 	}
 
-	state2 := apimodels.SequenceState{
+	state2 := models.SequenceState{
 		Name:           "my-sequence2",
 		Service:        "my-service",
 		Project:        "my-project",
@@ -78,7 +79,7 @@ func TestMongoDBStateRepo_FindSequenceStates(t *testing.T) {
 		State:          "finished",
 	}
 
-	state3 := apimodels.SequenceState{
+	state3 := models.SequenceState{
 		Name:           "my-sequence3",
 		Service:        "my-service",
 		Project:        "my-project",
@@ -208,7 +209,7 @@ func TestMongoDBStateRepo_StateRepoInsertAndRetrieve(t *testing.T) {
 
 	mdbrepo := db.NewMongoDBStateRepo(db.GetMongoDBConnectionInstance())
 
-	state := apimodels.SequenceState{
+	state := models.SequenceState{
 		Name:           "my-sequence",
 		Service:        "my-service",
 		Project:        "my-project",
@@ -290,7 +291,7 @@ func TestMongoDBStateRepo_StateRepoInsertInvalidStates(t *testing.T) {
 	mdbrepo := db.NewMongoDBStateRepo(db.GetMongoDBConnectionInstance())
 
 	// create a state without a project
-	invalidState := apimodels.SequenceState{
+	invalidState := models.SequenceState{
 		Name:           "my-sequence",
 		Service:        "my-service",
 		Time:           "",
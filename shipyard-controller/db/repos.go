@@ -25,9 +25,9 @@ var ErrOpenRemediationNotFound = errors.New("open remediation not found")
 
 //go:generate moq --skip-ensure -pkg db_mock -out ./mock/sequencestaterepo_mock.go . SequenceStateRepo
 type SequenceStateRepo interface {
-	CreateSequenceState(state apimodels.SequenceState) error
-	FindSequenceStates(filter apimodels.StateFilter) (*apimodels.SequenceStates, error)
-	UpdateSequenceState(state apimodels.SequenceState) error
+	CreateSequenceState(state models.SequenceState) error
+	FindSequenceStates(filter apimodels.StateFilter) (*models.SequenceStates, error)
+	UpdateSequenceState(state models.SequenceState) error
 	DeleteSequenceStates(filter apimodels.StateFilter) error
 }
 
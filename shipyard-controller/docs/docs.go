@@ -2329,6 +2329,9 @@ var doc = `{
                 "latestFailedEvent": {
                     "$ref": "#/definitions/models.SequenceStateEvent"
                 },
+                "latestStatus": {
+                    "$ref": "#/definitions/models.SequenceStateStatus"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2337,6 +2340,23 @@ var doc = `{
                 }
             }
         },
+        "models.SequenceStateStatus": {
+            "type": "object",
+            "properties": {
+                "eventId": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
         "models.SequenceStates": {
             "type": "object",
             "properties": {
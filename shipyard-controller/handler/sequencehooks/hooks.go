@@ -30,6 +30,11 @@ type ISequenceTaskStartedHook interface {
 	OnSequenceTaskStarted(apimodels.KeptnContextExtendedCE)
 }
 
+//go:generate moq -pkg fake -skip-ensure -out ./fake/sequencetaskstatuschanged.go . ISequenceTaskStatusChangedHook
+type ISequenceTaskStatusChangedHook interface {
+	OnSequenceTaskStatusChanged(apimodels.KeptnContextExtendedCE)
+}
+
 //go:generate moq -pkg fake -skip-ensure -out ./fake/sequencetaskfinished.go . ISequenceTaskFinishedHook
 type ISequenceTaskFinishedHook interface {
 	OnSequenceTaskFinished(apimodels.KeptnContextExtendedCE)
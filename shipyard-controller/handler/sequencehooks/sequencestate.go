@@ -43,14 +43,14 @@ func (smv *SequenceStateMaterializedView) OnSequenceTriggered(event apimodels.Ke
 		return
 	}
 
-	state := apimodels.SequenceState{
+	state := models.SequenceState{
 		Name:           sequenceName,
 		Service:        eventScope.Service,
 		Project:        eventScope.Project,
 		Time:           timeutils.GetKeptnTimeStamp(event.Time),
 		Shkeptncontext: eventScope.KeptnContext,
 		State:          apimodels.SequenceTriggeredState,
-		Stages:         []apimodels.SequenceStateStage{},
+		Stages:         []models.SequenceStateStage{},
 	}
 
 	//if the next event in sequence is an action we get the problem title form it
@@ -126,6 +126,25 @@ func (smv *SequenceStateMaterializedView) OnSequenceTaskStarted(event apimodels.
 	}
 }
 
+func (smv *SequenceStateMaterializedView) OnSequenceTaskStatusChanged(event apimodels.KeptnContextExtendedCE) {
+	smv.mutex.Lock()
+	defer smv.mutex.Unlock()
+	state, err := smv.updateLastEventOfSequence(event)
+	if err != nil {
+		log.Errorf("could not update sequence state: %s", err.Error())
+		return
+	}
+
+	if err := smv.updateStatusOfSequence(event, state); err != nil {
+		log.Errorf("could not update status of sequence state: %s", err.Error())
+		return
+	}
+
+	if err := smv.SequenceStateRepo.UpdateSequenceState(state); err != nil {
+		log.Errorf("could not update sequence state: %s", err.Error())
+	}
+}
+
 func (smv *SequenceStateMaterializedView) OnSequenceTaskFinished(event apimodels.KeptnContextExtendedCE) {
 	smv.mutex.Lock()
 	defer smv.mutex.Unlock()
@@ -208,11 +227,11 @@ func (smv *SequenceStateMaterializedView) OnSequenceResumed(resume models.EventS
 	}
 }
 
-func (smv *SequenceStateMaterializedView) findSequenceStateForEvent(eventScope models.EventScope) (*apimodels.SequenceState, error) {
+func (smv *SequenceStateMaterializedView) findSequenceStateForEvent(eventScope models.EventScope) (*models.SequenceState, error) {
 	return smv.findSequenceState(eventScope.Project, eventScope.KeptnContext)
 }
 
-func (smv *SequenceStateMaterializedView) findSequenceState(project, keptnContext string) (*apimodels.SequenceState, error) {
+func (smv *SequenceStateMaterializedView) findSequenceState(project, keptnContext string) (*models.SequenceState, error) {
 	states, err := smv.SequenceStateRepo.FindSequenceStates(apimodels.StateFilter{
 		GetSequenceStateParams: apimodels.GetSequenceStateParams{
 			Project:      project,
@@ -270,7 +289,7 @@ func (smv *SequenceStateMaterializedView) updateSequenceStateInStage(eventScope
 	}
 }
 
-func (smv *SequenceStateMaterializedView) updateEvaluationOfSequence(event apimodels.KeptnContextExtendedCE, state apimodels.SequenceState) error {
+func (smv *SequenceStateMaterializedView) updateEvaluationOfSequence(event apimodels.KeptnContextExtendedCE, state models.SequenceState) error {
 	evaluationFinishedEventData := &keptnv2.EvaluationFinishedEventData{}
 	if err := keptnv2.Decode(event.Data, evaluationFinishedEventData); err != nil {
 		return fmt.Errorf("could not decode evaluation.finished event data: %s", err.Error())
@@ -290,7 +309,7 @@ func (smv *SequenceStateMaterializedView) updateEvaluationOfSequence(event apimo
 	return nil
 }
 
-func (smv *SequenceStateMaterializedView) updateImageOfSequence(event apimodels.KeptnContextExtendedCE, state apimodels.SequenceState) error {
+func (smv *SequenceStateMaterializedView) updateImageOfSequence(event apimodels.KeptnContextExtendedCE, state models.SequenceState) error {
 	deploymentTriggeredEventData := &keptnv2.DeploymentTriggeredEventData{}
 	if err := keptnv2.Decode(event.Data, deploymentTriggeredEventData); err != nil {
 		return fmt.Errorf("could not decode deployment.triggered event data: %s", err.Error())
@@ -311,10 +330,32 @@ func (smv *SequenceStateMaterializedView) updateImageOfSequence(event apimodels.
 	return nil
 }
 
-func (smv *SequenceStateMaterializedView) updateLastEventOfSequence(event apimodels.KeptnContextExtendedCE) (apimodels.SequenceState, error) {
+func (smv *SequenceStateMaterializedView) updateStatusOfSequence(event apimodels.KeptnContextExtendedCE, state models.SequenceState) error {
+	statusChangedEventData := &models.TaskStatusChangedEventData{}
+	if err := keptnv2.Decode(event.Data, statusChangedEventData); err != nil {
+		return fmt.Errorf("could not decode status.changed event data: %s", err.Error())
+	}
 	eventScope, err := models.NewEventScope(event)
 	if err != nil {
-		return apimodels.SequenceState{}, fmt.Errorf("could not determine event scope: %s", err.Error())
+		return fmt.Errorf("could not determine event scope: %s", err.Error())
+	}
+	for index, stage := range state.Stages {
+		if stage.Name == eventScope.Stage {
+			state.Stages[index].LatestStatus = &models.SequenceStateStatus{
+				EventID:  event.ID,
+				Message:  statusChangedEventData.Message,
+				Progress: statusChangedEventData.Progress,
+				Time:     timeutils.GetKeptnTimeStamp(event.Time),
+			}
+		}
+	}
+	return nil
+}
+
+func (smv *SequenceStateMaterializedView) updateLastEventOfSequence(event apimodels.KeptnContextExtendedCE) (models.SequenceState, error) {
+	eventScope, err := models.NewEventScope(event)
+	if err != nil {
+		return models.SequenceState{}, fmt.Errorf("could not determine event scope: %s", err.Error())
 	}
 
 	states, err := smv.SequenceStateRepo.FindSequenceStates(apimodels.StateFilter{
@@ -325,17 +366,17 @@ func (smv *SequenceStateMaterializedView) updateLastEventOfSequence(event apimod
 	})
 
 	if err != nil {
-		return apimodels.SequenceState{}, fmt.Errorf(sequenceStateRetrievalErrorMsg, eventScope.KeptnContext, err.Error())
+		return models.SequenceState{}, fmt.Errorf(sequenceStateRetrievalErrorMsg, eventScope.KeptnContext, err.Error())
 	}
 
 	if len(states.States) == 0 {
-		return apimodels.SequenceState{}, fmt.Errorf("could not find sequence state for keptnContext %s", eventScope.KeptnContext)
+		return models.SequenceState{}, fmt.Errorf("could not find sequence state for keptnContext %s", eventScope.KeptnContext)
 	}
 	state := states.States[0]
 
 	eventData := &keptnv2.EventData{}
 	if err := keptnv2.Decode(event.Data, eventData); err != nil {
-		return apimodels.SequenceState{}, fmt.Errorf("could not parse event data: %s", err.Error())
+		return models.SequenceState{}, fmt.Errorf("could not parse event data: %s", err.Error())
 	}
 
 	newLastEvent := &apimodels.SequenceStateEvent{
@@ -349,17 +390,18 @@ func (smv *SequenceStateMaterializedView) updateLastEventOfSequence(event apimod
 		if stage.Name == eventScope.Stage {
 			stageFound = true
 			state.Stages[index].LatestEvent = newLastEvent
-			state.Stages[index].State = getStageState(*eventScope)
+			state.Stages[index].LatestStatus = nil
+			state.Stages[index].State = getStageState(*eventScope, stage.State)
 			if eventData.Result == keptnv2.ResultFailed || eventData.Status == keptnv2.StatusErrored {
 				state.Stages[index].LatestFailedEvent = newLastEvent
 			}
 		}
 	}
 	if !stageFound {
-		newStage := apimodels.SequenceStateStage{
+		newStage := models.SequenceStateStage{
 			Name:        eventScope.Stage,
 			LatestEvent: newLastEvent,
-			State:       getStageState(*eventScope),
+			State:       getStageState(*eventScope, ""),
 		}
 		if eventData.Result == keptnv2.ResultFailed || eventData.Status == keptnv2.StatusErrored {
 			newStage.LatestFailedEvent = newLastEvent
@@ -369,7 +411,11 @@ func (smv *SequenceStateMaterializedView) updateLastEventOfSequence(event apimod
 	return state, nil
 }
 
-func getStageState(eventScope models.EventScope) string {
+func getStageState(eventScope models.EventScope, currentState string) string {
+	// a '.status.changed' event only reports the progress of a task and does not change the state of the stage
+	if models.IsStatusChangedEventType(eventScope.EventType) && currentState != "" {
+		return currentState
+	}
 	stageState := apimodels.SequenceTriggeredState
 	// check if this event was a <stage>.<sequence>.finished event - if yes, mark the stage as completed
 	if keptnv2.IsSequenceEventType(eventScope.EventType) {
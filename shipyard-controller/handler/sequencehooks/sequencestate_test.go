@@ -33,9 +33,9 @@ func TestSequenceStateMaterializedView_OnSequenceStarted(t *testing.T) {
 			name: "start sequence",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -47,7 +47,7 @@ func TestSequenceStateMaterializedView_OnSequenceStarted(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -95,9 +95,9 @@ func TestSequenceStateMaterializedView_OnSequenceWaiting(t *testing.T) {
 			name: "start sequence",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -109,7 +109,7 @@ func TestSequenceStateMaterializedView_OnSequenceWaiting(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -157,9 +157,9 @@ func TestSequenceStateMaterializedView_OnSequenceTimeOud(t *testing.T) {
 			name: "sequence timed out",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -171,7 +171,7 @@ func TestSequenceStateMaterializedView_OnSequenceTimeOud(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -220,16 +220,16 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 			name: "finish sequence",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
 									Project:        "my-project",
 									Shkeptncontext: "my-context",
 									State:          "triggered",
-									Stages: []models.SequenceStateStage{
+									Stages: []scmodels.SequenceStateStage{
 										{
 											Name:  "dev",
 											State: "succeeded",
@@ -243,7 +243,7 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -265,16 +265,16 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 			name: "try to finish sequence - not all stages finished yet",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
 									Project:        "my-project",
 									Shkeptncontext: "my-context",
 									State:          "triggered",
-									Stages: []models.SequenceStateStage{
+									Stages: []scmodels.SequenceStateStage{
 										{
 											Name:  "dev",
 											State: "succeeded",
@@ -288,7 +288,7 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -310,9 +310,9 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 			name: "invalid event scope - do not update",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -324,7 +324,7 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -341,10 +341,10 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 			name: "cannot find sequence - do not update",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
 						return nil, errors.New("oops")
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -365,10 +365,10 @@ func TestSequenceStateMaterializedView_OnSequenceFinished(t *testing.T) {
 			name: "cannot find sequence - do not update (2)",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{}, nil
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -419,9 +419,9 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 			name: "update evaluation",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -433,7 +433,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -460,9 +460,9 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 			name: "update evaluation fails: not a lighthouse finished event",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -474,7 +474,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -501,9 +501,9 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 			name: "failed task",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -515,7 +515,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -637,9 +637,9 @@ func TestSequenceStateMaterializedView_MultipleScoresOnSequenceFinished(t *testi
 	t.Run("multiple score test", func(t *testing.T) {
 
 		SequenceStateRepo := &db_mock.SequenceStateRepoMock{
-			FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-				return &models.SequenceStates{
-					States: []models.SequenceState{
+			FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+				return &scmodels.SequenceStates{
+					States: []scmodels.SequenceState{
 						{
 							Name:           "my-sequence",
 							Service:        "my-service",
@@ -651,7 +651,7 @@ func TestSequenceStateMaterializedView_MultipleScoresOnSequenceFinished(t *testi
 					},
 				}, nil
 			},
-			UpdateSequenceStateFunc: func(state models.SequenceState) error {
+			UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 				return nil
 			},
 		}
@@ -691,6 +691,55 @@ func TestSequenceStateMaterializedView_OnSequenceTaskStarted(t *testing.T) {
 	}
 }
 
+func TestSequenceStateMaterializedView_OnSequenceTaskStatusChanged(t *testing.T) {
+	sequenceStateRepo := &db_mock.SequenceStateRepoMock{
+		FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+			return &scmodels.SequenceStates{
+				States: []scmodels.SequenceState{
+					{
+						Name:           "my-sequence",
+						Service:        "my-service",
+						Project:        "my-project",
+						Shkeptncontext: "my-context",
+						State:          "started",
+						Stages:         []scmodels.SequenceStateStage{{Name: "my-stage", State: "triggered"}},
+					},
+				},
+			}, nil
+		},
+		UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
+			return nil
+		},
+	}
+	smv := sequencehooks.NewSequenceStateMaterializedView(sequenceStateRepo)
+
+	smv.OnSequenceTaskStatusChanged(models.KeptnContextExtendedCE{
+		ID:             "my-status-id",
+		Type:           common.Stringp("sh.keptn.event.test.status.changed"),
+		Shkeptncontext: "my-context",
+		Data: scmodels.TaskStatusChangedEventData{
+			EventData: keptnv2.EventData{
+				Project: "my-project",
+				Stage:   "my-stage",
+				Service: "my-service",
+				Message: "executed 40 of 100 requests",
+			},
+			Progress: 40,
+		},
+	})
+
+	require.Len(t, sequenceStateRepo.UpdateSequenceStateCalls(), 1)
+	stage := sequenceStateRepo.UpdateSequenceStateCalls()[0].State.Stages[0]
+	require.Equal(t, "triggered", stage.State)
+	require.NotNil(t, stage.LatestEvent)
+	require.Equal(t, "sh.keptn.event.test.status.changed", stage.LatestEvent.Type)
+	require.Equal(t, "my-status-id", stage.LatestEvent.ID)
+	require.NotNil(t, stage.LatestStatus)
+	require.Equal(t, "my-status-id", stage.LatestStatus.EventID)
+	require.Equal(t, "executed 40 of 100 requests", stage.LatestStatus.Message)
+	require.Equal(t, 40, stage.LatestStatus.Progress)
+}
+
 func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 	tests := []struct {
 		name                        string
@@ -708,9 +757,9 @@ func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 			name: "update sequence state - insert new stage",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -722,7 +771,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -740,16 +789,16 @@ func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 			name: "update sequence state with existing stage",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
 									Project:        "my-project",
 									Shkeptncontext: "my-context",
 									State:          "triggered",
-									Stages: []models.SequenceStateStage{
+									Stages: []scmodels.SequenceStateStage{
 										{
 											Name: "my-stage",
 											LatestEvent: &models.SequenceStateEvent{
@@ -762,7 +811,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -792,7 +841,7 @@ func TestSequenceStateMaterializedView_OnSequenceTaskTriggered(t *testing.T) {
 			name: "find state returns error - do not call update",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
 						return nil, errors.New("oops")
 					},
 				},
@@ -874,7 +923,7 @@ func TestSequenceStateMaterializedView_OnSequenceTriggered(t *testing.T) {
 			name: "create a new sequence state",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					CreateSequenceStateFunc: func(state models.SequenceState) error {
+					CreateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -890,7 +939,7 @@ func TestSequenceStateMaterializedView_OnSequenceTriggered(t *testing.T) {
 			name: "create a new remediation sequence",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					CreateSequenceStateFunc: func(state models.SequenceState) error {
+					CreateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -931,7 +980,7 @@ func TestSequenceStateMaterializedView_OnSequenceTriggered(t *testing.T) {
 			name: "state already exists",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					CreateSequenceStateFunc: func(state models.SequenceState) error {
+					CreateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return db.ErrStateAlreadyExists
 					},
 				},
@@ -947,7 +996,7 @@ func TestSequenceStateMaterializedView_OnSequenceTriggered(t *testing.T) {
 			name: "create state returns an error",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					CreateSequenceStateFunc: func(state models.SequenceState) error {
+					CreateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return errors.New("oops")
 					},
 				},
@@ -1020,9 +1069,9 @@ func TestSequenceStateMaterializedView_OnSequencePaused(t *testing.T) {
 			name: "overall sequence paused",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
@@ -1034,7 +1083,7 @@ func TestSequenceStateMaterializedView_OnSequencePaused(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -1048,16 +1097,16 @@ func TestSequenceStateMaterializedView_OnSequencePaused(t *testing.T) {
 			name: "stage of sequence paused",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
 									Project:        "my-project",
 									Shkeptncontext: "my-context",
 									State:          "triggered",
-									Stages: []models.SequenceStateStage{
+									Stages: []scmodels.SequenceStateStage{
 										{
 											Name: "my-stage",
 										},
@@ -1066,7 +1115,7 @@ func TestSequenceStateMaterializedView_OnSequencePaused(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
@@ -1106,16 +1155,16 @@ func TestSequenceStateMaterializedView_OnSubSequenceFinished(t *testing.T) {
 			name: "abort subsequence",
 			fields: SequenceStateMVTestFields{
 				SequenceStateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "my-sequence",
 									Service:        "my-service",
 									Project:        "my-project",
 									Shkeptncontext: "my-context",
 									State:          "triggered",
-									Stages: []models.SequenceStateStage{
+									Stages: []scmodels.SequenceStateStage{
 										{
 											Name:              "my-stage",
 											LatestEvent:       &models.SequenceStateEvent{},
@@ -1126,7 +1175,7 @@ func TestSequenceStateMaterializedView_OnSubSequenceFinished(t *testing.T) {
 							},
 						}, nil
 					},
-					UpdateSequenceStateFunc: func(state models.SequenceState) error {
+					UpdateSequenceStateFunc: func(state scmodels.SequenceState) error {
 						return nil
 					},
 				},
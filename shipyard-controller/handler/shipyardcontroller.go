@@ -22,6 +22,10 @@ const maxRepoReadRetries = 5
 const couldNotGetActiveSequencesErrMsg = "unable to get active task executions for project %s in stage %s for Keptn context %s: %w"
 const noActiveSequencesErrMsg = "no active task executions for project %s in stage %s for Keptn context %s found"
 
+// SequenceAbortedEventType is the type of the event that is sent when a sequence is aborted, which allows services to cancel their running tasks.
+// It has to match sdk.SequenceAbortedEventType of the go-sdk, which can not be imported since the shipyard-controller is built on its own
+const SequenceAbortedEventType = "sh.keptn.event.sequence.aborted"
+
 var shipyardControllerInstance *shipyardController
 
 //go:generate moq -pkg fake -skip-ensure -out ./fake/shipyardcontroller.go . IShipyardController
@@ -47,6 +51,7 @@ type shipyardController struct {
 	sequenceWaitingHooks       []sequencehooks.ISequenceWaitingHook
 	sequenceTaskTriggeredHooks []sequencehooks.ISequenceTaskTriggeredHook
 	sequenceTaskStartedHooks   []sequencehooks.ISequenceTaskStartedHook
+	sequenceTaskStatusHooks    []sequencehooks.ISequenceTaskStatusChangedHook
 	sequenceTaskFinishedHooks  []sequencehooks.ISequenceTaskFinishedHook
 	subSequenceFinishedHooks   []sequencehooks.ISubSequenceFinishedHook
 	sequenceFinishedHooks      []sequencehooks.ISequenceFinishedHook
@@ -163,6 +168,18 @@ func (sc *shipyardController) HandleIncomingEvent(event apimodels.KeptnContextEx
 			}
 			cb(err)
 		}()
+	case string(common.StatusChangedEvent):
+		go func() {
+			err := sc.handleTaskStatusChanged(event)
+			if err != nil {
+				if errors.Is(err, ErrSequenceNotFound) || errors.Is(err, models.ErrInvalidEventScope) {
+					log.Infof("Unable to handle task status event: %v", err)
+				} else {
+					log.Errorf("Unable to handle task status event: %v", err)
+				}
+			}
+			cb(err)
+		}()
 	case string(common.StartedEvent), string(common.FinishedEvent):
 		go func() {
 			err := sc.handleTaskEvent(event)
@@ -316,6 +333,50 @@ func (sc *shipyardController) handleTaskEvent(event apimodels.KeptnContextExtend
 	return sc.onTaskProgress(event, *sequenceExecution, eventScope)
 }
 
+// handleTaskStatusChanged stores the message and progress of a '.status.changed' event in the state of the current task
+func (sc *shipyardController) handleTaskStatusChanged(event apimodels.KeptnContextExtendedCE) error {
+	eventScope, err := models.NewEventScope(event)
+	if err != nil {
+		return fmt.Errorf("unable to handle 'task.status.changed' event: %w", err)
+	}
+
+	if !models.IsStatusChangedEventType(eventScope.EventType) {
+		return nil
+	}
+
+	sequenceExecution, err := sc.getOpenSequenceExecution(*eventScope)
+	if err != nil {
+		return fmt.Errorf("unable to handle %s event: %w", eventScope.EventType, err)
+	}
+
+	if sequenceExecution == nil {
+		log.Infof("The received %s event with keptn context %s is not accociated with a task that was previously triggered",
+			eventScope.EventType, eventScope.KeptnContext)
+		return ErrSequenceNotFound
+	}
+
+	eventData := models.TaskStatusChangedEventData{}
+	if err := keptnv2.Decode(event.Data, &eventData); err != nil {
+		return fmt.Errorf("unable to decode %s event data: %w", eventScope.EventType, err)
+	}
+
+	taskEvent := models.TaskEvent{
+		EventType: *event.Type,
+		Source:    *event.Source,
+		Result:    eventScope.Result,
+		Status:    eventScope.Status,
+		Time:      timeutils.GetKeptnTimeStamp(event.Time),
+		Message:   eventData.Message,
+		Progress:  eventData.Progress,
+	}
+	if _, err := sc.sequenceExecutionRepo.AppendTaskEvent(*sequenceExecution, taskEvent); err != nil {
+		return err
+	}
+
+	sc.onSequenceTaskStatusChanged(eventScope.WrappedEvent)
+	return nil
+}
+
 func (sc *shipyardController) onTaskProgress(event apimodels.KeptnContextExtendedCE, sequenceExecution models.SequenceExecution, eventScope *models.EventScope) error {
 	taskEvent := models.TaskEvent{
 		EventType: *event.Type,
@@ -401,6 +462,11 @@ func (sc *shipyardController) cancelSequence(cancel apimodels.SequenceControl) e
 		return fmt.Errorf(couldNotGetActiveSequencesErrMsg, cancel.Project, cancel.Stage, cancel.KeptnContext, err)
 	}
 
+	// the services are notified even if there are no active sequence executions, since their tasks may still be running
+	if err := sc.sendSequenceAbortedEvent(cancel); err != nil {
+		log.WithError(err).Errorf("could not send %s event for sequence %s", SequenceAbortedEventType, cancel.KeptnContext)
+	}
+
 	if len(sequenceExecutions) == 0 {
 		log.Infof(noActiveSequencesErrMsg, cancel.Project, cancel.Stage, cancel.KeptnContext)
 		return nil
@@ -435,6 +501,15 @@ func (sc *shipyardController) cancelSequence(cancel apimodels.SequenceControl) e
 	return nil
 }
 
+// sendSequenceAbortedEvent notifies the services that the sequence has been aborted, so they can cancel the tasks they are currently executing
+func (sc *shipyardController) sendSequenceAbortedEvent(cancel apimodels.SequenceControl) error {
+	event := common.CreateEventWithPayload(cancel.KeptnContext, "", SequenceAbortedEventType, keptnv2.EventData{
+		Project: cancel.Project,
+		Stage:   cancel.Stage,
+	})
+	return sc.eventDispatcher.Add(models.DispatcherEvent{TimeStamp: time.Now().UTC(), Event: event}, true)
+}
+
 func (sc *shipyardController) pauseSequence(pause apimodels.SequenceControl) error {
 	scope := models.EventScope{
 		KeptnContext: pause.KeptnContext,
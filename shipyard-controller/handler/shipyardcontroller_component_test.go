@@ -999,6 +999,12 @@ func Test_shipyardController_CancelSequence(t *testing.T) {
 	require.Nil(t, err)
 	require.Len(t, fakeSequenceFinishedHook.OnSequenceFinishedCalls(), 0)
 	require.Len(t, fakeSequenceAbortedHook.OnSequenceAbortedCalls(), 1)
+
+	sentEventTypes := []string{}
+	for _, call := range sc.eventDispatcher.(*fake.IEventDispatcherMock).AddCalls() {
+		sentEventTypes = append(sentEventTypes, call.Event.Event.Type())
+	}
+	require.Contains(t, sentEventTypes, SequenceAbortedEventType)
 }
 
 func Test_shipyardController_CancelQueuedSequence(t *testing.T) {
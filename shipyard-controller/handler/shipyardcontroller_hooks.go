@@ -26,6 +26,10 @@ func (sc *shipyardController) AddSequenceTaskStartedHook(hook sequencehooks.ISeq
 	sc.sequenceTaskStartedHooks = append(sc.sequenceTaskStartedHooks, hook)
 }
 
+func (sc *shipyardController) AddSequenceTaskStatusChangedHook(hook sequencehooks.ISequenceTaskStatusChangedHook) {
+	sc.sequenceTaskStatusHooks = append(sc.sequenceTaskStatusHooks, hook)
+}
+
 func (sc *shipyardController) AddSequenceTaskFinishedHook(hook sequencehooks.ISequenceTaskFinishedHook) {
 	sc.sequenceTaskFinishedHooks = append(sc.sequenceTaskFinishedHooks, hook)
 }
@@ -84,6 +88,12 @@ func (sc *shipyardController) onSequenceTaskTriggered(event models.KeptnContextE
 	}
 }
 
+func (sc *shipyardController) onSequenceTaskStatusChanged(event models.KeptnContextExtendedCE) {
+	for _, hook := range sc.sequenceTaskStatusHooks {
+		hook.OnSequenceTaskStatusChanged(event)
+	}
+}
+
 func (sc *shipyardController) onSequenceTaskFinished(event models.KeptnContextExtendedCE) {
 	for _, hook := range sc.sequenceTaskFinishedHooks {
 		hook.OnSequenceTaskFinished(event)
@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	apimodels "github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/shipyard-controller/common"
 	"github.com/keptn/keptn/shipyard-controller/db"
 	db_mock "github.com/keptn/keptn/shipyard-controller/db/mock"
@@ -205,3 +206,158 @@ func TestHandleTaskEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleTaskStatusChanged(t *testing.T) {
+	eventType := "sh.keptn.event.test.status.changed"
+	source := "jmeter-service"
+	event := apimodels.KeptnContextExtendedCE{
+		Type:           &eventType,
+		Source:         &source,
+		Shkeptncontext: "my-context",
+		Triggeredid:    "my-triggered-id",
+		Data: map[string]interface{}{
+			"project":  "my-project",
+			"stage":    "my-stage",
+			"service":  "my-service",
+			"message":  "executed 40 of 100 requests",
+			"progress": 40,
+		},
+	}
+
+	t.Run("status is appended to the current task", func(t *testing.T) {
+		sequenceExecutionRepo := &db_mock.SequenceExecutionRepoMock{
+			GetFunc: func(filter models.SequenceExecutionFilter) ([]models.SequenceExecution, error) {
+				require.Equal(t, "my-triggered-id", filter.CurrentTriggeredID)
+				return []models.SequenceExecution{{ID: "my-sequence-execution"}}, nil
+			},
+			AppendTaskEventFunc: func(taskSequence models.SequenceExecution, event models.TaskEvent) (*models.SequenceExecution, error) {
+				return &taskSequence, nil
+			},
+		}
+		statusChangedHook := &fakehooks.ISequenceTaskStatusChangedHookMock{OnSequenceTaskStatusChangedFunc: func(event apimodels.KeptnContextExtendedCE) {}}
+		sc := &shipyardController{sequenceExecutionRepo: sequenceExecutionRepo}
+		sc.AddSequenceTaskStatusChangedHook(statusChangedHook)
+
+		err := sc.handleTaskStatusChanged(event)
+
+		require.Nil(t, err)
+		require.Len(t, sequenceExecutionRepo.AppendTaskEventCalls(), 1)
+		taskEvent := sequenceExecutionRepo.AppendTaskEventCalls()[0].Event
+		require.Equal(t, eventType, taskEvent.EventType)
+		require.Equal(t, source, taskEvent.Source)
+		require.Equal(t, "executed 40 of 100 requests", taskEvent.Message)
+		require.Equal(t, 40, taskEvent.Progress)
+		require.Len(t, statusChangedHook.OnSequenceTaskStatusChangedCalls(), 1)
+	})
+
+	t.Run("no matching sequence execution", func(t *testing.T) {
+		sequenceExecutionRepo := &db_mock.SequenceExecutionRepoMock{
+			GetFunc: func(filter models.SequenceExecutionFilter) ([]models.SequenceExecution, error) {
+				return nil, nil
+			},
+		}
+		statusChangedHook := &fakehooks.ISequenceTaskStatusChangedHookMock{OnSequenceTaskStatusChangedFunc: func(event apimodels.KeptnContextExtendedCE) {}}
+		sc := &shipyardController{sequenceExecutionRepo: sequenceExecutionRepo}
+		sc.AddSequenceTaskStatusChangedHook(statusChangedHook)
+
+		err := sc.handleTaskStatusChanged(event)
+
+		require.ErrorIs(t, err, ErrSequenceNotFound)
+		require.Empty(t, sequenceExecutionRepo.AppendTaskEventCalls())
+		require.Empty(t, statusChangedHook.OnSequenceTaskStatusChangedCalls())
+	})
+}
+
+func TestCancelSequence(t *testing.T) {
+	sequenceExecutionRepo := &db_mock.SequenceExecutionRepoMock{
+		GetFunc: func(filter models.SequenceExecutionFilter) ([]models.SequenceExecution, error) {
+			return []models.SequenceExecution{
+				{
+					ID:       "my-sequence-execution",
+					Sequence: keptnv2.Sequence{Name: "delivery"},
+					Status: models.SequenceExecutionStatus{
+						CurrentTask: models.TaskExecutionState{Name: "test", TriggeredID: "my-test-triggered-id"},
+					},
+					Scope: models.EventScope{
+						KeptnContext: "my-context",
+						EventData:    keptnv2.EventData{Project: "my-project", Stage: "my-stage", Service: "my-service"},
+					},
+				},
+			}, nil
+		},
+		UpdateStatusFunc: func(taskSequence models.SequenceExecution) (*models.SequenceExecution, error) {
+			return &taskSequence, nil
+		},
+	}
+	eventRepo := &db_mock.EventRepoMock{
+		DeleteEventFunc: func(project string, eventID string, status common.EventStatus) error {
+			return nil
+		},
+		DeleteAllFinishedEventsFunc: func(eventScope models.EventScope) error {
+			return nil
+		},
+	}
+	eventDispatcher := &fake.IEventDispatcherMock{
+		AddFunc: func(event models.DispatcherEvent, skipQueue bool) error {
+			return nil
+		},
+	}
+	sc := &shipyardController{
+		sequenceExecutionRepo: sequenceExecutionRepo,
+		eventRepo:             eventRepo,
+		eventDispatcher:       eventDispatcher,
+		sequenceDispatcher: &fake.ISequenceDispatcherMock{
+			RemoveFunc: func(eventScope models.EventScope) error {
+				return nil
+			},
+		},
+	}
+
+	err := sc.cancelSequence(apimodels.SequenceControl{
+		KeptnContext: "my-context",
+		Project:      "my-project",
+		Stage:        "my-stage",
+	})
+
+	require.Nil(t, err)
+	require.Len(t, eventDispatcher.AddCalls(), 2)
+
+	abortedEvent := eventDispatcher.AddCalls()[0].Event.Event
+	require.Equal(t, SequenceAbortedEventType, abortedEvent.Type())
+	keptnContext, err := abortedEvent.Context.GetExtension("shkeptncontext")
+	require.Nil(t, err)
+	require.Equal(t, "my-context", keptnContext)
+	eventData := keptnv2.EventData{}
+	require.Nil(t, abortedEvent.DataAs(&eventData))
+	require.Equal(t, "my-project", eventData.Project)
+	require.Equal(t, "my-stage", eventData.Stage)
+
+	finishedEvent := eventDispatcher.AddCalls()[1].Event.Event
+	require.Equal(t, keptnv2.GetFinishedEventType("my-stage.delivery"), finishedEvent.Type())
+}
+
+func TestCancelSequenceWithoutActiveSequenceExecutions(t *testing.T) {
+	sequenceExecutionRepo := &db_mock.SequenceExecutionRepoMock{
+		GetFunc: func(filter models.SequenceExecutionFilter) ([]models.SequenceExecution, error) {
+			return nil, nil
+		},
+	}
+	eventDispatcher := &fake.IEventDispatcherMock{
+		AddFunc: func(event models.DispatcherEvent, skipQueue bool) error {
+			return nil
+		},
+	}
+	sc := &shipyardController{
+		sequenceExecutionRepo: sequenceExecutionRepo,
+		eventDispatcher:       eventDispatcher,
+	}
+
+	err := sc.cancelSequence(apimodels.SequenceControl{
+		KeptnContext: "my-context",
+		Project:      "my-project",
+	})
+
+	require.Nil(t, err)
+	require.Len(t, eventDispatcher.AddCalls(), 1)
+	require.Equal(t, SequenceAbortedEventType, eventDispatcher.AddCalls()[0].Event.Event.Type())
+}
@@ -42,7 +42,7 @@ func NewStateHandler(stateRepo db.SequenceStateRepo, shipyardController IShipyar
 // @Param	pageSize			query	int		false	"The number of items to return"
 // @Param   nextPageKey     	query   string  false	"Pointer to the next set of items"
 // @Param   keptnContext		query	string	false	"Comma separated list of keptnContext IDs"
-// @Success 200 {object} apimodels.SequenceStates	"ok"
+// @Success 200 {object} models.SequenceStates	"ok"
 // @Failure 400 {object} models.Error "Invalid payload"
 // @Failure 500 {object} models.Error "Internal error"
 // @Router /sequence/{project} [get]
@@ -7,6 +7,7 @@ import (
 	"github.com/keptn/go-utils/pkg/common/timeutils"
 	db_mock "github.com/keptn/keptn/shipyard-controller/db/mock"
 	"github.com/keptn/keptn/shipyard-controller/handler"
+	scmodels "github.com/keptn/keptn/shipyard-controller/models"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -28,14 +29,14 @@ func TestStateHandler_GetState(t *testing.T) {
 			name: "state repo returns states",
 			fields: fields{
 				StateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
 						require.Equal(t, "sequenceName", filter.Name)
 						require.Equal(t, "sequenceState", filter.State)
 						require.Equal(t, "2021-05-10T09:51:00.000Z", filter.FromTime)
 						require.Equal(t, "2021-05-10T09:50:00.000Z", filter.BeforeTime)
 						require.Equal(t, "my-context", filter.KeptnContext)
-						return &models.SequenceStates{
-							States: []models.SequenceState{
+						return &scmodels.SequenceStates{
+							States: []scmodels.SequenceState{
 								{
 									Name:           "delivery",
 									Service:        "my-service",
@@ -60,7 +61,7 @@ func TestStateHandler_GetState(t *testing.T) {
 			name: "state repo returns error",
 			fields: fields{
 				StateRepo: &db_mock.SequenceStateRepoMock{
-					FindSequenceStatesFunc: func(filter models.StateFilter) (*models.SequenceStates, error) {
+					FindSequenceStatesFunc: func(filter models.StateFilter) (*scmodels.SequenceStates, error) {
 						return nil, errors.New("oops")
 					},
 				},
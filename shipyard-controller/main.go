@@ -219,6 +219,7 @@ func main() {
 	shipyardController.AddSequenceTaskTriggeredHook(projectMVRepo)
 	shipyardController.AddSequenceTaskStartedHook(sequenceStateMaterializedView)
 	shipyardController.AddSequenceTaskStartedHook(projectMVRepo)
+	shipyardController.AddSequenceTaskStatusChangedHook(sequenceStateMaterializedView)
 	shipyardController.AddSequenceTaskFinishedHook(sequenceStateMaterializedView)
 	shipyardController.AddSequenceTaskFinishedHook(projectMVRepo)
 	shipyardController.AddSubSequenceFinishedHook(sequenceStateMaterializedView)
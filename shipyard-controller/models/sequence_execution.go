@@ -4,6 +4,7 @@ import (
 	"github.com/keptn/go-utils/pkg/api/models"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/shipyard-controller/common"
+	"strings"
 )
 
 // SequenceExecution contains all required information needed by the shipyard controller on how to preceed within a task sequence.
@@ -251,6 +252,22 @@ type TaskEvent struct {
 	Status     keptnv2.StatusType     `json:"status" bson:"status"`
 	Time       string                 `json:"time" bson:"time"`
 	Properties map[string]interface{} `json:"properties" bson:"properties"`
+	// Message contains the message of a task's '.status.changed' event
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+	// Progress contains the percentage of completion reported by a task's '.status.changed' event
+	Progress int `json:"progress,omitempty" bson:"progress,omitempty"`
+}
+
+// TaskStatusChangedEventData is the data of a '.status.changed' event, which reports the progress of a running task
+type TaskStatusChangedEventData struct {
+	keptnv2.EventData
+	// Progress is the percentage of completion of the task
+	Progress int `json:"progress,omitempty"`
+}
+
+// IsStatusChangedEventType checks whether the given event type is the type of a task's '.status.changed' event
+func IsStatusChangedEventType(eventType string) bool {
+	return strings.HasSuffix(eventType, ".status.changed")
 }
 
 type SequenceExecutionFilter struct {
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"github.com/keptn/go-utils/pkg/api/models"
+)
+
+// SequenceStateStatus contains the message and progress reported by the latest '.status.changed' event of a running task
+type SequenceStateStatus struct {
+	EventID  string `json:"eventId" bson:"eventId"`
+	Message  string `json:"message,omitempty" bson:"message"`
+	Progress int    `json:"progress,omitempty" bson:"progress"`
+	Time     string `json:"time" bson:"time"`
+}
+
+// SequenceStateStage represent current state of a stage in a sequence
+type SequenceStateStage struct {
+	Name              string                          `json:"name" bson:"name"`
+	Image             string                          `json:"image,omitempty" bson:"image"`
+	State             string                          `json:"state" bson:"state"`
+	LatestEvaluation  *models.SequenceStateEvaluation `json:"latestEvaluation,omitempty" bson:"latestEvaluation"`
+	LatestEvent       *models.SequenceStateEvent      `json:"latestEvent,omitempty" bson:"latestEvent"`
+	LatestFailedEvent *models.SequenceStateEvent      `json:"latestFailedEvent,omitempty" bson:"latestFailedEvent"`
+	LatestStatus      *SequenceStateStatus            `json:"latestStatus,omitempty" bson:"latestStatus,omitempty"`
+}
+
+// SequenceState represent the current state of a sequence
+type SequenceState struct {
+	Name           string               `json:"name" bson:"name"`
+	Service        string               `json:"service" bson:"service"`
+	Project        string               `json:"project" bson:"project"`
+	Time           string               `json:"time" bson:"time"`
+	Shkeptncontext string               `json:"shkeptncontext" bson:"shkeptncontext"`
+	State          string               `json:"state" bson:"state"`
+	Stages         []SequenceStateStage `json:"stages" bson:"stages"`
+	ProblemTitle   string               `json:"problemTitle,omitempty" bson:"problemTitle"`
+}
+
+// SequenceStates collects all states of a sequence
+type SequenceStates struct {
+	States []SequenceState `json:"states"`
+	// Pointer to next page
+	NextPageKey int64 `json:"nextPageKey,omitempty"`
+
+	// Size of returned page
+	PageSize int64 `json:"pageSize,omitempty"`
+
+	// Total number of events
+	TotalCount int64 `json:"totalCount,omitempty"`
+}
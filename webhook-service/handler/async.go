@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/go-sdk/pkg/sdk"
+	"github.com/keptn/keptn/webhook-service/lib"
+	logger "github.com/sirupsen/logrus"
+)
+
+// callbackPath is the path of the endpoint that receives the callbacks of asynchronous jobs, followed by the job ID
+const callbackPath = "/v1/callback/"
+
+// asyncJobManager tracks the jobs started by asynchronous webhooks until they are completed or timed out
+type asyncJobManager struct {
+	taskHandler     *TaskHandler
+	store           lib.IJobStore
+	callbackBaseURL string
+	jobs            map[string]*trackedJob
+	mutex           sync.Mutex
+}
+
+type trackedJob struct {
+	job          *lib.Job
+	keptnHandler sdk.IKeptn
+	cancel       context.CancelFunc
+}
+
+// WithAsyncJobs enables asynchronous webhooks. Pending jobs are persisted in the given store, and callbacks are expected at
+// <callbackBaseURL>/v1/callback/<job-id>. Polling requests are executed via the IRequestExecutor set by WithRequestExecutor
+func WithAsyncJobs(store lib.IJobStore, callbackBaseURL string) TaskHandlerOption {
+	return func(taskHandler *TaskHandler) {
+		taskHandler.asyncJobs = &asyncJobManager{
+			taskHandler:     taskHandler,
+			store:           store,
+			callbackBaseURL: strings.TrimSuffix(callbackBaseURL, "/"),
+			jobs:            map[string]*trackedJob{},
+		}
+	}
+}
+
+// ResumeAsyncJobs continues to track the pending jobs of the store, e.g. after a restart of the service.
+// Jobs that exceeded their deadline in the meantime are reported as timed out
+func (th *TaskHandler) ResumeAsyncJobs(keptnHandler sdk.IKeptn) error {
+	if th.asyncJobs == nil {
+		return nil
+	}
+	jobs, err := th.asyncJobs.store.List()
+	if err != nil {
+		return fmt.Errorf("could not load pending jobs: %w", err)
+	}
+	for _, job := range jobs {
+		logger.Infof("resuming asynchronous job %s", job.ID)
+		th.asyncJobs.track(job, keptnHandler)
+	}
+	return nil
+}
+
+// CallbackHandler returns the http.Handler receiving the callbacks of asynchronous jobs
+func (th *TaskHandler) CallbackHandler() http.Handler {
+	if th.asyncJobs == nil {
+		return http.NotFoundHandler()
+	}
+	return th.asyncJobs
+}
+
+func (m *asyncJobManager) newJob(event sdk.KeptnEvent, webhook lib.Webhook) (*lib.Job, error) {
+	timeout, err := webhook.Async.GetTimeout()
+	if err != nil {
+		return nil, err
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("could not generate callback token: %w", err)
+	}
+	return &lib.Job{
+		ID:       uuid.New().String(),
+		Token:    hex.EncodeToString(token),
+		Event:    event,
+		Webhook:  webhook,
+		Deadline: time.Now().Add(timeout),
+	}, nil
+}
+
+func (m *asyncJobManager) callbackURL(job *lib.Job) string {
+	return fmt.Sprintf("%s%s%s?token=%s", m.callbackBaseURL, callbackPath, job.ID, job.Token)
+}
+
+// start persists the job and tracks it until it is completed
+func (m *asyncJobManager) start(job *lib.Job, keptnHandler sdk.IKeptn) error {
+	if err := m.store.Save(job); err != nil {
+		return fmt.Errorf("could not store asynchronous job: %w", err)
+	}
+	m.track(job, keptnHandler)
+	return nil
+}
+
+func (m *asyncJobManager) track(job *lib.Job, keptnHandler sdk.IKeptn) {
+	ctx, cancel := context.WithDeadline(context.Background(), job.Deadline)
+	m.mutex.Lock()
+	m.jobs[job.ID] = &trackedJob{job: job, keptnHandler: keptnHandler, cancel: cancel}
+	m.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			m.complete(job.ID, func(job *lib.Job, eventAdapter *lib.EventDataAdapter) map[string]interface{} {
+				return m.failedResult(job, eventAdapter, keptnv2.StatusErrored, fmt.Sprintf("asynchronous webhook job %s timed out", job.ID))
+			})
+		}
+	}()
+	if job.Webhook.Async.Poll != nil {
+		go m.poll(ctx, job)
+	}
+}
+
+func (m *asyncJobManager) poll(ctx context.Context, job *lib.Job) {
+	poll := job.Webhook.Async.Poll
+	interval, err := poll.GetInterval()
+	if err != nil {
+		interval = lib.DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		response, err := m.executePollRequest(job)
+		if err != nil {
+			logger.WithError(err).Warnf("poll request of asynchronous job %s failed", job.ID)
+			continue
+		}
+		if _, err := (&lib.ResponseSpec{Assertions: poll.Until}).Evaluate(*response); err != nil {
+			if !lib.IsAssertionError(err) {
+				logger.WithError(err).Warnf("poll request of asynchronous job %s failed", job.ID)
+			}
+			continue
+		}
+		m.completeWithResponse(job.ID, *response, poll.Request.Response)
+		return
+	}
+}
+
+func (m *asyncJobManager) executePollRequest(job *lib.Job) (*lib.Response, error) {
+	if m.taskHandler.requestExecutor == nil {
+		return nil, errors.New("no request executor available")
+	}
+	eventAdapter, err := lib.NewEventDataAdapter(job.Event)
+	if err != nil {
+		return nil, err
+	}
+	secretEnvVars, err := m.taskHandler.gatherSecretEnvVars(job.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	eventAdapter.Add("env", secretEnvVars)
+	if len(job.NamedResponses) > 0 {
+		eventAdapter.Add("responses", job.NamedResponses)
+	}
+	response, _, err := m.taskHandler.performRequest(job.Webhook.Async.Poll.Request, eventAdapter)
+	if err != nil {
+		return nil, errors.New(removeSecretsFromMessage(err.Error(), secretEnvVars))
+	}
+	return response, nil
+}
+
+// ServeHTTP receives the callback of an asynchronous job and completes the job using the request body as final response
+func (m *asyncJobManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, callbackPath) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	jobID := strings.TrimPrefix(r.URL.Path, callbackPath)
+
+	m.mutex.Lock()
+	tracked, ok := m.jobs[jobID]
+	m.mutex.Unlock()
+	if !ok || tracked.job.Webhook.Async.Callback == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(tracked.job.Token)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, lib.DefaultMaxResponseSize+1))
+	if err != nil || len(body) > lib.DefaultMaxResponseSize {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// the status code of the callback is not relevant, the body is evaluated by the assertions of the response spec
+	m.completeWithResponse(jobID, lib.Response{Body: string(body)}, tracked.job.Webhook.Async.Callback.Response)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *asyncJobManager) completeWithResponse(jobID string, response lib.Response, responseSpec *lib.ResponseSpec) {
+	m.complete(jobID, func(job *lib.Job, eventAdapter *lib.EventDataAdapter) map[string]interface{} {
+		responses := append(append([]string{}, job.Responses...), response.Body)
+		outputs, err := responseSpec.Evaluate(response)
+		result := m.finishedEventData(job, eventAdapter, requestResults{responses: responses, outputs: mergeOutputs(job.Outputs, outputs), attempts: job.Attempts})
+		if err != nil {
+			result["result"] = keptnv2.ResultFailed
+			result["status"] = keptnv2.StatusErrored
+			if lib.IsAssertionError(err) {
+				result["status"] = keptnv2.StatusSucceeded
+			}
+			result["message"] = err.Error()
+		}
+		return result
+	})
+}
+
+// complete stops tracking the job, removes it from the store, and sends the .finished event with the data returned by getResult.
+// Each job is completed only once, i.e. subsequent calls are ignored
+func (m *asyncJobManager) complete(jobID string, getResult func(job *lib.Job, eventAdapter *lib.EventDataAdapter) map[string]interface{}) {
+	m.mutex.Lock()
+	tracked, ok := m.jobs[jobID]
+	delete(m.jobs, jobID)
+	m.mutex.Unlock()
+	if !ok {
+		return
+	}
+	tracked.cancel()
+
+	if err := m.store.Delete(jobID); err != nil && !errors.Is(err, lib.ErrJobNotFound) {
+		logger.WithError(err).Errorf("could not remove asynchronous job %s", jobID)
+	}
+	eventAdapter, err := lib.NewEventDataAdapter(tracked.job.Event)
+	if err != nil {
+		logger.WithError(err).Errorf("could not complete asynchronous job %s", jobID)
+		return
+	}
+	logger.Infof("completed asynchronous job %s", jobID)
+	m.taskHandler.sendFinishedEvent(tracked.keptnHandler, tracked.job.Event, getResult(tracked.job, eventAdapter))
+}
+
+func (m *asyncJobManager) failedResult(job *lib.Job, eventAdapter *lib.EventDataAdapter, status keptnv2.StatusType, message string) map[string]interface{} {
+	result := m.finishedEventData(job, eventAdapter, requestResults{responses: job.Responses, outputs: job.Outputs, attempts: job.Attempts})
+	result["result"] = keptnv2.ResultFailed
+	result["status"] = status
+	result["message"] = message
+	return result
+}
+
+func (m *asyncJobManager) finishedEventData(job *lib.Job, eventAdapter *lib.EventDataAdapter, results requestResults) map[string]interface{} {
+	// jobs are only created for <task>.triggered events, so the task name can always be derived
+	taskName, _, _ := keptnv2.ParseTaskEventType(*job.Event.Type)
+	return newFinishedEventData(taskName, eventAdapter, results)
+}
+
+func mergeOutputs(outputs ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, o := range outputs {
+		for name, value := range o {
+			merged[name] = value
+		}
+	}
+	return merged
+}
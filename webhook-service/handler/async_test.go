@@ -0,0 +1,294 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/go-sdk/pkg/sdk"
+	"github.com/keptn/keptn/webhook-service/handler"
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/keptn/keptn/webhook-service/lib/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const webHookContentBetaWithPolling = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - name: startJob
+          url: http://local:8080/jobs
+          method: POST
+      async:
+        poll:
+          interval: 10ms
+          request:
+            url: http://local:8080/jobs/{{.responses.startJob.id}}
+            method: GET
+            response:
+              assertions:
+                - jsonPath: $.result
+                  equals: success
+              outputs:
+                - name: reportURL
+                  jsonPath: $.report
+          until:
+            - jsonPath: $.state
+              equals: done`
+
+const webHookContentBetaWithCallback = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://local:8080/jobs
+          method: POST
+          payload: '{"callback": "{{.callback.url}}"}'
+      async:
+        timeout: %s
+        callback:
+          response:
+            assertions:
+              - jsonPath: $.result
+                equals: success`
+
+// newInMemoryJobStore returns an IJobStore keeping the jobs in a map
+func newInMemoryJobStore() *fake.IJobStoreMock {
+	jobs := map[string]*lib.Job{}
+	mutex := sync.Mutex{}
+	return &fake.IJobStoreMock{
+		SaveFunc: func(job *lib.Job) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			jobs[job.ID] = job
+			return nil
+		},
+		GetFunc: func(id string) (*lib.Job, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if job, ok := jobs[id]; ok {
+				return job, nil
+			}
+			return nil, lib.ErrJobNotFound
+		},
+		DeleteFunc: func(id string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if _, ok := jobs[id]; !ok {
+				return lib.ErrJobNotFound
+			}
+			delete(jobs, id)
+			return nil
+		},
+		ListFunc: func() ([]*lib.Job, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			result := []*lib.Job{}
+			for _, job := range jobs {
+				result = append(result, job)
+			}
+			return result, nil
+		},
+	}
+}
+
+func newAsyncTaskHandler(jobStore lib.IJobStore, executeFunc func(request lib.Request) (*lib.Response, error)) (*handler.TaskHandler, *fake.IRequestExecutorMock) {
+	return newRequestExecutorTaskHandler(executeFunc, handler.WithAsyncJobs(jobStore, "http://webhook-service:8082"))
+}
+
+func getSentEventData(t *testing.T, fakeKeptn *sdk.FakeKeptn, index int) map[string]interface{} {
+	return getEventData(t, fakeKeptn.GetEventSender().SentEvents[index])
+}
+
+func getEventData(t *testing.T, event cloudevents.Event) map[string]interface{} {
+	finishedEvent, err := keptnv2.ToKeptnEvent(event)
+	require.Nil(t, err)
+	eventData := map[string]interface{}{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, &eventData))
+	return eventData
+}
+
+// recordFinishedEvents returns a function providing the .finished events sent asynchronously by the given fake keptn.
+// The events are recorded by a reactor, since the sent events of the fake event sender can not be read while they are sent
+func recordFinishedEvents(fakeKeptn *sdk.FakeKeptn) func() []cloudevents.Event {
+	events := []cloudevents.Event{}
+	mutex := sync.Mutex{}
+	fakeKeptn.GetEventSender().AddReactor("sh.keptn.event.webhook.finished", func(event cloudevents.Event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, event)
+		return nil
+	})
+	return func() []cloudevents.Event {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]cloudevents.Event{}, events...)
+	}
+}
+
+func TestTaskHandler_Execute_AsyncPolling(t *testing.T) {
+	jobStore := newInMemoryJobStore()
+	polls := 0
+	mutex := sync.Mutex{}
+	taskHandler, _ := newAsyncTaskHandler(jobStore, func(request lib.Request) (*lib.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch request.URL {
+		case "http://local:8080/jobs":
+			return &lib.Response{StatusCode: 201, Body: `{"id": "42"}`}, nil
+		case "http://local:8080/jobs/42":
+			polls++
+			if polls < 3 {
+				return &lib.Response{StatusCode: 200, Body: `{"state": "running"}`}, nil
+			}
+			return &lib.Response{StatusCode: 200, Body: `{"state": "done", "result": "success", "report": "http://reports/42"}`}, nil
+		}
+		return &lib.Response{StatusCode: 404}, nil
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithPolling})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	finishedEvents := recordFinishedEvents(fakeKeptn)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	// the .finished event is only sent once the job is done
+	require.Len(t, jobStore.SaveCalls(), 1)
+	require.Eventually(t, func() bool {
+		return len(finishedEvents()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(t, jobStore.DeleteCalls(), 1)
+	eventData := getEventData(t, finishedEvents()[0])
+	assert.Equal(t, string(keptnv2.ResultPass), eventData["result"])
+	assert.Equal(t, map[string]interface{}{
+		"responses": []interface{}{`{"id": "42"}`, `{"state": "done", "result": "success", "report": "http://reports/42"}`},
+		"reportURL": "http://reports/42",
+	}, eventData["webhook"])
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, 3, polls)
+}
+
+func TestTaskHandler_Execute_AsyncCallback(t *testing.T) {
+	jobStore := newInMemoryJobStore()
+	taskHandler, requestExecutorMock := newAsyncTaskHandler(jobStore, func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 202, Body: "accepted"}, nil
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: strings.Replace(webHookContentBetaWithCallback, "%s", "1h", 1)})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, jobStore.SaveCalls(), 1)
+	job := jobStore.SaveCalls()[0].Job
+	callbackURL := "http://webhook-service:8082/v1/callback/" + job.ID + "?token=" + job.Token
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	assert.Equal(t, `{"callback": "`+callbackURL+`"}`, requestExecutorMock.ExecuteCalls()[0].Request.Payload)
+	require.Equal(t, 1, len(fakeKeptn.GetEventSender().SentEvents))
+
+	callbackHandler := taskHandler.CallbackHandler()
+	sendCallback := func(url string, body string) int {
+		recorder := httptest.NewRecorder()
+		callbackHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, url, strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, sendCallback("/v1/callback/"+job.ID+"?token=invalid", `{"result": "success"}`))
+	assert.Equal(t, http.StatusNotFound, sendCallback("/v1/callback/unknown?token="+job.Token, `{"result": "success"}`))
+	require.Equal(t, 1, len(fakeKeptn.GetEventSender().SentEvents))
+
+	assert.Equal(t, http.StatusAccepted, sendCallback("/v1/callback/"+job.ID+"?token="+job.Token, `{"result": "failure"}`))
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	eventData := getSentEventData(t, fakeKeptn, 1)
+	assert.Equal(t, string(keptnv2.ResultFailed), eventData["result"])
+	assert.Equal(t, string(keptnv2.StatusSucceeded), eventData["status"])
+	assert.Contains(t, eventData["message"], "assertion on '$.result' failed")
+	require.Len(t, jobStore.DeleteCalls(), 1)
+
+	// the job is completed only once
+	assert.Equal(t, http.StatusNotFound, sendCallback("/v1/callback/"+job.ID+"?token="+job.Token, `{"result": "success"}`))
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+}
+
+func TestTaskHandler_Execute_AsyncTimeout(t *testing.T) {
+	jobStore := newInMemoryJobStore()
+	taskHandler, _ := newAsyncTaskHandler(jobStore, func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 202, Body: "accepted"}, nil
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: strings.Replace(webHookContentBetaWithCallback, "%s", "50ms", 1)})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	finishedEvents := recordFinishedEvents(fakeKeptn)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Eventually(t, func() bool {
+		return len(finishedEvents()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(t, jobStore.DeleteCalls(), 1)
+	eventData := getEventData(t, finishedEvents()[0])
+	assert.Equal(t, string(keptnv2.ResultFailed), eventData["result"])
+	assert.Equal(t, string(keptnv2.StatusErrored), eventData["status"])
+	assert.Contains(t, eventData["message"], "timed out")
+	assert.Equal(t, map[string]interface{}{"responses": []interface{}{"accepted"}}, eventData["webhook"])
+}
+
+func TestTaskHandler_ResumeAsyncJobs(t *testing.T) {
+	jobStore := newInMemoryJobStore()
+	taskHandler, _ := newAsyncTaskHandler(jobStore, func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 202, Body: "accepted"}, nil
+	})
+
+	event := sdk.KeptnEvent{}
+	triggeredEvent := newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json")
+	require.Nil(t, keptnv2.Decode(&triggeredEvent, &event))
+
+	// a job that was pending while the service was restarted
+	require.Nil(t, jobStore.Save(&lib.Job{
+		ID:        "my-job",
+		Token:     "my-token",
+		Event:     event,
+		Webhook:   lib.Webhook{Async: &lib.AsyncSpec{Callback: &lib.CallbackSpec{}}},
+		Responses: []string{"accepted"},
+		Deadline:  time.Now().Add(time.Hour),
+	}))
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	require.Nil(t, taskHandler.ResumeAsyncJobs(fakeKeptn))
+
+	recorder := httptest.NewRecorder()
+	taskHandler.CallbackHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/callback/my-job?token=my-token", strings.NewReader("done")))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	require.Equal(t, 1, len(fakeKeptn.GetEventSender().SentEvents))
+	assert.Equal(t, "sh.keptn.event.webhook.finished", fakeKeptn.GetEventSender().SentEvents[0].Type())
+	eventData := getSentEventData(t, fakeKeptn, 0)
+	assert.Equal(t, string(keptnv2.ResultPass), eventData["result"])
+	assert.Equal(t, map[string]interface{}{"responses": []interface{}{"accepted", "done"}}, eventData["webhook"])
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	keptn "github.com/keptn/go-utils/pkg/api/utils"
 
@@ -24,17 +25,42 @@ type SecretEnv struct {
 type TaskHandler struct {
 	templateEngine   lib.ITemplateEngine
 	curlExecutor     lib.ICurlExecutor
+	requestExecutor  lib.IRequestExecutor
 	requestValidator lib.RequestValidator
 	secretReader     lib.ISecretReader
+	asyncJobs        *asyncJobManager
+	egressRules      lib.EgressRuleProvider
 }
 
-func NewTaskHandler(templateEngine lib.ITemplateEngine, curlExecutor lib.ICurlExecutor, requestValidator lib.RequestValidator, secretReader lib.ISecretReader) *TaskHandler {
-	return &TaskHandler{
+type TaskHandlerOption func(taskHandler *TaskHandler)
+
+// WithRequestExecutor executes v1beta1 requests with the given executor instead of converting them to curl commands.
+// Requests that contain curl options are still executed via the ICurlExecutor
+func WithRequestExecutor(requestExecutor lib.IRequestExecutor) TaskHandlerOption {
+	return func(taskHandler *TaskHandler) {
+		taskHandler.requestExecutor = requestExecutor
+	}
+}
+
+// WithEgressRules rejects requests that are executed via curl if egress rules apply to the project of the webhook,
+// since the target of curl commands can not be evaluated against the rules
+func WithEgressRules(egressRuleProvider lib.EgressRuleProvider) TaskHandlerOption {
+	return func(taskHandler *TaskHandler) {
+		taskHandler.egressRules = egressRuleProvider
+	}
+}
+
+func NewTaskHandler(templateEngine lib.ITemplateEngine, curlExecutor lib.ICurlExecutor, requestValidator lib.RequestValidator, secretReader lib.ISecretReader, opts ...TaskHandlerOption) *TaskHandler {
+	taskHandler := &TaskHandler{
 		templateEngine:   templateEngine,
 		curlExecutor:     curlExecutor,
 		requestValidator: requestValidator,
 		secretReader:     secretReader,
 	}
+	for _, o := range opts {
+		o(taskHandler)
+	}
+	return taskHandler
 }
 
 func (th *TaskHandler) Execute(keptnHandler sdk.IKeptn, event sdk.KeptnEvent) (interface{}, *sdk.Error) {
@@ -66,27 +92,57 @@ func (th *TaskHandler) Execute(keptnHandler sdk.IKeptn, event sdk.KeptnEvent) (i
 		return nil, sdkError(removeSecretsFromMessage(err.Error(), secretEnvVars), err)
 	}
 	eventAdapter.Add("env", secretEnvVars)
-	responses, err = th.performWebhookRequests(*webhook, eventAdapter, responses)
-	if err != nil {
-		onError(err, secretEnvVars)
-		return nil, sdkError(removeSecretsFromMessage(err.Error(), secretEnvVars), err)
-	}
 
 	// check if the incoming event was a task.triggered event, and if the 'sendFinished'  property of the webhook was set to true
 	// only in this case, the result should be sent back to Keptn in the form of a .finished event
-	if keptnv2.IsTaskEventType(*event.Type) && keptnv2.IsTriggeredEventType(*event.Type) && webhook.ShouldSendFinishedEvent() {
+	sendFinished := keptnv2.IsTaskEventType(*event.Type) && keptnv2.IsTriggeredEventType(*event.Type) && webhook.ShouldSendFinishedEvent()
+
+	var job *lib.Job
+	if webhook.Async != nil && sendFinished {
+		if job, err = th.newAsyncJob(event, *webhook, eventAdapter); err != nil {
+			onError(err, secretEnvVars)
+			return nil, sdkError(err.Error(), err)
+		}
+	}
+
+	results, execErr := th.performWebhookRequests(*webhook, eventAdapter, responses)
+	// failed assertions are reported in the .finished event containing the responses, rather than as an error
+	failedAssertion := execErr != nil && lib.IsAssertionError(execErr) && sendFinished
+	if execErr != nil && !failedAssertion {
+		onError(execErr, secretEnvVars)
+		return nil, sdkError(removeSecretsFromMessage(execErr.Error(), secretEnvVars), execErr)
+	}
+
+	if job != nil && !failedAssertion {
+		// the .finished event is sent once the job has been completed
+		job.Responses = results.responses
+		job.Outputs = results.outputs
+		job.Attempts = results.attempts
+		if namedResponses, ok := eventAdapter.Get()["responses"].(map[string]interface{}); ok {
+			job.NamedResponses = namedResponses
+		}
+		// the job is trimmed before it is persisted, so it behaves the same after a restart of the webhook-service
+		job.Trim()
+		if err := th.asyncJobs.start(job, keptnHandler); err != nil {
+			onError(lib.NewWebhookExecutionError(true, err, lib.WithNrOfExecutedRequests(len(webhook.Requests))), secretEnvVars)
+			return nil, sdkError(err.Error(), err)
+		}
+		logger.Infof("started asynchronous job %s", job.ID)
+		return nil, nil
+	}
+
+	if sendFinished {
 		taskName, _, err := keptnv2.ParseTaskEventType(*event.Type)
 		if err != nil {
 			return nil, sdkError(fmt.Sprintf("could not derive task name from event type %s", *event.Type), err)
 		}
-		result := map[string]interface{}{
-			"project": eventAdapter.Project(),
-			"stage":   eventAdapter.Stage(),
-			"service": eventAdapter.Service(),
-			"labels":  eventAdapter.Labels(),
-			taskName: map[string]interface{}{
-				"responses": responses,
-			},
+		result := newFinishedEventData(taskName, eventAdapter, results)
+		if failedAssertion {
+			message := removeSecretsFromMessage(execErr.Error(), secretEnvVars)
+			logger.Infof("webhook assertion failed: %s", message)
+			result["result"] = keptnv2.ResultFailed
+			result["status"] = keptnv2.StatusSucceeded
+			result["message"] = message
 		}
 		err = keptnHandler.SendFinishedEvent(event, result)
 		if err != nil {
@@ -98,6 +154,40 @@ func (th *TaskHandler) Execute(keptnHandler sdk.IKeptn, event sdk.KeptnEvent) (i
 	return nil, nil
 }
 
+// newAsyncJob creates the job of an asynchronous webhook and makes its callback URL available to the requests via {{.callback.url}}
+func (th *TaskHandler) newAsyncJob(event sdk.KeptnEvent, webhook lib.Webhook, eventAdapter *lib.EventDataAdapter) (*lib.Job, error) {
+	if th.asyncJobs == nil {
+		return nil, lib.NewWebhookExecutionError(true, errors.New("asynchronous webhooks are not supported by this webhook-service"))
+	}
+	job, err := th.asyncJobs.newJob(event, webhook)
+	if err != nil {
+		return nil, lib.NewWebhookExecutionError(true, err)
+	}
+	if webhook.Async.Callback != nil {
+		eventAdapter.Add("callback", map[string]interface{}{"url": th.asyncJobs.callbackURL(job)})
+	}
+	return job, nil
+}
+
+func newFinishedEventData(taskName string, eventAdapter *lib.EventDataAdapter, results requestResults) map[string]interface{} {
+	taskResult := map[string]interface{}{
+		"responses": results.responses,
+	}
+	if len(results.attempts) > 0 {
+		taskResult["attempts"] = results.attempts
+	}
+	for name, value := range results.outputs {
+		taskResult[name] = value
+	}
+	return map[string]interface{}{
+		"project": eventAdapter.Project(),
+		"stage":   eventAdapter.Stage(),
+		"service": eventAdapter.Service(),
+		"labels":  eventAdapter.Labels(),
+		taskName:  taskResult,
+	}
+}
+
 func (th *TaskHandler) onPreExecutionError(keptnHandler sdk.IKeptn, event sdk.KeptnEvent, eventAdapter *lib.EventDataAdapter, err error) (interface{}, *sdk.Error) {
 	// in this case, send .started and .finished event immediately
 	if err := keptnHandler.SendStartedEvent(event); err != nil {
@@ -119,8 +209,12 @@ func (th *TaskHandler) onPreExecutionError(keptnHandler sdk.IKeptn, event sdk.Ke
 
 func (th *TaskHandler) getErrorCallbackForWebhookConfig(keptnHandler sdk.IKeptn, event sdk.KeptnEvent, eventAdapter *lib.EventDataAdapter, webhook *lib.Webhook) func(err error, secrets map[string]string) {
 	return func(err error, secrets map[string]string) {
-		logger.WithError(err).Error("error during webhook execution")
 		whe, ok := err.(*lib.WebhookExecutionError)
+		if ok && len(whe.Attempts) > 0 {
+			logger.WithError(err).WithField("attempts", whe.Attempts).Error("error during webhook execution")
+		} else {
+			logger.WithError(err).Error("error during webhook execution")
+		}
 
 		result := map[string]interface{}{
 			"project": eventAdapter.Project(),
@@ -131,6 +225,15 @@ func (th *TaskHandler) getErrorCallbackForWebhookConfig(keptnHandler sdk.IKeptn,
 			"status":  keptnv2.StatusErrored,
 			"message": removeSecretsFromMessage(err.Error(), secrets),
 		}
+		if lib.IsAssertionError(err) {
+			// the webhook has been executed successfully, but the response did not match the expectations
+			result["status"] = keptnv2.StatusSucceeded
+		}
+		if ok && len(whe.Attempts) > 0 {
+			if taskName, _, err := keptnv2.ParseTaskEventType(*event.Type); err == nil {
+				result[taskName] = map[string]interface{}{"attempts": whe.Attempts}
+			}
+		}
 
 		if ok && whe.PreExecutionError {
 			if webhook.ShouldSendFinishedEvent() {
@@ -194,29 +297,180 @@ func (th *TaskHandler) onStartedWebhookExecution(keptnHandler sdk.IKeptn, event
 	return nil
 }
 
-func (th *TaskHandler) performWebhookRequests(webhook lib.Webhook, eventAdapter *lib.EventDataAdapter, responses []string) ([]string, error) {
+// requestResults are the results of the executed requests of a webhook
+type requestResults struct {
+	responses []string
+	outputs   map[string]interface{}
+	// attempts contains the number of attempts of each request, if any request of the webhook declares a retry policy
+	attempts []int
+}
+
+func (th *TaskHandler) performWebhookRequests(webhook lib.Webhook, eventAdapter *lib.EventDataAdapter, responses []string) (requestResults, error) {
 	executedRequests := 0
+	results := requestResults{responses: responses, outputs: map[string]interface{}{}}
+	attempts := []int{}
+	hasRetryPolicy := false
+	namedResponses := map[string]interface{}{}
 	logger.Infof("executing webhooks for subscriptionID %s", webhook.SubscriptionID)
 	for _, req := range webhook.Requests {
-		request, err := th.CreateRequest(req)
-		if err != nil {
-			logger.Infof("creating CURL request failed: %s", err.Error())
-			return nil, lib.NewWebhookExecutionError(true, fmt.Errorf("creating CURL request failed: %s", err.Error()), lib.WithNrOfExecutedRequests(executedRequests))
+		var response *lib.Response
+		var err error
+		requestAttempts := 1
+		if th.shouldUseRequestExecutor(req) {
+			request := lib.ConvertToRequest(req)
+			hasRetryPolicy = hasRetryPolicy || request.Retry != nil
+			response, requestAttempts, err = th.performRequest(request, eventAdapter)
+		} else {
+			response, err = th.performCurlRequest(req, eventAdapter)
+		}
+		attempts = append(attempts, requestAttempts)
+		if hasRetryPolicy {
+			results.attempts = attempts
 		}
-		// parse the data from the event, together with the secret env vars
-		parsedCurlCommand, err := th.templateEngine.ParseTemplate(eventAdapter.Get(), request)
 		if err != nil {
-			return nil, lib.NewWebhookExecutionError(true, fmt.Errorf("could not parse request '%s' : %s", request, err.Error()), lib.WithNrOfExecutedRequests(executedRequests))
+			return requestResults{attempts: results.attempts}, lib.NewWebhookExecutionError(true, err, lib.WithNrOfExecutedRequests(executedRequests), lib.WithAttempts(results.attempts))
 		}
-		// perform the request
-		response, err := th.curlExecutor.Curl(parsedCurlCommand)
+		results.responses = append(results.responses, response.Body)
+
+		requestOutputs, err := getResponseSpec(req).Evaluate(*response)
 		if err != nil {
-			return nil, lib.NewWebhookExecutionError(true, fmt.Errorf("could not execute request '%s': %s", request, err.Error()), lib.WithNrOfExecutedRequests(executedRequests))
+			return results, lib.NewWebhookExecutionError(true, fmt.Errorf("unexpected response of request %d: %w", executedRequests+1, err), lib.WithNrOfExecutedRequests(executedRequests), lib.WithAttempts(results.attempts))
+		}
+		for name, value := range requestOutputs {
+			results.outputs[name] = value
+		}
+		// make the response available to the templates of the subsequent requests
+		if name := getRequestName(req); name != "" {
+			namedResponses[name] = lib.NewResponseTemplateData(*response, requestOutputs)
+			eventAdapter.Add("responses", namedResponses)
 		}
 		executedRequests = executedRequests + 1
-		responses = append(responses, response)
 	}
-	return responses, nil
+	return results, nil
+}
+
+func getResponseSpec(request interface{}) *lib.ResponseSpec {
+	if _, ok := request.(string); ok {
+		return nil
+	}
+	return lib.ConvertToRequest(request).Response
+}
+
+func getRequestName(request interface{}) string {
+	if _, ok := request.(string); ok {
+		return ""
+	}
+	return lib.ConvertToRequest(request).Name
+}
+
+func (th *TaskHandler) shouldUseRequestExecutor(request interface{}) bool {
+	if th.requestExecutor == nil {
+		return false
+	}
+	if _, ok := request.(string); ok {
+		return false
+	}
+	return !lib.ConvertToRequest(request).IsCurlRequest()
+}
+
+func (th *TaskHandler) performCurlRequest(req interface{}, eventAdapter *lib.EventDataAdapter) (*lib.Response, error) {
+	if err := th.validateCurlEgress(eventAdapter.Project()); err != nil {
+		logger.Infof("validating request failed: %s", err.Error())
+		return nil, fmt.Errorf("validating request failed: %s", err.Error())
+	}
+	request, err := th.CreateRequest(req)
+	if err != nil {
+		logger.Infof("creating CURL request failed: %s", err.Error())
+		return nil, fmt.Errorf("creating CURL request failed: %s", err.Error())
+	}
+	// parse the data from the event, together with the secret env vars
+	parsedCurlCommand, err := th.templateEngine.ParseTemplate(eventAdapter.Get(), request)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse request '%s' : %s", request, err.Error())
+	}
+	// perform the request
+	response, err := th.curlExecutor.Curl(parsedCurlCommand)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute request '%s': %s", request, err.Error())
+	}
+	return &lib.Response{Body: response}, nil
+}
+
+// performRequest executes the request according to its retry policy, and returns the last response together with the number of attempts
+func (th *TaskHandler) performRequest(request lib.Request, eventAdapter *lib.EventDataAdapter) (*lib.Response, int, error) {
+	requestName := fmt.Sprintf("%s %s", request.Method, request.URL)
+	// in contrast to curl commands, the placeholders are replaced before the request is validated
+	parsedRequest, err := th.parseRequest(request, eventAdapter.Get())
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not parse request '%s' : %s", requestName, err.Error())
+	}
+	parsedRequest = parsedRequest.WithProject(eventAdapter.Project())
+	if err := th.requestValidator.Validate(parsedRequest); err != nil {
+		logger.Infof("validating request failed: %s", err.Error())
+		return nil, 0, fmt.Errorf("validating request failed: %s", err.Error())
+	}
+	parsedRequest, err = th.secureRequest(parsedRequest, eventAdapter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not secure request '%s': %s", requestName, err.Error())
+	}
+	response, attempts, err := lib.ExecuteWithRetry(th.requestExecutor, parsedRequest)
+	if err != nil {
+		if attempts > 1 {
+			return nil, attempts, fmt.Errorf("could not execute request '%s' after %d attempts: %s", requestName, attempts, err.Error())
+		}
+		return nil, attempts, fmt.Errorf("could not execute request '%s': %s", requestName, err.Error())
+	}
+	if attempts > 1 {
+		logger.Infof("request '%s' completed after %d attempts", requestName, attempts)
+	}
+	return response, attempts, nil
+}
+
+// secureRequest loads the client certificate for mutual TLS and signs the payload of the request, if configured
+func (th *TaskHandler) secureRequest(request lib.Request, eventAdapter *lib.EventDataAdapter) (lib.Request, error) {
+	if request.TLS != nil && request.TLS.ClientCert != nil {
+		clientCert := request.TLS.ClientCert
+		certPEM, err := th.secretReader.ReadSecret(clientCert.SecretName, clientCert.GetCertKey())
+		if err != nil {
+			return request, fmt.Errorf("could not read client certificate: %w", err)
+		}
+		keyPEM, err := th.secretReader.ReadSecret(clientCert.SecretName, clientCert.GetKeyKey())
+		if err != nil {
+			return request, fmt.Errorf("could not read client certificate key: %w", err)
+		}
+		request.TLS = request.TLS.WithClientCertificate(certPEM, keyPEM)
+	}
+	if request.Signature != nil {
+		secretEnvVars, _ := eventAdapter.Get()["env"].(map[string]string)
+		secret, ok := secretEnvVars[request.Signature.SecretEnv]
+		if !ok {
+			return request, fmt.Errorf("signature secret '%s' is not available", request.Signature.SecretEnv)
+		}
+		request = lib.SignRequest(request, secret, time.Now())
+	}
+	return request, nil
+}
+
+func (th *TaskHandler) parseRequest(request lib.Request, data interface{}) (lib.Request, error) {
+	var err error
+	parse := func(templateStr string) string {
+		if err != nil || templateStr == "" {
+			return templateStr
+		}
+		var parsed string
+		parsed, err = th.templateEngine.ParseTemplate(data, templateStr)
+		return parsed
+	}
+
+	parsedRequest := request
+	parsedRequest.URL = parse(request.URL)
+	parsedRequest.Payload = parse(request.Payload)
+	parsedRequest.Proxy = parse(request.Proxy)
+	parsedRequest.Headers = make([]lib.Header, len(request.Headers))
+	for i, header := range request.Headers {
+		parsedRequest.Headers[i] = lib.Header{Key: parse(header.Key), Value: parse(header.Value)}
+	}
+	return parsedRequest, err
 }
 
 func (th *TaskHandler) gatherSecretEnvVars(webhook lib.Webhook) (map[string]string, error) {
@@ -256,6 +510,22 @@ func (th *TaskHandler) CreateRequest(request interface{}) (string, error) {
 	return "", fmt.Errorf("could not create request: invalid request type")
 }
 
+func (th *TaskHandler) validateCurlEgress(project string) error {
+	if th.egressRules == nil {
+		return nil
+	}
+	rules, err := th.egressRules.Get()
+	if err != nil {
+		return lib.NewCurlError(fmt.Errorf("could not load egress rules: %w", err), lib.DeniedURLError)
+	}
+	for _, rule := range rules {
+		if rule.AppliesTo(project) {
+			return lib.NewCurlError(fmt.Errorf("requests executed via curl are not allowed, since egress rule '%s' applies to project '%s'", rule.Name, project), lib.DeniedURLError)
+		}
+	}
+	return nil
+}
+
 func (th *TaskHandler) validateAlphaCurlRequest(curlCmd string) error {
 	sanitizedCurlCmd := strings.ReplaceAll(curlCmd, "\\", "")
 	denyList := lib.GetDeniedAlphaURLs(lib.GetEnv())
@@ -1167,3 +1167,520 @@ func Test_createRequest(t *testing.T) {
 		})
 	}
 }
+
+const webHookContentBetaWithRequestExecutor = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      envFrom:
+        - name: mysecret
+          secretRef:
+            name: mysecret
+            key: key
+      requests:
+        - url: http://local:8080/{{.data.project}}
+          method: POST
+          timeout: 10s
+          headers:
+            - key: x-token
+              value: "{{.env.mysecret}}"
+          payload: '{"stage": "{{.data.stage}}"}'
+        - url: http://local:8080
+          method: GET
+          options: "--insecure"`
+
+func TestTaskHandler_Execute_BetaRequestsWithRequestExecutor(t *testing.T) {
+	templateEngineMock := &fake.ITemplateEngineMock{ParseTemplateFunc: func(data interface{}, templateStr string) (string, error) {
+		tplE := &lib.TemplateEngine{}
+		return tplE.ParseTemplate(data, templateStr)
+	}}
+
+	secretReaderMock := &fake.ISecretReaderMock{}
+	secretReaderMock.ReadSecretFunc = func(name string, key string) (string, error) {
+		return "my-secret-value", nil
+	}
+
+	curlExecutorMock := &fake.ICurlExecutorMock{}
+	curlExecutorMock.CurlFunc = func(curlCmd string) (string, error) {
+		return "curl success", nil
+	}
+
+	requestExecutorMock := &fake.IRequestExecutorMock{}
+	requestExecutorMock.ExecuteFunc = func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 200, Body: "success"}, nil
+	}
+
+	validatedRequests := []lib.Request{}
+	requestValidatorMock := &fake.RequestValidatorMock{}
+	requestValidatorMock.ValidateFunc = func(request lib.Request) error {
+		validatedRequests = append(validatedRequests, request)
+		return nil
+	}
+
+	taskHandler := handler.NewTaskHandler(templateEngineMock, curlExecutorMock, requestValidatorMock, secretReaderMock, handler.WithRequestExecutor(requestExecutorMock))
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithRequestExecutor})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	// the placeholders are replaced before the request is validated and executed
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	executedRequest := requestExecutorMock.ExecuteCalls()[0].Request
+	assert.Equal(t, "http://local:8080/myproject", executedRequest.URL)
+	assert.Equal(t, "POST", executedRequest.Method)
+	assert.Equal(t, "10s", executedRequest.Timeout)
+	assert.Equal(t, []lib.Header{{Key: "x-token", Value: "my-secret-value"}}, executedRequest.Headers)
+	assert.Equal(t, `{"stage": "mystage"}`, executedRequest.Payload)
+	require.NotEmpty(t, validatedRequests)
+	assert.Equal(t, executedRequest, validatedRequests[0])
+
+	// requests with curl options are still executed via curl
+	require.Len(t, curlExecutorMock.CurlCalls(), 1)
+	assert.Equal(t, "curl --request GET --insecure http://local:8080", curlExecutorMock.CurlCalls()[0].CurlCmd)
+
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := map[string]interface{}{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, &eventData))
+	assert.Equal(t, map[string]interface{}{"responses": []interface{}{"success", "curl success"}}, eventData["webhook"])
+}
+
+func TestTaskHandler_Execute_BetaRequestExecutorFails(t *testing.T) {
+	templateEngineMock := &fake.ITemplateEngineMock{ParseTemplateFunc: func(data interface{}, templateStr string) (string, error) {
+		tplE := &lib.TemplateEngine{}
+		return tplE.ParseTemplate(data, templateStr)
+	}}
+
+	secretReaderMock := &fake.ISecretReaderMock{}
+	secretReaderMock.ReadSecretFunc = func(name string, key string) (string, error) {
+		return "my-secret-value", nil
+	}
+
+	curlExecutorMock := &fake.ICurlExecutorMock{}
+
+	requestExecutorMock := &fake.IRequestExecutorMock{}
+	requestExecutorMock.ExecuteFunc = func(request lib.Request) (*lib.Response, error) {
+		return nil, lib.NewCurlError(errors.New("request failed for my-secret-value"), lib.RequestError)
+	}
+
+	requestValidatorMock := &fake.RequestValidatorMock{}
+	requestValidatorMock.ValidateFunc = func(request lib.Request) error {
+		return nil
+	}
+
+	taskHandler := handler.NewTaskHandler(templateEngineMock, curlExecutorMock, requestValidatorMock, secretReaderMock, handler.WithRequestExecutor(requestExecutorMock))
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithRequestExecutor})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	require.Empty(t, curlExecutorMock.CurlCalls())
+
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := &keptnv2.EventData{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, eventData))
+	assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+	assert.Equal(t, keptnv2.ResultFailed, eventData.Result)
+	assert.NotContains(t, eventData.Message, "my-secret-value")
+}
+
+const webHookContentBetaWithAssertions = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://local:8080/health
+          method: GET
+          response:
+            statusCodes: [200]
+            assertions:
+              - jsonPath: $.status
+                equals: healthy
+            outputs:
+              - name: score
+                jsonPath: $.score
+        - url: http://local:8080/version
+          method: GET
+          response:
+            outputs:
+              - name: version
+                regex: "v(\\d+\\.\\d+)"`
+
+func newAssertionsTaskHandler(responses map[string]*lib.Response) (*handler.TaskHandler, *fake.IRequestExecutorMock) {
+	templateEngineMock := &fake.ITemplateEngineMock{ParseTemplateFunc: func(data interface{}, templateStr string) (string, error) {
+		tplE := &lib.TemplateEngine{}
+		return tplE.ParseTemplate(data, templateStr)
+	}}
+	requestExecutorMock := &fake.IRequestExecutorMock{}
+	requestExecutorMock.ExecuteFunc = func(request lib.Request) (*lib.Response, error) {
+		return responses[request.URL], nil
+	}
+	requestValidatorMock := &fake.RequestValidatorMock{}
+	requestValidatorMock.ValidateFunc = func(request lib.Request) error {
+		return nil
+	}
+	return handler.NewTaskHandler(templateEngineMock, &fake.ICurlExecutorMock{}, requestValidatorMock, &fake.ISecretReaderMock{}, handler.WithRequestExecutor(requestExecutorMock)), requestExecutorMock
+}
+
+func TestTaskHandler_Execute_ResponseAssertionsAndOutputs(t *testing.T) {
+	taskHandler, requestExecutorMock := newAssertionsTaskHandler(map[string]*lib.Response{
+		"http://local:8080/health":  {StatusCode: 200, Body: `{"status": "healthy", "score": 95}`},
+		"http://local:8080/version": {StatusCode: 200, Body: "running v1.2"},
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithAssertions})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 2)
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := map[string]interface{}{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, &eventData))
+	assert.Equal(t, string(keptnv2.ResultPass), eventData["result"])
+	assert.Equal(t, map[string]interface{}{
+		"responses": []interface{}{`{"status": "healthy", "score": 95}`, "running v1.2"},
+		"score":     float64(95),
+		"version":   "1.2",
+	}, eventData["webhook"])
+}
+
+func TestTaskHandler_Execute_ResponseAssertionFails(t *testing.T) {
+	taskHandler, requestExecutorMock := newAssertionsTaskHandler(map[string]*lib.Response{
+		"http://local:8080/health":  {StatusCode: 200, Body: `{"status": "degraded", "score": 40}`},
+		"http://local:8080/version": {StatusCode: 200, Body: "running v1.2"},
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithAssertions})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	// the remaining requests are not executed after a failed assertion
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := map[string]interface{}{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, &eventData))
+	assert.Equal(t, string(keptnv2.ResultFailed), eventData["result"])
+	assert.Equal(t, string(keptnv2.StatusSucceeded), eventData["status"])
+	assert.Contains(t, eventData["message"], "assertion on '$.status' failed: expected 'healthy' but got 'degraded'")
+	assert.Equal(t, map[string]interface{}{
+		"responses": []interface{}{`{"status": "degraded", "score": 40}`},
+	}, eventData["webhook"])
+}
+
+func TestTaskHandler_Execute_UnexpectedStatusCode(t *testing.T) {
+	taskHandler, _ := newAssertionsTaskHandler(map[string]*lib.Response{
+		"http://local:8080/health": {StatusCode: 503, Body: "unavailable"},
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithAssertions})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := &keptnv2.EventData{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, eventData))
+	assert.Equal(t, keptnv2.ResultFailed, eventData.Result)
+	assert.Equal(t, keptnv2.StatusSucceeded, eventData.Status)
+	assert.Contains(t, eventData.Message, "unexpected status code 503")
+}
+
+const webHookContentBetaWithChainedRequests = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - name: createTicket
+          url: http://local:8080/tickets
+          method: POST
+          payload: '{"title": "deployment of {{.data.service}}"}'
+          response:
+            outputs:
+              - name: ticketURL
+                jsonPath: $.links.self
+        - url: http://local:8080/tickets/{{.responses.createTicket.id}}/comments
+          method: POST
+          payload: '{"text": "see {{.responses.createTicket.ticketURL}}"}'`
+
+func TestTaskHandler_Execute_ChainedRequests(t *testing.T) {
+	taskHandler, requestExecutorMock := newAssertionsTaskHandler(map[string]*lib.Response{
+		"http://local:8080/tickets":                    {StatusCode: 201, Body: `{"id": "TICKET-42", "links": {"self": "http://tickets/42"}}`},
+		"http://local:8080/tickets/TICKET-42/comments": {StatusCode: 201, Body: `{}`},
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithChainedRequests})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 2)
+	secondRequest := requestExecutorMock.ExecuteCalls()[1].Request
+	assert.Equal(t, "http://local:8080/tickets/TICKET-42/comments", secondRequest.URL)
+	assert.Equal(t, `{"text": "see http://tickets/42"}`, secondRequest.Payload)
+
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := &keptnv2.EventData{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, eventData))
+	assert.Equal(t, keptnv2.ResultPass, eventData.Result)
+}
+
+func TestTaskHandler_Execute_ChainedRequestReferencesUnknownResponse(t *testing.T) {
+	taskHandler, requestExecutorMock := newAssertionsTaskHandler(map[string]*lib.Response{
+		"http://local:8080/tickets": {StatusCode: 201, Body: `{"links": {"self": "http://tickets/42"}}`},
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithChainedRequests})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	// the response of the first request does not contain an id, so the second request cannot be parsed
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	finishedEvent, err := keptnv2.ToKeptnEvent(fakeKeptn.GetEventSender().SentEvents[1])
+	require.Nil(t, err)
+	eventData := &keptnv2.EventData{}
+	require.Nil(t, keptnv2.EventDataAs(finishedEvent, eventData))
+	assert.Equal(t, keptnv2.ResultFailed, eventData.Result)
+	assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+}
+
+const webHookContentBetaWithRetry = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://local:8080/flaky
+          method: POST
+          retry:
+            attempts: 3
+            initialBackoff: 1ms
+        - url: http://local:8080/stable
+          method: GET`
+
+func newRequestExecutorTaskHandler(executeFunc func(request lib.Request) (*lib.Response, error), opts ...handler.TaskHandlerOption) (*handler.TaskHandler, *fake.IRequestExecutorMock) {
+	templateEngineMock := &fake.ITemplateEngineMock{ParseTemplateFunc: func(data interface{}, templateStr string) (string, error) {
+		tplE := &lib.TemplateEngine{}
+		return tplE.ParseTemplate(data, templateStr)
+	}}
+	requestExecutorMock := &fake.IRequestExecutorMock{ExecuteFunc: executeFunc}
+	requestValidatorMock := &fake.RequestValidatorMock{}
+	requestValidatorMock.ValidateFunc = func(request lib.Request) error {
+		return nil
+	}
+	opts = append([]handler.TaskHandlerOption{handler.WithRequestExecutor(requestExecutorMock)}, opts...)
+	return handler.NewTaskHandler(templateEngineMock, &fake.ICurlExecutorMock{}, requestValidatorMock, &fake.ISecretReaderMock{}, opts...), requestExecutorMock
+}
+
+func TestTaskHandler_Execute_RetryRequest(t *testing.T) {
+	flakyCalls := 0
+	taskHandler, requestExecutorMock := newRequestExecutorTaskHandler(func(request lib.Request) (*lib.Response, error) {
+		if request.URL == "http://local:8080/flaky" {
+			flakyCalls++
+			if flakyCalls < 3 {
+				return &lib.Response{StatusCode: 503, Body: "unavailable"}, nil
+			}
+		}
+		return &lib.Response{StatusCode: 200, Body: "success"}, nil
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithRetry})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 4)
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	eventData := getSentEventData(t, fakeKeptn, 1)
+	assert.Equal(t, string(keptnv2.ResultPass), eventData["result"])
+	assert.Equal(t, map[string]interface{}{
+		"responses": []interface{}{"success", "success"},
+		"attempts":  []interface{}{float64(3), float64(1)},
+	}, eventData["webhook"])
+}
+
+func TestTaskHandler_Execute_RetryAttemptsExhausted(t *testing.T) {
+	taskHandler, requestExecutorMock := newRequestExecutorTaskHandler(func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 503, Body: "unavailable"}, nil
+	})
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithRetry})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	// the second request is not executed after the first one failed
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 3)
+	require.Equal(t, 2, len(fakeKeptn.GetEventSender().SentEvents))
+	eventData := getSentEventData(t, fakeKeptn, 1)
+	assert.Equal(t, string(keptnv2.ResultFailed), eventData["result"])
+	assert.Equal(t, string(keptnv2.StatusErrored), eventData["status"])
+	assert.Contains(t, eventData["message"], "status code 503")
+	assert.Equal(t, map[string]interface{}{"attempts": []interface{}{float64(3)}}, eventData["webhook"])
+}
+
+const webHookContentBetaWithSignature = `apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      envFrom:
+        - name: "signingKey"
+          secretRef:
+            name: "my-signing-secret"
+            key: "key"
+      requests:
+        - url: http://local:8080/signed
+          method: POST
+          payload: '{"project": "{{.data.project}}"}'
+          signature:
+            secretEnv: signingKey
+            header: X-Signature
+          tls:
+            clientCert:
+              secretName: my-client-cert`
+
+func TestTaskHandler_Execute_SignedRequest(t *testing.T) {
+	templateEngine := &fake.ITemplateEngineMock{ParseTemplateFunc: (&lib.TemplateEngine{}).ParseTemplate}
+	requestExecutorMock := &fake.IRequestExecutorMock{ExecuteFunc: func(request lib.Request) (*lib.Response, error) {
+		return &lib.Response{StatusCode: 200, Body: "success"}, nil
+	}}
+	requestValidatorMock := &fake.RequestValidatorMock{ValidateFunc: func(request lib.Request) error {
+		return nil
+	}}
+	secretReaderMock := &fake.ISecretReaderMock{ReadSecretFunc: func(name, key string) (string, error) {
+		return name + "/" + key, nil
+	}}
+	taskHandler := handler.NewTaskHandler(templateEngine, &fake.ICurlExecutorMock{}, requestValidatorMock, secretReaderMock, handler.WithRequestExecutor(requestExecutorMock))
+
+	fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+	fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContentBetaWithSignature})
+	fakeKeptn.AddTaskHandler("*", taskHandler)
+	fakeKeptn.SetAutomaticResponse(false)
+	fakeKeptn.Start()
+	fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+	require.Len(t, requestExecutorMock.ExecuteCalls(), 1)
+	request := requestExecutorMock.ExecuteCalls()[0].Request
+	require.Len(t, request.Headers, 2)
+	assert.Equal(t, lib.DefaultSignatureTimestampHeader, request.Headers[0].Key)
+	assert.Equal(t, "X-Signature", request.Headers[1].Key)
+	expectedSignature := lib.ComputeSignature("my-signing-secret/key", request.Headers[0].Value, `{"project": "myproject"}`)
+	assert.Equal(t, "sha256="+expectedSignature, request.Headers[1].Value)
+
+	// the client certificate is read from the referenced secret, and not exposed in the webhook config
+	assert.Equal(t, "my-client-cert", request.TLS.ClientCert.SecretName)
+	assert.Contains(t, secretReaderMock.ReadSecretCalls(), struct{ Name, Key string }{Name: "my-client-cert", Key: "tls.crt"})
+	assert.Contains(t, secretReaderMock.ReadSecretCalls(), struct{ Name, Key string }{Name: "my-client-cert", Key: "tls.key"})
+
+	eventData := getSentEventData(t, fakeKeptn, 1)
+	assert.Equal(t, string(keptnv2.ResultPass), eventData["result"])
+}
+
+func TestTaskHandler_Execute_CurlRequestWithEgressRules(t *testing.T) {
+	templateEngineMock := &fake.ITemplateEngineMock{ParseTemplateFunc: (&lib.TemplateEngine{}).ParseTemplate}
+	secretReaderMock := &fake.ISecretReaderMock{ReadSecretFunc: func(name string, key string) (string, error) {
+		return "my-secret-value", nil
+	}}
+	tests := []struct {
+		name          string
+		rules         string
+		wantCurlCalls int
+		wantResult    keptnv2.ResultType
+	}{
+		{
+			name:          "no rules for the project",
+			rules:         "- name: deny-other\n  action: deny\n  hosts: [example.com]\n  projects: [other-project]",
+			wantCurlCalls: 1,
+			wantResult:    keptnv2.ResultPass,
+		},
+		{
+			name:       "rule applies to the project",
+			rules:      "- name: allow-internal\n  action: allow\n  hosts: [\"*.internal.example.com\"]\n  projects: [myproject]",
+			wantResult: keptnv2.ResultFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			curlExecutorMock := &fake.ICurlExecutorMock{CurlFunc: func(curlCmd string) (string, error) {
+				return "success", nil
+			}}
+			rules, err := lib.ParseEgressRules(tt.rules)
+			require.Nil(t, err)
+			egressRuleProvider := &fake.EgressRuleProviderMock{GetFunc: func() ([]lib.EgressRule, error) { return rules, nil }}
+			taskHandler := handler.NewTaskHandler(templateEngineMock, curlExecutorMock, &fake.RequestValidatorMock{}, secretReaderMock, handler.WithEgressRules(egressRuleProvider))
+
+			fakeKeptn := sdk.NewFakeKeptn("test-webhook-svc")
+			fakeKeptn.SetResourceHandler(sdk.StringResourceHandler{ResourceContent: webHookContent})
+			fakeKeptn.AddTaskHandler("*", taskHandler)
+			fakeKeptn.SetAutomaticResponse(false)
+			fakeKeptn.Start()
+			fakeKeptn.NewEvent(newWebhookTriggeredEvent("test/events/test-webhook.triggered-0.json"))
+
+			require.Len(t, curlExecutorMock.CurlCalls(), tt.wantCurlCalls)
+			eventData := getSentEventData(t, fakeKeptn, 1)
+			assert.Equal(t, string(tt.wantResult), eventData["result"])
+			if tt.wantResult == keptnv2.ResultFailed {
+				assert.Contains(t, eventData["message"], "egress rule 'allow-internal' applies to project 'myproject'")
+			}
+		})
+	}
+}
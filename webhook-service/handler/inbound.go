@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/go-sdk/pkg/sdk"
+	"github.com/keptn/keptn/webhook-service/lib"
+	logger "github.com/sirupsen/logrus"
+)
+
+// inboundPath is the path of the endpoint that receives the requests of inbound webhooks, followed by the name of the webhook
+const inboundPath = "/v1/inbound/"
+
+// InboundReceiver maps the requests of external systems to Keptn events, which are forwarded via the EventSender
+type InboundReceiver struct {
+	webhookProvider lib.InboundWebhookProvider
+	secretReader    lib.ISecretReader
+	templateEngine  lib.ITemplateEngine
+	eventSender     sdk.EventSender
+	source          string
+}
+
+// NewInboundReceiver creates an InboundReceiver sending the mapped events with the given source
+func NewInboundReceiver(webhookProvider lib.InboundWebhookProvider, secretReader lib.ISecretReader, templateEngine lib.ITemplateEngine, eventSender sdk.EventSender, source string) *InboundReceiver {
+	return &InboundReceiver{
+		webhookProvider: webhookProvider,
+		secretReader:    secretReader,
+		templateEngine:  templateEngine,
+		eventSender:     eventSender,
+		source:          source,
+	}
+}
+
+type inboundResponse struct {
+	KeptnContext string `json:"keptnContext,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// ServeHTTP authenticates the request of an inbound webhook, and sends the Keptn event the request is mapped to
+func (r *InboundReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeInboundResponse(w, http.StatusMethodNotAllowed, inboundResponse{Message: "method not allowed"})
+		return
+	}
+	name := strings.TrimPrefix(req.URL.Path, inboundPath)
+	if !strings.HasPrefix(req.URL.Path, inboundPath) || name == "" {
+		writeInboundResponse(w, http.StatusNotFound, inboundResponse{Message: "inbound webhook not found"})
+		return
+	}
+
+	webhook, err := r.webhookProvider.Get(name)
+	if errors.Is(err, lib.ErrInboundWebhookNotFound) {
+		writeInboundResponse(w, http.StatusNotFound, inboundResponse{Message: "inbound webhook not found"})
+		return
+	} else if err != nil {
+		logger.WithError(err).Errorf("could not load inbound webhook %s", name)
+		writeInboundResponse(w, http.StatusInternalServerError, inboundResponse{Message: "could not load inbound webhook"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, lib.DefaultMaxResponseSize+1))
+	if err != nil || len(body) > lib.DefaultMaxResponseSize {
+		writeInboundResponse(w, http.StatusBadRequest, inboundResponse{Message: "could not read request body"})
+		return
+	}
+
+	secret, err := r.secretReader.ReadSecret(webhook.Auth.SecretRef.Name, webhook.Auth.SecretRef.Key)
+	if err != nil || secret == "" {
+		logger.Errorf("could not read secret %s.%s of inbound webhook %s", webhook.Auth.SecretRef.Name, webhook.Auth.SecretRef.Key, name)
+		writeInboundResponse(w, http.StatusInternalServerError, inboundResponse{Message: "could not authenticate request"})
+		return
+	}
+	if err := webhook.Auth.Verify(secret, req.Header, body, time.Now()); err != nil {
+		logger.Infof("rejected request of inbound webhook %s: %s", name, err.Error())
+		writeInboundResponse(w, http.StatusUnauthorized, inboundResponse{Message: "unauthorized"})
+		return
+	}
+
+	event, err := r.mapEvent(webhook.Event, req, body)
+	if err != nil {
+		writeInboundResponse(w, http.StatusBadRequest, inboundResponse{Message: fmt.Sprintf("could not map request to Keptn event: %s", err.Error())})
+		return
+	}
+	if err := r.eventSender.SendEvent(*event); err != nil {
+		logger.WithError(err).Errorf("could not send event of inbound webhook %s", name)
+		writeInboundResponse(w, http.StatusBadGateway, inboundResponse{Message: "could not send Keptn event"})
+		return
+	}
+	keptnContext, _ := event.Extensions()["shkeptncontext"].(string)
+	logger.Infof("sent %s event for inbound webhook %s with context %s", event.Type(), name, keptnContext)
+	writeInboundResponse(w, http.StatusAccepted, inboundResponse{KeptnContext: keptnContext})
+}
+
+func (r *InboundReceiver) mapEvent(mapping lib.InboundEventMapping, req *http.Request, body []byte) (*cloudevents.Event, error) {
+	templateData := map[string]interface{}{
+		"body":    parseInboundBody(body),
+		"headers": flattenValues(req.Header),
+		"query":   flattenValues(req.URL.Query()),
+	}
+	var err error
+	parse := func(templateStr string) string {
+		if err != nil || templateStr == "" {
+			return templateStr
+		}
+		var parsed string
+		parsed, err = r.templateEngine.ParseTemplate(templateData, templateStr)
+		return strings.TrimSpace(parsed)
+	}
+	eventType := parse(mapping.Type)
+	project := parse(mapping.Project)
+	stage := parse(mapping.Stage)
+	service := parse(mapping.Service)
+	data := parse(mapping.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !keptnv2.IsValidEventType(eventType) {
+		return nil, fmt.Errorf("invalid event type '%s'", eventType)
+	}
+	if project == "" {
+		return nil, errors.New("project must not be empty")
+	}
+
+	eventData := map[string]interface{}{}
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), &eventData); err != nil {
+			return nil, fmt.Errorf("event data is not a valid JSON object: %w", err)
+		}
+	}
+	eventData["project"] = project
+	setIfNotEmpty(eventData, "stage", stage)
+	setIfNotEmpty(eventData, "service", service)
+
+	// the event is not built via Build(), since it requires the stage and service, which are not needed for all event types
+	keptnEvent := keptnv2.KeptnEvent(eventType, r.source, eventData).WithKeptnContext(uuid.New().String()).KeptnContextExtendedCE
+	event := keptnv2.ToCloudEvent(keptnEvent)
+	return &event, nil
+}
+
+// parseInboundBody returns the JSON body of a request as object, or the raw body if it is not valid JSON
+func parseInboundBody(body []byte) interface{} {
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return string(body)
+	}
+	return parsed
+}
+
+func flattenValues(values map[string][]string) map[string]string {
+	flattened := map[string]string{}
+	for key, v := range values {
+		if len(v) > 0 {
+			flattened[key] = v[0]
+		}
+	}
+	return flattened
+}
+
+func setIfNotEmpty(m map[string]interface{}, key string, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
+
+func writeInboundResponse(w http.ResponseWriter, statusCode int, response inboundResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(response)
+}
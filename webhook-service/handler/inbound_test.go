@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/keptn/keptn/go-sdk/pkg/sdk"
+	"github.com/keptn/keptn/webhook-service/handler"
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/keptn/keptn/webhook-service/lib/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var registryPushWebhook = lib.InboundWebhook{
+	Name: "registry-push",
+	Auth: lib.InboundAuth{Type: lib.InboundAuthToken, SecretRef: lib.WebHookSecretRef{Name: "registry-token", Key: "token"}},
+	Event: lib.InboundEventMapping{
+		Type:    "sh.keptn.event.dev.delivery.triggered",
+		Project: "{{.body.project}}",
+		Stage:   "dev",
+		Service: "{{.body.repository}}",
+		Data:    `{"configurationChange": {"values": {"image": {{printf "%s:%s" .body.repository .body.tag | quote}}}}, "labels": {"trigger": {{index .headers "X-Registry-Event" | quote}}}}`,
+	},
+}
+
+func newInboundReceiver(eventSender sdk.EventSender) *handler.InboundReceiver {
+	webhookProvider := &fake.InboundWebhookProviderMock{GetFunc: func(name string) (*lib.InboundWebhook, error) {
+		if name == registryPushWebhook.Name {
+			return &registryPushWebhook, nil
+		}
+		return nil, lib.ErrInboundWebhookNotFound
+	}}
+	secretReader := &fake.ISecretReaderMock{ReadSecretFunc: func(name, key string) (string, error) {
+		return "my-token", nil
+	}}
+	return handler.NewInboundReceiver(webhookProvider, secretReader, &lib.TemplateEngine{}, eventSender, "webhook-service")
+}
+
+func sendInboundRequest(receiver http.Handler, method string, path string, token string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set(lib.DefaultInboundTokenHeader, token)
+	}
+	req.Header.Set("X-Registry-Event", "push")
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestInboundReceiver_ServeHTTP(t *testing.T) {
+	eventSender := &sdk.TestSender{}
+	receiver := newInboundReceiver(eventSender)
+
+	recorder := sendInboundRequest(receiver, http.MethodPost, "/v1/inbound/registry-push", "my-token", `{"project": "my-project", "repository": "my-service", "tag": "1.2.3"}`)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Len(t, eventSender.SentEvents, 1)
+	event := eventSender.SentEvents[0]
+	assert.Equal(t, "sh.keptn.event.dev.delivery.triggered", event.Type())
+	assert.Equal(t, "webhook-service", event.Source())
+
+	response := map[string]string{}
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.NotEmpty(t, response["keptnContext"])
+	assert.Equal(t, response["keptnContext"], event.Extensions()["shkeptncontext"])
+
+	eventData := map[string]interface{}{}
+	require.Nil(t, event.DataAs(&eventData))
+	assert.Equal(t, map[string]interface{}{
+		"project":             "my-project",
+		"stage":               "dev",
+		"service":             "my-service",
+		"configurationChange": map[string]interface{}{"values": map[string]interface{}{"image": "my-service:1.2.3"}},
+		"labels":              map[string]interface{}{"trigger": "push"},
+	}, eventData)
+}
+
+func TestInboundReceiver_ServeHTTPRejectedRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{name: "wrong method", method: http.MethodGet, path: "/v1/inbound/registry-push", token: "my-token", wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown webhook", method: http.MethodPost, path: "/v1/inbound/unknown", token: "my-token", wantStatus: http.StatusNotFound},
+		{name: "missing token", method: http.MethodPost, path: "/v1/inbound/registry-push", body: `{"project": "my-project"}`, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodPost, path: "/v1/inbound/registry-push", token: "other", body: `{"project": "my-project"}`, wantStatus: http.StatusUnauthorized},
+		{name: "missing project", method: http.MethodPost, path: "/v1/inbound/registry-push", token: "my-token", body: `{"repository": "my-service"}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventSender := &sdk.TestSender{}
+			recorder := sendInboundRequest(newInboundReceiver(eventSender), tt.method, tt.path, tt.token, tt.body)
+
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			require.Empty(t, eventSender.SentEvents)
+		})
+	}
+}
+
+func TestInboundReceiver_ServeHTTPEventSenderFails(t *testing.T) {
+	eventSender := &sdk.TestSender{Reactors: map[string]func(event cloudevents.Event) error{
+		"*": func(event cloudevents.Event) error {
+			return errors.New("unavailable")
+		},
+	}}
+
+	recorder := sendInboundRequest(newInboundReceiver(eventSender), http.MethodPost, "/v1/inbound/registry-push", "my-token", `{"project": "my-project", "repository": "my-service", "tag": "1.2.3"}`)
+
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+}
@@ -24,6 +24,10 @@ func (c *CurlError) Error() string {
 	return c.err.Error()
 }
 
+func (c *CurlError) Unwrap() error {
+	return c.err
+}
+
 func NewCurlError(err error, reason errType) *CurlError {
 	return &CurlError{
 		err:    err,
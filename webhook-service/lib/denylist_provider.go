@@ -20,6 +20,8 @@ type denyListProvider struct {
 
 type GetDeniedURLsFunc func(env map[string]string) []string
 
+// NewDenyListProvider returns a DenyListProvider extending the default deny list by the denyList of the keptn-webhook-config ConfigMap.
+// If no kubeClient is provided, e.g. when running outside of Kubernetes, only the default deny list is used
 func NewDenyListProvider(kubeClient kubernetes.Interface) DenyListProvider {
 	return denyListProvider{
 		getDeniedURLs: GetDeniedURLs,
@@ -29,6 +31,9 @@ func NewDenyListProvider(kubeClient kubernetes.Interface) DenyListProvider {
 
 func (d denyListProvider) Get() []string {
 	denyList := d.getDeniedURLs(GetEnv())
+	if d.kubeClient == nil {
+		return denyList
+	}
 
 	configMap, err := d.kubeClient.CoreV1().ConfigMaps(GetNamespaceFromEnvVar()).Get(context.TODO(), WebhookConfigMap, metav1.GetOptions{})
 	if err != nil {
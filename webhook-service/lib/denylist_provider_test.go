@@ -90,3 +90,13 @@ func TestGetDenyList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDenyListWithoutKubernetes(t *testing.T) {
+	denyListProvider := denyListProvider{
+		getDeniedURLs: func(env map[string]string) []string {
+			return []string{"1.2.3.4", "kubernetes:9876"}
+		},
+	}
+
+	require.Equal(t, []string{"1.2.3.4", "kubernetes:9876"}, denyListProvider.Get())
+}
@@ -0,0 +1,282 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"net"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// egress rule actions
+const (
+	EgressActionAllow = "allow"
+	EgressActionDeny  = "deny"
+)
+
+// egressRulesConfigKey is the key of the egress rules within the keptn-webhook-config ConfigMap
+const egressRulesConfigKey = "egressRules"
+
+var egressRuleNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// EgressRule allows or denies requests to the targets matching all of its criteria. Criteria that are not set match any target.
+// Deny rules are evaluated first, and reject a request if any of them matches. If allow rules apply to a project, requests of the project
+// must match at least one of them
+type EgressRule struct {
+	Name   string `yaml:"name"`
+	Action string `yaml:"action"`
+	// CIDRs are matched against the resolved IP addresses of the target. A deny rule matches if any address is within the ranges,
+	// an allow rule only matches if all addresses are
+	CIDRs []string `yaml:"cidrs,omitempty"`
+	// Hosts are matched against the host name of the target URL. A leading '*.' matches any subdomain, e.g. *.internal.example.com
+	Hosts   []string `yaml:"hosts,omitempty"`
+	Ports   []int    `yaml:"ports,omitempty"`
+	Schemes []string `yaml:"schemes,omitempty"`
+	// Projects limits the rule to the webhooks of the given projects. The rule applies to all projects if no project is set
+	Projects []string `yaml:"projects,omitempty"`
+
+	networks []*net.IPNet
+}
+
+// EgressTarget is the destination of a request that is evaluated against the egress rules
+type EgressTarget struct {
+	Project string
+	Scheme  string
+	Host    string
+	Port    int
+	IPs     []net.IP
+}
+
+//go:generate moq  -pkg fake -out ./fake/egress_rule_provider_mock.go . EgressRuleProvider
+type EgressRuleProvider interface {
+	Get() ([]EgressRule, error)
+}
+
+type egressRuleProvider struct {
+	kubeClient kubernetes.Interface
+}
+
+// NewEgressRuleProvider returns an EgressRuleProvider reading the egress rules from the keptn-webhook-config ConfigMap.
+// Invalid rules are reported as error, so requests are rejected rather than executed without the intended restrictions.
+// If no kubeClient is provided, e.g. when running outside of Kubernetes, there are no egress rules
+func NewEgressRuleProvider(kubeClient kubernetes.Interface) EgressRuleProvider {
+	return egressRuleProvider{kubeClient: kubeClient}
+}
+
+func (p egressRuleProvider) Get() ([]EgressRule, error) {
+	if p.kubeClient == nil {
+		return []EgressRule{}, nil
+	}
+	configMap, err := p.kubeClient.CoreV1().ConfigMaps(GetNamespaceFromEnvVar()).Get(context.TODO(), WebhookConfigMap, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return []EgressRule{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to get ConfigMap %s content: %w", WebhookConfigMap, err)
+	}
+	return ParseEgressRules(configMap.Data[egressRulesConfigKey])
+}
+
+// ParseEgressRules decodes and validates a YAML list of egress rules
+func ParseEgressRules(content string) ([]EgressRule, error) {
+	rules := []EgressRule{}
+	if strings.TrimSpace(content) == "" {
+		return rules, nil
+	}
+	if err := yaml.Unmarshal([]byte(content), &rules); err != nil {
+		return nil, fmt.Errorf("could not parse egress rules: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range rules {
+		if err := rules[i].init(); err != nil {
+			return nil, err
+		}
+		if names[rules[i].Name] {
+			return nil, fmt.Errorf("egress rule name '%s' is not unique", rules[i].Name)
+		}
+		names[rules[i].Name] = true
+	}
+	return rules, nil
+}
+
+func (r *EgressRule) init() error {
+	if !egressRuleNameRegex.MatchString(r.Name) {
+		return fmt.Errorf("invalid egress rule name '%s'", r.Name)
+	}
+	if r.Action != EgressActionAllow && r.Action != EgressActionDeny {
+		return fmt.Errorf("egress rule '%s' must have action '%s' or '%s'", r.Name, EgressActionAllow, EgressActionDeny)
+	}
+	if len(r.CIDRs) == 0 && len(r.Hosts) == 0 && len(r.Ports) == 0 && len(r.Schemes) == 0 {
+		return fmt.Errorf("egress rule '%s' must contain at least one of 'cidrs', 'hosts', 'ports' or 'schemes'", r.Name)
+	}
+	r.networks = nil
+	for _, cidr := range r.CIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("egress rule '%s' contains invalid CIDR '%s'", r.Name, cidr)
+		}
+		r.networks = append(r.networks, network)
+	}
+	for _, host := range r.Hosts {
+		if host == "" || strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return fmt.Errorf("egress rule '%s' contains invalid host '%s'", r.Name, host)
+		}
+	}
+	for _, port := range r.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("egress rule '%s' contains invalid port %d", r.Name, port)
+		}
+	}
+	return nil
+}
+
+// AppliesTo returns true if the rule applies to the webhooks of the given project
+func (r EgressRule) AppliesTo(project string) bool {
+	return len(r.Projects) == 0 || containsString(r.Projects, project)
+}
+
+// Matches returns true if the target matches all criteria of the rule
+func (r EgressRule) Matches(target EgressTarget) bool {
+	if len(r.Schemes) > 0 && !containsFold(r.Schemes, target.Scheme) {
+		return false
+	}
+	if len(r.Ports) > 0 && !containsInt(r.Ports, target.Port) {
+		return false
+	}
+	if len(r.Hosts) > 0 && !r.matchesHost(target.Host) {
+		return false
+	}
+	if len(r.networks) > 0 {
+		if r.Action == EgressActionDeny {
+			return r.containsAnyIP(target.IPs)
+		}
+		return r.containsAllIPs(target.IPs)
+	}
+	return true
+}
+
+func (r EgressRule) matchesHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range r.Hosts {
+		pattern = strings.ToLower(pattern)
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func (r EgressRule) containsAnyIP(ips []net.IP) bool {
+	for _, ip := range ips {
+		if r.containsIP(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r EgressRule) containsAllIPs(ips []net.IP) bool {
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !r.containsIP(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r EgressRule) containsIP(ip net.IP) bool {
+	for _, network := range r.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateEgressRules returns a DeniedURLError naming the rule that rejected the target, or nil if the target is allowed
+func EvaluateEgressRules(rules []EgressRule, target EgressTarget) error {
+	allowRules := []string{}
+	for _, rule := range rules {
+		if !rule.AppliesTo(target.Project) {
+			continue
+		}
+		if rule.Action == EgressActionAllow {
+			allowRules = append(allowRules, rule.Name)
+			continue
+		}
+		if rule.Matches(target) {
+			return NewCurlError(fmt.Errorf("request to '%s' denied by egress rule '%s'", target.Host, rule.Name), DeniedURLError)
+		}
+	}
+	if len(allowRules) == 0 {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.Action == EgressActionAllow && rule.AppliesTo(target.Project) && rule.Matches(target) {
+			return nil
+		}
+	}
+	return NewCurlError(fmt.Errorf("request to '%s' is not allowed by egress rules '%s'", target.Host, strings.Join(allowRules, "', '")), DeniedURLError)
+}
+
+// NewEgressTarget derives the scheme, host and port of the target from the URL of a request
+func NewEgressTarget(project string, rawURL string, ips []net.IP) (EgressTarget, error) {
+	parsedURL, err := neturl.Parse(rawURL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return EgressTarget{}, NewCurlError(fmt.Errorf("invalid URL '%s'", rawURL), InvalidCommandError)
+	}
+	port := defaultPort(parsedURL.Scheme)
+	if parsedURL.Port() != "" {
+		if port, err = strconv.Atoi(parsedURL.Port()); err != nil {
+			return EgressTarget{}, NewCurlError(fmt.Errorf("invalid port in URL '%s'", rawURL), InvalidCommandError)
+		}
+	}
+	return EgressTarget{
+		Project: project,
+		Scheme:  strings.ToLower(parsedURL.Scheme),
+		Host:    parsedURL.Hostname(),
+		Port:    port,
+		IPs:     ips,
+	}, nil
+}
+
+func defaultPort(scheme string) int {
+	switch strings.ToLower(scheme) {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	}
+	return 0
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package lib_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testEgressRules = `
+- name: deny-cluster-network
+  action: deny
+  cidrs: [10.0.0.0/8, 127.0.0.0/8]
+- name: deny-plain-http
+  action: deny
+  schemes: [http]
+  projects: [secure-project]
+- name: allow-internal
+  action: allow
+  hosts: ["*.internal.example.com"]
+  ports: [443, 8443]
+  projects: [secure-project]
+- name: allow-partner
+  action: allow
+  cidrs: [203.0.113.0/24]
+  projects: [secure-project]
+`
+
+func TestParseEgressRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "valid rules", content: testEgressRules, want: 4},
+		{name: "invalid yaml", content: "- name: [", wantErr: true},
+		{name: "missing name", content: "- action: deny\n  hosts: [example.com]", wantErr: true},
+		{name: "duplicate name", content: "- name: a\n  action: deny\n  hosts: [a.com]\n- name: a\n  action: deny\n  hosts: [b.com]", wantErr: true},
+		{name: "invalid action", content: "- name: a\n  action: block\n  hosts: [a.com]", wantErr: true},
+		{name: "no criteria", content: "- name: a\n  action: deny", wantErr: true},
+		{name: "invalid cidr", content: "- name: a\n  action: deny\n  cidrs: [10.0.0.0]", wantErr: true},
+		{name: "invalid host wildcard", content: "- name: a\n  action: allow\n  hosts: [\"api.*.example.com\"]", wantErr: true},
+		{name: "invalid port", content: "- name: a\n  action: allow\n  ports: [70000]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lib.ParseEgressRules(tt.content)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Len(t, got, tt.want)
+		})
+	}
+}
+
+func TestEvaluateEgressRules(t *testing.T) {
+	rules, err := lib.ParseEgressRules(testEgressRules)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name        string
+		project     string
+		url         string
+		ips         []string
+		wantErrRule string
+	}{
+		{name: "public target", project: "my-project", url: "http://example.com", ips: []string{"93.184.216.34"}},
+		{name: "denied cidr", project: "my-project", url: "http://example.com", ips: []string{"93.184.216.34", "10.1.2.3"}, wantErrRule: "deny-cluster-network"},
+		{name: "denied cidr before allow rules", project: "secure-project", url: "https://api.internal.example.com", ips: []string{"127.0.0.1"}, wantErrRule: "deny-cluster-network"},
+		{name: "denied scheme", project: "secure-project", url: "http://api.internal.example.com", ips: []string{"192.0.2.1"}, wantErrRule: "deny-plain-http"},
+		{name: "allowed host wildcard", project: "secure-project", url: "https://api.internal.example.com", ips: []string{"192.0.2.1"}},
+		{name: "allowed nested subdomain", project: "secure-project", url: "https://a.b.INTERNAL.example.com:8443/path", ips: []string{"192.0.2.1"}},
+		{name: "host wildcard does not match the domain itself", project: "secure-project", url: "https://internal.example.com", ips: []string{"192.0.2.1"}, wantErrRule: "allow-internal', 'allow-partner"},
+		{name: "host wildcard does not match suffix", project: "secure-project", url: "https://evilinternal.example.com", ips: []string{"192.0.2.1"}, wantErrRule: "allow-internal', 'allow-partner"},
+		{name: "port not allowed", project: "secure-project", url: "https://api.internal.example.com:9000", ips: []string{"192.0.2.1"}, wantErrRule: "allow-internal', 'allow-partner"},
+		{name: "allowed cidr", project: "secure-project", url: "https://partner.com", ips: []string{"203.0.113.10"}},
+		{name: "allow cidr requires all addresses", project: "secure-project", url: "https://partner.com", ips: []string{"203.0.113.10", "198.51.100.1"}, wantErrRule: "allow-internal', 'allow-partner"},
+		{name: "allow cidr requires resolved addresses", project: "secure-project", url: "https://partner.com", ips: []string{}, wantErrRule: "allow-internal', 'allow-partner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ips := []net.IP{}
+			for _, ip := range tt.ips {
+				ips = append(ips, net.ParseIP(ip))
+			}
+			target, err := lib.NewEgressTarget(tt.project, tt.url, ips)
+			require.Nil(t, err)
+
+			err = lib.EvaluateEgressRules(rules, target)
+			if tt.wantErrRule == "" {
+				require.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			require.True(t, lib.IsDeniedURLError(err))
+			require.Contains(t, err.Error(), "'"+tt.wantErrRule+"'")
+		})
+	}
+}
+
+func TestNewEgressTarget(t *testing.T) {
+	target, err := lib.NewEgressTarget("my-project", "HTTPS://Example.com/path", nil)
+	require.Nil(t, err)
+	require.Equal(t, lib.EgressTarget{Project: "my-project", Scheme: "https", Host: "Example.com", Port: 443}, target)
+
+	target, err = lib.NewEgressTarget("my-project", "http://example.com:8080", nil)
+	require.Nil(t, err)
+	require.Equal(t, 8080, target.Port)
+
+	_, err = lib.NewEgressTarget("my-project", "not a url", nil)
+	require.NotNil(t, err)
+}
+
+func TestEgressRuleProvider_Get(t *testing.T) {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	kubeClient := fake.NewSimpleClientset()
+	provider := lib.NewEgressRuleProvider(kubeClient)
+
+	rules, err := provider.Get()
+	require.Nil(t, err)
+	require.Empty(t, rules)
+
+	_, err = kubeClient.CoreV1().ConfigMaps("keptn").Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.WebhookConfigMap, Namespace: "keptn"},
+		Data:       map[string]string{"denyList": "kubernetes", "egressRules": testEgressRules},
+	}, metav1.CreateOptions{})
+	require.Nil(t, err)
+
+	rules, err = provider.Get()
+	require.Nil(t, err)
+	require.Len(t, rules, 4)
+	require.Equal(t, "deny-cluster-network", rules[0].Name)
+
+	_, err = kubeClient.CoreV1().ConfigMaps("keptn").Update(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.WebhookConfigMap, Namespace: "keptn"},
+		Data:       map[string]string{"egressRules": "- name: invalid"},
+	}, metav1.UpdateOptions{})
+	require.Nil(t, err)
+
+	_, err = provider.Get()
+	require.NotNil(t, err)
+}
+
+func TestEgressRuleProvider_GetWithoutKubernetes(t *testing.T) {
+	rules, err := lib.NewEgressRuleProvider(nil).Get()
+	require.Nil(t, err)
+	require.Empty(t, rules)
+}
@@ -4,6 +4,8 @@ type WebhookExecutionError struct {
 	PreExecutionError bool
 	ErrorObj          error
 	ExecutedRequests  int
+	// Attempts contains the number of attempts of each executed request, if the webhook declares a retry policy
+	Attempts []int
 }
 
 type WebhookExecutionErrorOpt func(executionError *WebhookExecutionError)
@@ -14,6 +16,12 @@ func WithNrOfExecutedRequests(nrRequests int) WebhookExecutionErrorOpt {
 	}
 }
 
+func WithAttempts(attempts []int) WebhookExecutionErrorOpt {
+	return func(executionError *WebhookExecutionError) {
+		executionError.Attempts = attempts
+	}
+}
+
 func NewWebhookExecutionError(preExec bool, err error, opts ...WebhookExecutionErrorOpt) *WebhookExecutionError {
 	whe := &WebhookExecutionError{
 		PreExecutionError: preExec,
@@ -31,3 +39,7 @@ func NewWebhookExecutionError(preExec bool, err error, opts ...WebhookExecutionE
 func (whe WebhookExecutionError) Error() string {
 	return whe.ErrorObj.Error()
 }
+
+func (whe WebhookExecutionError) Unwrap() error {
+	return whe.ErrorObj
+}
@@ -0,0 +1,194 @@
+package lib
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// inbound webhook authentication types
+const (
+	InboundAuthHMAC  = "hmac"
+	InboundAuthToken = "token"
+)
+
+const (
+	// DefaultInboundTokenHeader contains the token of inbound webhooks using token authentication, if no header is set
+	DefaultInboundTokenHeader = "X-Keptn-Webhook-Token"
+	// DefaultInboundSignatureMaxAge is the maximum age of the timestamp of a signed inbound request
+	DefaultInboundSignatureMaxAge = 5 * time.Minute
+	// inboundWebhooksConfigKey is the key of the inbound webhooks within the keptn-webhook-config ConfigMap
+	inboundWebhooksConfigKey = "inboundWebhooks"
+)
+
+// ErrInboundWebhookNotFound is returned if no inbound webhook with the requested name is configured
+var ErrInboundWebhookNotFound = errors.New("inbound webhook not found")
+
+var inboundWebhookNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// InboundWebhook accepts requests of external systems at /v1/inbound/<name> and maps them to a Keptn event
+type InboundWebhook struct {
+	Name  string              `yaml:"name"`
+	Auth  InboundAuth         `yaml:"auth"`
+	Event InboundEventMapping `yaml:"event"`
+}
+
+// InboundAuth authenticates inbound requests using either a token, or an HMAC-SHA256 signature of the request body
+type InboundAuth struct {
+	Type      string           `yaml:"type"`
+	SecretRef WebHookSecretRef `yaml:"secretRef"`
+	// Header contains the token or signature. Defaults to X-Keptn-Webhook-Token for tokens and X-Keptn-Signature for signatures
+	Header string `yaml:"header,omitempty"`
+	// TimestampHeader is only used for HMAC authentication. If set, the signature is calculated over "<timestamp>.<body>",
+	// like the signature of outgoing requests, and requests with a timestamp older than 5 minutes are rejected
+	TimestampHeader string `yaml:"timestampHeader,omitempty"`
+}
+
+// InboundEventMapping contains the templates of the Keptn event that is sent for an inbound request.
+// The templates can reference the request using {{.body}}, {{.headers}} and {{.query}}
+type InboundEventMapping struct {
+	Type    string `yaml:"type"`
+	Project string `yaml:"project"`
+	Stage   string `yaml:"stage,omitempty"`
+	Service string `yaml:"service,omitempty"`
+	// Data is a template resulting in a JSON object, which is used as data of the event
+	Data string `yaml:"data,omitempty"`
+}
+
+// GetHeader returns the name of the header containing the token or signature
+func (a InboundAuth) GetHeader() string {
+	if a.Header != "" {
+		return a.Header
+	}
+	if a.Type == InboundAuthHMAC {
+		return DefaultSignatureHeader
+	}
+	return DefaultInboundTokenHeader
+}
+
+// Verify checks the token or signature of an inbound request with the given secret
+func (a InboundAuth) Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	value := header.Get(a.GetHeader())
+	if value == "" {
+		return fmt.Errorf("missing header %s", a.GetHeader())
+	}
+	if a.Type == InboundAuthToken {
+		value = strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(value), []byte(secret)) != 1 {
+			return errors.New("invalid token")
+		}
+		return nil
+	}
+
+	signedContent := string(body)
+	if a.TimestampHeader != "" {
+		timestamp := header.Get(a.TimestampHeader)
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid header %s", a.TimestampHeader)
+		}
+		if age := now.Sub(time.Unix(seconds, 0)); age > DefaultInboundSignatureMaxAge || age < -DefaultInboundSignatureMaxAge {
+			return errors.New("signature timestamp is outside of the accepted range")
+		}
+		signedContent = timestamp + "." + signedContent
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signedContent))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(value), []byte(expected)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+//go:generate moq  -pkg fake -out ./fake/inbound_webhook_provider_mock.go . InboundWebhookProvider
+type InboundWebhookProvider interface {
+	Get(name string) (*InboundWebhook, error)
+}
+
+type inboundWebhookProvider struct {
+	kubeClient kubernetes.Interface
+}
+
+// NewInboundWebhookProvider returns an InboundWebhookProvider reading the inbound webhooks from the keptn-webhook-config ConfigMap.
+// If no kubeClient is provided, e.g. when running outside of Kubernetes, there are no inbound webhooks
+func NewInboundWebhookProvider(kubeClient kubernetes.Interface) InboundWebhookProvider {
+	return inboundWebhookProvider{kubeClient: kubeClient}
+}
+
+func (p inboundWebhookProvider) Get(name string) (*InboundWebhook, error) {
+	if p.kubeClient == nil {
+		return nil, ErrInboundWebhookNotFound
+	}
+	configMap, err := p.kubeClient.CoreV1().ConfigMaps(GetNamespaceFromEnvVar()).Get(context.TODO(), WebhookConfigMap, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, ErrInboundWebhookNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to get ConfigMap %s content: %w", WebhookConfigMap, err)
+	}
+	webhooks, err := ParseInboundWebhooks(configMap.Data[inboundWebhooksConfigKey])
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		if webhooks[i].Name == name {
+			return &webhooks[i], nil
+		}
+	}
+	return nil, ErrInboundWebhookNotFound
+}
+
+// ParseInboundWebhooks decodes and validates a YAML list of inbound webhooks
+func ParseInboundWebhooks(content string) ([]InboundWebhook, error) {
+	webhooks := []InboundWebhook{}
+	if strings.TrimSpace(content) == "" {
+		return webhooks, nil
+	}
+	if err := yaml.Unmarshal([]byte(content), &webhooks); err != nil {
+		return nil, fmt.Errorf("could not parse inbound webhooks: %w", err)
+	}
+	names := map[string]bool{}
+	for _, webhook := range webhooks {
+		if err := webhook.validate(); err != nil {
+			return nil, err
+		}
+		if names[webhook.Name] {
+			return nil, fmt.Errorf("inbound webhook name '%s' is not unique", webhook.Name)
+		}
+		names[webhook.Name] = true
+	}
+	return webhooks, nil
+}
+
+func (w InboundWebhook) validate() error {
+	if !inboundWebhookNameRegex.MatchString(w.Name) {
+		return fmt.Errorf("invalid inbound webhook name '%s'", w.Name)
+	}
+	if w.Auth.Type != InboundAuthHMAC && w.Auth.Type != InboundAuthToken {
+		return fmt.Errorf("inbound webhook '%s' must use auth type '%s' or '%s'", w.Name, InboundAuthHMAC, InboundAuthToken)
+	}
+	if w.Auth.SecretRef.Name == "" || w.Auth.SecretRef.Key == "" {
+		return fmt.Errorf("inbound webhook '%s' must reference the secret used for authentication", w.Name)
+	}
+	if w.Auth.TimestampHeader != "" && w.Auth.Type != InboundAuthHMAC {
+		return fmt.Errorf("inbound webhook '%s' can only use a timestamp header with auth type '%s'", w.Name, InboundAuthHMAC)
+	}
+	if w.Event.Type == "" || w.Event.Project == "" {
+		return fmt.Errorf("inbound webhook '%s' must contain the event type and project", w.Name)
+	}
+	return nil
+}
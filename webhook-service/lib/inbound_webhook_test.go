@@ -0,0 +1,139 @@
+package lib_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testInboundWebhooks = `
+- name: registry-push
+  auth:
+    type: token
+    secretRef:
+      name: registry-token
+      key: token
+  event:
+    type: sh.keptn.event.dev.delivery.triggered
+    project: "{{.body.project}}"
+    stage: dev
+    service: "{{.body.service}}"
+- name: github-release
+  auth:
+    type: hmac
+    secretRef:
+      name: github-secret
+      key: secret
+    header: X-Hub-Signature-256
+  event:
+    type: sh.keptn.event.release.triggered
+    project: my-project
+`
+
+func TestParseInboundWebhooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "valid webhooks", content: testInboundWebhooks, want: 2},
+		{name: "invalid yaml", content: "- name: [", wantErr: true},
+		{name: "invalid name", content: "- name: My_Webhook\n  auth: {type: token, secretRef: {name: a, key: b}}\n  event: {type: sh.keptn.event.a.triggered, project: p}", wantErr: true},
+		{name: "duplicate name", content: "- name: a\n  auth: {type: token, secretRef: {name: a, key: b}}\n  event: {type: sh.keptn.event.a.triggered, project: p}\n- name: a\n  auth: {type: token, secretRef: {name: a, key: b}}\n  event: {type: sh.keptn.event.a.triggered, project: p}", wantErr: true},
+		{name: "missing auth", content: "- name: a\n  event: {type: sh.keptn.event.a.triggered, project: p}", wantErr: true},
+		{name: "missing secret", content: "- name: a\n  auth: {type: hmac}\n  event: {type: sh.keptn.event.a.triggered, project: p}", wantErr: true},
+		{name: "timestamp header with token", content: "- name: a\n  auth: {type: token, timestampHeader: X-Timestamp, secretRef: {name: a, key: b}}\n  event: {type: sh.keptn.event.a.triggered, project: p}", wantErr: true},
+		{name: "missing project", content: "- name: a\n  auth: {type: token, secretRef: {name: a, key: b}}\n  event: {type: sh.keptn.event.a.triggered}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lib.ParseInboundWebhooks(tt.content)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Len(t, got, tt.want)
+		})
+	}
+}
+
+func TestInboundAuth_Verify(t *testing.T) {
+	body := []byte(`{"foo":"bar"}`)
+	now := time.Unix(1640995200, 0)
+	header := func(kv ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i < len(kv); i += 2 {
+			h.Set(kv[i], kv[i+1])
+		}
+		return h
+	}
+	// calculated with: echo -n '{"foo":"bar"}' | openssl dgst -sha256 -hmac 'my-secret'
+	bodySignature := "sha256=0b23358ec8690624bb0a1bdfb15fd2d1339a6098384744c8a6622db7ff09bed3"
+	timestampSignature := "sha256=" + lib.ComputeSignature("my-secret", "1640995200", string(body))
+
+	tests := []struct {
+		name    string
+		auth    lib.InboundAuth
+		header  http.Header
+		wantErr bool
+	}{
+		{name: "valid token", auth: lib.InboundAuth{Type: lib.InboundAuthToken}, header: header(lib.DefaultInboundTokenHeader, "my-secret")},
+		{name: "valid bearer token", auth: lib.InboundAuth{Type: lib.InboundAuthToken, Header: "Authorization"}, header: header("Authorization", "Bearer my-secret")},
+		{name: "invalid token", auth: lib.InboundAuth{Type: lib.InboundAuthToken}, header: header(lib.DefaultInboundTokenHeader, "other"), wantErr: true},
+		{name: "missing token", auth: lib.InboundAuth{Type: lib.InboundAuthToken}, header: header(), wantErr: true},
+		{name: "valid signature", auth: lib.InboundAuth{Type: lib.InboundAuthHMAC}, header: header(lib.DefaultSignatureHeader, bodySignature)},
+		{name: "invalid signature", auth: lib.InboundAuth{Type: lib.InboundAuthHMAC}, header: header(lib.DefaultSignatureHeader, "sha256=0000"), wantErr: true},
+		{name: "valid signature with timestamp", auth: lib.InboundAuth{Type: lib.InboundAuthHMAC, TimestampHeader: "X-Timestamp"}, header: header(lib.DefaultSignatureHeader, timestampSignature, "X-Timestamp", "1640995200")},
+		{name: "signature without timestamp", auth: lib.InboundAuth{Type: lib.InboundAuthHMAC, TimestampHeader: "X-Timestamp"}, header: header(lib.DefaultSignatureHeader, timestampSignature), wantErr: true},
+		{name: "expired timestamp", auth: lib.InboundAuth{Type: lib.InboundAuthHMAC, TimestampHeader: "X-Timestamp"}, header: header(lib.DefaultSignatureHeader, "sha256="+lib.ComputeSignature("my-secret", "1640990000", string(body)), "X-Timestamp", "1640990000"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Verify("my-secret", tt.header, body, now)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestInboundWebhookProvider_Get(t *testing.T) {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	kubeClient := fake.NewSimpleClientset()
+	provider := lib.NewInboundWebhookProvider(kubeClient)
+
+	_, err := provider.Get("registry-push")
+	require.ErrorIs(t, err, lib.ErrInboundWebhookNotFound)
+
+	_, err = kubeClient.CoreV1().ConfigMaps("keptn").Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.WebhookConfigMap, Namespace: "keptn"},
+		Data:       map[string]string{"inboundWebhooks": testInboundWebhooks},
+	}, metav1.CreateOptions{})
+	require.Nil(t, err)
+
+	webhook, err := provider.Get("github-release")
+	require.Nil(t, err)
+	require.Equal(t, "X-Hub-Signature-256", webhook.Auth.GetHeader())
+	require.Equal(t, "my-project", webhook.Event.Project)
+
+	_, err = provider.Get("unknown")
+	require.ErrorIs(t, err, lib.ErrInboundWebhookNotFound)
+}
+
+func TestInboundWebhookProvider_GetWithoutKubernetes(t *testing.T) {
+	_, err := lib.NewInboundWebhookProvider(nil).Get("registry-push")
+	require.ErrorIs(t, err, lib.ErrInboundWebhookNotFound)
+}
@@ -0,0 +1,215 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keptn/keptn/go-sdk/pkg/sdk"
+	logger "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// JobsSecretName is the name of the secret used by the K8sJobStore. It has to be created beforehand, so the webhook-service only needs
+// permissions for this secret. Each pending job is stored under its ID, since the responses of a job may contain sensitive data
+const JobsSecretName = "keptn-webhook-jobs"
+
+// MaxPersistedResponseSize is the maximum size of each response body that is kept in a Job by Trim
+const MaxPersistedResponseSize = 64 * 1024
+
+// MaxPersistedJobSize is the maximum size of an encoded job
+const MaxPersistedJobSize = 256 * 1024
+
+// MaxPersistedJobsSize is the maximum size of all encoded pending jobs, which stays below the size limit of a secret
+const MaxPersistedJobsSize = 900 * 1024
+
+// ErrJobNotFound is returned if a job does not exist (anymore)
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobTooLarge is returned if a job exceeds MaxPersistedJobSize
+var ErrJobTooLarge = errors.New("job too large")
+
+// ErrJobStoreFull is returned if storing a job would exceed MaxPersistedJobsSize
+var ErrJobStoreFull = errors.New("job store full")
+
+// Job is an asynchronous webhook task that has been started, but not completed yet
+type Job struct {
+	ID string `json:"id"`
+	// Token authenticates the callback of the job
+	Token   string         `json:"token"`
+	Event   sdk.KeptnEvent `json:"event"`
+	Webhook Webhook        `json:"webhook"`
+	// Responses, Outputs, Attempts and NamedResponses are the results of the requests that started the job
+	Responses      []string               `json:"responses"`
+	Outputs        map[string]interface{} `json:"outputs"`
+	Attempts       []int                  `json:"attempts,omitempty"`
+	NamedResponses map[string]interface{} `json:"namedResponses"`
+	Deadline       time.Time              `json:"deadline"`
+}
+
+// Trim truncates the responses of the job to MaxPersistedResponseSize. Named responses exceeding this size only keep the outputs
+// of the job, so subsequent poll requests can only reference large responses via outputs
+func (j *Job) Trim() {
+	for i, response := range j.Responses {
+		if len(response) > MaxPersistedResponseSize {
+			j.Responses[i] = response[:MaxPersistedResponseSize]
+		}
+	}
+	for name, namedResponse := range j.NamedResponses {
+		content, err := json.Marshal(namedResponse)
+		if err == nil && len(content) <= MaxPersistedResponseSize {
+			continue
+		}
+		trimmed := map[string]interface{}{}
+		if fields, ok := namedResponse.(map[string]interface{}); ok {
+			for field, value := range fields {
+				if _, isOutput := j.Outputs[field]; isOutput {
+					trimmed[field] = value
+				}
+			}
+		}
+		j.NamedResponses[name] = trimmed
+	}
+}
+
+//go:generate moq  -pkg fake -out ./fake/job_store_mock.go . IJobStore
+type IJobStore interface {
+	Save(job *Job) error
+	Get(id string) (*Job, error)
+	Delete(id string) error
+	List() ([]*Job, error)
+}
+
+// K8sJobStore persists the pending jobs in the secret keptn-webhook-jobs, so they survive a restart of the webhook-service
+type K8sJobStore struct {
+	k8sClient kubernetes.Interface
+	mutex     sync.Mutex
+}
+
+func NewK8sJobStore(k8sClient kubernetes.Interface) *K8sJobStore {
+	return &K8sJobStore{k8sClient: k8sClient}
+}
+
+func (s *K8sJobStore) Save(job *Job) error {
+	content, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("could not marshal job %s: %w", job.ID, err)
+	}
+	if len(content) > MaxPersistedJobSize {
+		return fmt.Errorf("%w: job %s has %d bytes, the maximum is %d bytes", ErrJobTooLarge, job.ID, len(content), MaxPersistedJobSize)
+	}
+
+	err = s.updateSecret(func(data map[string][]byte) error {
+		size := len(content)
+		for id, existing := range data {
+			if id != job.ID {
+				size += len(existing)
+			}
+		}
+		if size > MaxPersistedJobsSize {
+			return fmt.Errorf("%w: pending jobs would have %d bytes, the maximum is %d bytes", ErrJobStoreFull, size, MaxPersistedJobsSize)
+		}
+		data[job.ID] = content
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *K8sJobStore) Get(id string) (*Job, error) {
+	secret, err := s.getSecret()
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("could not get job %s: %w", id, err)
+	}
+	content, ok := secret.Data[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return decodeJob(id, content)
+}
+
+func (s *K8sJobStore) Delete(id string) error {
+	err := s.updateSecret(func(data map[string][]byte) error {
+		if _, ok := data[id]; !ok {
+			return ErrJobNotFound
+		}
+		delete(data, id)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) || k8serrors.IsNotFound(err) {
+			return ErrJobNotFound
+		}
+		return fmt.Errorf("could not delete job %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *K8sJobStore) List() ([]*Job, error) {
+	secret, err := s.getSecret()
+	if err != nil {
+		return nil, fmt.Errorf("could not list jobs: %w", err)
+	}
+	ids := make([]string, 0, len(secret.Data))
+	for id := range secret.Data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	jobs := []*Job{}
+	for _, id := range ids {
+		job, err := decodeJob(id, secret.Data[id])
+		if err != nil {
+			// a job that can not be decoded must not prevent resuming the other jobs
+			logger.WithError(err).Errorf("Skipping stored job %s", id)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *K8sJobStore) getSecret() (*corev1.Secret, error) {
+	return s.k8sClient.CoreV1().Secrets(GetNamespaceFromEnvVar()).Get(context.TODO(), JobsSecretName, metav1.GetOptions{})
+}
+
+// updateSecret applies the change to the jobs in the secret. Changes within the webhook-service are serialized,
+// and the change is retried if the secret has been modified concurrently
+func (s *K8sJobStore) updateSecret(change func(data map[string][]byte) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := s.getSecret()
+		if err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		if err := change(secret.Data); err != nil {
+			return err
+		}
+		_, err = s.k8sClient.CoreV1().Secrets(GetNamespaceFromEnvVar()).Update(context.TODO(), secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func decodeJob(id string, content []byte) (*Job, error) {
+	job := &Job{}
+	if err := json.Unmarshal(content, job); err != nil {
+		return nil, fmt.Errorf("could not decode job %s: %w", id, err)
+	}
+	return job, nil
+}
@@ -0,0 +1,142 @@
+package lib_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestJobStore() *lib.K8sJobStore {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	return lib.NewK8sJobStore(fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.JobsSecretName, Namespace: "keptn"},
+	}))
+}
+
+func TestK8sJobStore(t *testing.T) {
+	store := newTestJobStore()
+
+	jobs, err := store.List()
+	require.Nil(t, err)
+	require.Empty(t, jobs)
+
+	_, err = store.Get("my-job")
+	require.ErrorIs(t, err, lib.ErrJobNotFound)
+
+	deadline := time.Date(2022, 2, 1, 12, 0, 0, 0, time.UTC)
+	job := &lib.Job{
+		ID:    "my-job",
+		Token: "my-token",
+		Webhook: lib.Webhook{
+			SubscriptionID: "my-subscription-id",
+			Requests: []interface{}{
+				map[string]interface{}{"url": "http://local:8080", "method": "POST"},
+			},
+			Async: &lib.AsyncSpec{Callback: &lib.CallbackSpec{}},
+		},
+		Responses:      []string{`{"id": "42"}`},
+		NamedResponses: map[string]interface{}{"startJob": map[string]interface{}{"id": "42"}},
+		Deadline:       deadline,
+	}
+	require.Nil(t, store.Save(job))
+	// saving a job again updates it
+	require.Nil(t, store.Save(job))
+	require.Nil(t, store.Save(&lib.Job{ID: "other-job", Deadline: deadline}))
+
+	got, err := store.Get("my-job")
+	require.Nil(t, err)
+	require.Equal(t, job, got)
+	require.Equal(t, "http://local:8080", lib.ConvertToRequest(got.Webhook.Requests[0]).URL)
+
+	jobs, err = store.List()
+	require.Nil(t, err)
+	require.Len(t, jobs, 2)
+
+	require.Nil(t, store.Delete("my-job"))
+	require.ErrorIs(t, store.Delete("my-job"), lib.ErrJobNotFound)
+
+	jobs, err = store.List()
+	require.Nil(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "other-job", jobs[0].ID)
+}
+
+func TestK8sJobStore_SaveTooLargeJob(t *testing.T) {
+	store := newTestJobStore()
+
+	job := &lib.Job{ID: "my-job", Responses: []string{strings.Repeat("a", lib.MaxPersistedJobSize)}}
+	require.ErrorIs(t, store.Save(job), lib.ErrJobTooLarge)
+
+	_, err := store.Get("my-job")
+	require.ErrorIs(t, err, lib.ErrJobNotFound)
+}
+
+func TestK8sJobStore_SaveWhenStoreIsFull(t *testing.T) {
+	store := newTestJobStore()
+
+	// each job takes a bit more than a fifth of the store
+	response := strings.Repeat("a", lib.MaxPersistedJobsSize/5)
+	for i := 0; i < 4; i++ {
+		require.Nil(t, store.Save(&lib.Job{ID: fmt.Sprintf("job-%d", i), Responses: []string{response}}))
+	}
+	require.ErrorIs(t, store.Save(&lib.Job{ID: "my-job", Responses: []string{response}}), lib.ErrJobStoreFull)
+
+	// updating a stored job does not count its previous size
+	require.Nil(t, store.Save(&lib.Job{ID: "job-0", Responses: []string{response}}))
+}
+
+func TestK8sJobStore_SecretDoesNotExist(t *testing.T) {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	store := lib.NewK8sJobStore(fake.NewSimpleClientset())
+
+	require.NotNil(t, store.Save(&lib.Job{ID: "my-job"}))
+	_, err := store.Get("my-job")
+	require.ErrorIs(t, err, lib.ErrJobNotFound)
+	require.ErrorIs(t, store.Delete("my-job"), lib.ErrJobNotFound)
+	_, err = store.List()
+	require.NotNil(t, err)
+}
+
+func TestJob_Trim(t *testing.T) {
+	largeValue := strings.Repeat("a", lib.MaxPersistedResponseSize)
+	job := &lib.Job{
+		Responses: []string{`{"id": "42"}`, largeValue + "b"},
+		Outputs:   map[string]interface{}{"id": "42"},
+		NamedResponses: map[string]interface{}{
+			"small": map[string]interface{}{"id": "42", "status": "running"},
+			"large": map[string]interface{}{"id": "42", "payload": largeValue},
+		},
+	}
+
+	job.Trim()
+
+	require.Equal(t, []string{`{"id": "42"}`, largeValue}, job.Responses)
+	require.Equal(t, map[string]interface{}{
+		"small": map[string]interface{}{"id": "42", "status": "running"},
+		"large": map[string]interface{}{"id": "42"},
+	}, job.NamedResponses)
+}
+
+func TestK8sJobStore_ListSkipsUndecodableJobs(t *testing.T) {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	store := lib.NewK8sJobStore(fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.JobsSecretName, Namespace: "keptn"},
+		Data: map[string][]byte{
+			"broken-job": []byte("{not json"),
+			"my-job":     []byte(`{"id": "my-job"}`),
+		},
+	}))
+
+	jobs, err := store.List()
+	require.Nil(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "my-job", jobs[0].ID)
+}
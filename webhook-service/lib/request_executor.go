@@ -0,0 +1,272 @@
+package lib
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultRequestTimeout is used for requests that do not specify a timeout
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultMaxResponseSize is the maximum number of bytes that are read from a response body
+	DefaultMaxResponseSize = 1024 * 1024
+)
+
+var supportedProxySchemes = []string{"http", "https", "socks5"}
+
+//go:generate moq  -pkg fake -out ./fake/request_executor_mock.go . IRequestExecutor
+type IRequestExecutor interface {
+	Execute(request Request) (*Response, error)
+}
+
+// HTTPRequestExecutor executes v1beta1 requests using net/http instead of curl
+type HTTPRequestExecutor struct {
+	defaultTimeout      time.Duration
+	maxResponseSize     int64
+	denyListProvider    DenyListProvider
+	egressRuleProvider  EgressRuleProvider
+	useEnvironmentProxy bool
+}
+
+type HTTPRequestExecutorOption func(executor *HTTPRequestExecutor)
+
+// WithDefaultTimeout sets the timeout for requests that do not specify a timeout
+func WithDefaultTimeout(timeout time.Duration) HTTPRequestExecutorOption {
+	return func(executor *HTTPRequestExecutor) {
+		executor.defaultTimeout = timeout
+	}
+}
+
+// WithMaxResponseSize sets the maximum number of bytes that are accepted in a response body
+func WithMaxResponseSize(maxResponseSize int64) HTTPRequestExecutorOption {
+	return func(executor *HTTPRequestExecutor) {
+		executor.maxResponseSize = maxResponseSize
+	}
+}
+
+// WithDialDenyList validates the IP address of each connection against the IPs in the deny list.
+// Since the IP is checked after the DNS lookup, this also prevents hosts from resolving to a denied IP after the request has been validated
+func WithDialDenyList(denyListProvider DenyListProvider) HTTPRequestExecutorOption {
+	return func(executor *HTTPRequestExecutor) {
+		executor.denyListProvider = denyListProvider
+	}
+}
+
+// WithDialEgressRules evaluates the egress rules of the project of a request against the IP address and port of each connection.
+// If the request is sent via a proxy, the rules are evaluated against the proxy, since it is the only endpoint that is connected to
+func WithDialEgressRules(egressRuleProvider EgressRuleProvider) HTTPRequestExecutorOption {
+	return func(executor *HTTPRequestExecutor) {
+		executor.egressRuleProvider = egressRuleProvider
+	}
+}
+
+// WithEnvironmentProxy sends requests that do not specify a proxy via the proxy set in the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars.
+// Since the connection checks then only see the address of the proxy, this has to be enabled explicitly
+func WithEnvironmentProxy() HTTPRequestExecutorOption {
+	return func(executor *HTTPRequestExecutor) {
+		executor.useEnvironmentProxy = true
+	}
+}
+
+func NewHTTPRequestExecutor(opts ...HTTPRequestExecutorOption) *HTTPRequestExecutor {
+	executor := &HTTPRequestExecutor{
+		defaultTimeout:  DefaultRequestTimeout,
+		maxResponseSize: DefaultMaxResponseSize,
+	}
+	for _, o := range opts {
+		o(executor)
+	}
+	return executor
+}
+
+// Execute performs the request and returns the response regardless of its status code
+func (e *HTTPRequestExecutor) Execute(request Request) (*Response, error) {
+	client, err := e.newClient(request)
+	if err != nil {
+		return nil, &CurlError{err: err, reason: InvalidCommandError}
+	}
+	defer client.CloseIdleConnections()
+
+	timeout, err := request.GetTimeout(e.defaultTimeout)
+	if err != nil {
+		return nil, &CurlError{err: err, reason: InvalidCommandError}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var body io.Reader
+	if request.Payload != "" {
+		body = strings.NewReader(request.Payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, request.Method, request.URL, body)
+	if err != nil {
+		return nil, &CurlError{err: fmt.Errorf("could not create request: %w", err), reason: InvalidCommandError}
+	}
+	for _, header := range request.Headers {
+		req.Header.Add(header.Key, header.Value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if IsDeniedURLError(err) {
+			return nil, &CurlError{err: fmt.Errorf("could not execute request: %w", err), reason: DeniedURLError}
+		}
+		return nil, &CurlError{err: fmt.Errorf("error during request execution: %w", err), reason: RequestError}
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, e.maxResponseSize+1))
+	if err != nil {
+		return nil, &CurlError{err: fmt.Errorf("could not read response: %w", err), reason: RequestError}
+	}
+	if int64(len(responseBody)) > e.maxResponseSize {
+		return nil, &CurlError{err: fmt.Errorf("response exceeds the maximum size of %d bytes", e.maxResponseSize), reason: RequestError}
+	}
+	return &Response{StatusCode: resp.StatusCode, Body: string(responseBody)}, nil
+}
+
+func (e *HTTPRequestExecutor) newClient(request Request) (*http.Client, error) {
+	tlsConfig, err := request.TLS.toTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL, err := e.getProxyURL(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout: e.defaultTimeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			if err := e.validateDialedAddress(network, address, c); err != nil {
+				return err
+			}
+			if proxyURL != nil {
+				return e.validateDialedEgress(request.project, proxyURL.String(), address)
+			}
+			return e.validateDialedEgress(request.project, request.URL, address)
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               http.ProxyURL(proxyURL),
+			DialContext:         dialer.DialContext,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		// like curl, redirects are not followed, since the target of the redirect has not been validated
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// validateDialedAddress is called with the resolved IP address right before a connection is established
+func (e *HTTPRequestExecutor) validateDialedAddress(network string, address string, _ syscall.RawConn) error {
+	if e.denyListProvider == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return &CurlError{err: fmt.Errorf("invalid address %s", address), reason: DeniedURLError}
+	}
+	ip := net.ParseIP(host)
+	for _, denied := range e.denyListProvider.Get() {
+		if denied == address {
+			return &CurlError{err: fmt.Errorf("request contains denied IP address '%s'", denied), reason: DeniedURLError}
+		}
+		if deniedIP := net.ParseIP(denied); deniedIP != nil && deniedIP.Equal(ip) {
+			return &CurlError{err: fmt.Errorf("request contains denied IP address '%s'", denied), reason: DeniedURLError}
+		}
+	}
+	return nil
+}
+
+func (e *HTTPRequestExecutor) validateDialedEgress(project string, url string, address string) error {
+	if e.egressRuleProvider == nil {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return &CurlError{err: fmt.Errorf("invalid address %s", address), reason: DeniedURLError}
+	}
+	rules, err := e.egressRuleProvider.Get()
+	if err != nil {
+		return &CurlError{err: fmt.Errorf("could not load egress rules: %w", err), reason: DeniedURLError}
+	}
+	target, err := NewEgressTarget(project, url, []net.IP{net.ParseIP(host)})
+	if err != nil {
+		return err
+	}
+	if target.Port, err = strconv.Atoi(port); err != nil {
+		return &CurlError{err: fmt.Errorf("invalid address %s", address), reason: DeniedURLError}
+	}
+	return EvaluateEgressRules(rules, target)
+}
+
+func (o *TLSOptions) toTLSConfig() (*tls.Config, error) {
+	if o == nil {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify, //nolint:gosec
+		ServerName:         o.ServerName,
+	}
+	if o.CACert != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(o.CACert)) {
+			return nil, errors.New("could not parse CA certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if o.clientCertPEM != "" || o.clientKeyPEM != "" {
+		certificate, err := tls.X509KeyPair([]byte(o.clientCertPEM), []byte(o.clientKeyPEM))
+		if err != nil {
+			return nil, errors.New("could not parse client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// getProxyURL returns the proxy the request is sent through, or nil if the request is sent directly to its target
+func (e *HTTPRequestExecutor) getProxyURL(request Request) (*neturl.URL, error) {
+	if request.Proxy != "" {
+		return parseProxyURL(request.Proxy)
+	}
+	if !e.useEnvironmentProxy {
+		return nil, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, request.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+func parseProxyURL(proxy string) (*neturl.URL, error) {
+	proxyURL, err := neturl.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	for _, scheme := range supportedProxySchemes {
+		if proxyURL.Scheme == scheme {
+			return proxyURL, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme '%s'", proxyURL.Scheme)
+}
@@ -0,0 +1,310 @@
+package lib_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/keptn/keptn/webhook-service/lib/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRequestExecutor_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/echo":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(r.Method + " " + r.Header.Get("x-token") + " " + string(body)))
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("oops"))
+		case "/redirect":
+			http.Redirect(w, r, "/echo", http.StatusFound)
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		request          lib.Request
+		opts             []lib.HTTPRequestExecutorOption
+		want             string
+		wantStatusCode   int
+		wantErr          string
+		wantDeniedURLErr bool
+	}{
+		{
+			name: "request with headers and payload",
+			request: lib.Request{
+				URL:     server.URL + "/echo",
+				Method:  "POST",
+				Headers: []lib.Header{{Key: "x-token", Value: "my-token"}},
+				Payload: "my-payload",
+			},
+			want: "POST my-token my-payload",
+		},
+		{
+			name:           "error status code",
+			request:        lib.Request{URL: server.URL + "/fail", Method: "GET"},
+			want:           "oops",
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:           "redirects are not followed",
+			request:        lib.Request{URL: server.URL + "/redirect", Method: "GET"},
+			want:           "<a href=\"/echo\">Found</a>.\n\n",
+			wantStatusCode: http.StatusFound,
+		},
+		{
+			name:    "response exceeds size limit",
+			request: lib.Request{URL: server.URL + "/large", Method: "GET"},
+			opts:    []lib.HTTPRequestExecutorOption{lib.WithMaxResponseSize(10)},
+			wantErr: "response exceeds the maximum size of 10 bytes",
+		},
+		{
+			name:    "request timeout",
+			request: lib.Request{URL: server.URL + "/slow", Method: "GET", Timeout: "100ms"},
+			wantErr: "context deadline exceeded",
+		},
+		{
+			name:    "invalid proxy",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET", Proxy: "ftp://my-proxy"},
+			wantErr: "unsupported proxy scheme 'ftp'",
+		},
+		{
+			name:    "invalid CA certificate",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET", TLS: &lib.TLSOptions{CACert: "invalid"}},
+			wantErr: "could not parse CA certificate",
+		},
+		{
+			name:    "dialed IP is denied",
+			request: lib.Request{URL: strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/echo", Method: "GET"},
+			opts: []lib.HTTPRequestExecutorOption{lib.WithDialDenyList(fake.DenyListProviderMock{
+				GetDenyListFunc: func() []string {
+					return []string{"kubernetes", "127.0.0.1", "::1"}
+				},
+			})},
+			wantErr:          "request contains denied IP address",
+			wantDeniedURLErr: true,
+		},
+		{
+			name:    "dialed IP and port is denied",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET"},
+			opts: []lib.HTTPRequestExecutorOption{lib.WithDialDenyList(fake.DenyListProviderMock{
+				GetDenyListFunc: func() []string {
+					return []string{server.Listener.Addr().String()}
+				},
+			})},
+			wantErr:          "request contains denied IP address",
+			wantDeniedURLErr: true,
+		},
+		{
+			name:    "dialed IP is not denied",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET"},
+			opts: []lib.HTTPRequestExecutorOption{lib.WithDialDenyList(fake.DenyListProviderMock{
+				GetDenyListFunc: func() []string {
+					return []string{"1.1.1.1", "127.0.0.1:1"}
+				},
+			})},
+			want: "GET  ",
+		},
+		{
+			name:    "dialed IP is denied by egress rule",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET"}.WithProject("my-project"),
+			opts: []lib.HTTPRequestExecutorOption{lib.WithDialEgressRules(&fake.EgressRuleProviderMock{
+				GetFunc: func() ([]lib.EgressRule, error) {
+					return lib.ParseEgressRules("- name: deny-loopback\n  action: deny\n  cidrs: [127.0.0.0/8]\n  projects: [my-project]")
+				},
+			})},
+			wantErr:          "denied by egress rule 'deny-loopback'",
+			wantDeniedURLErr: true,
+		},
+		{
+			name:    "egress rule of other project",
+			request: lib.Request{URL: server.URL + "/echo", Method: "GET"}.WithProject("my-project"),
+			opts: []lib.HTTPRequestExecutorOption{lib.WithDialEgressRules(&fake.EgressRuleProviderMock{
+				GetFunc: func() ([]lib.EgressRule, error) {
+					return lib.ParseEgressRules("- name: deny-loopback\n  action: deny\n  cidrs: [127.0.0.0/8]\n  projects: [other-project]")
+				},
+			})},
+			want: "GET  ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := lib.NewHTTPRequestExecutor(tt.opts...)
+			got, err := executor.Execute(tt.request)
+			if tt.wantErr != "" {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				require.Equal(t, tt.wantDeniedURLErr, lib.IsDeniedURLError(err))
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got.Body)
+			if tt.wantStatusCode != 0 {
+				require.Equal(t, tt.wantStatusCode, got.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestExecutor_ExecuteTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("success"))
+	}))
+	defer server.Close()
+
+	executor := lib.NewHTTPRequestExecutor()
+
+	// the certificate of the test server is not trusted by default
+	_, err := executor.Execute(lib.Request{URL: server.URL, Method: "GET"})
+	require.NotNil(t, err)
+	require.True(t, lib.IsRequestError(err))
+
+	got, err := executor.Execute(lib.Request{URL: server.URL, Method: "GET", TLS: &lib.TLSOptions{InsecureSkipVerify: true}})
+	require.Nil(t, err)
+	require.Equal(t, "success", got.Body)
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	got, err = executor.Execute(lib.Request{URL: server.URL, Method: "GET", TLS: &lib.TLSOptions{CACert: string(caCert)}})
+	require.Nil(t, err)
+	require.Equal(t, "success", got.Body)
+}
+
+func TestHTTPRequestExecutor_ExecuteMutualTLS(t *testing.T) {
+	certPEM, keyPEM := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(certPEM))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	executor := lib.NewHTTPRequestExecutor()
+	tlsOptions := &lib.TLSOptions{InsecureSkipVerify: true, ClientCert: &lib.ClientCertRef{SecretName: "my-client-cert"}}
+
+	// the server rejects requests without a client certificate
+	_, err := executor.Execute(lib.Request{URL: server.URL, Method: "GET", TLS: tlsOptions})
+	require.NotNil(t, err)
+
+	got, err := executor.Execute(lib.Request{URL: server.URL, Method: "GET", TLS: tlsOptions.WithClientCertificate(string(certPEM), string(keyPEM))})
+	require.Nil(t, err)
+	require.Equal(t, "hello keptn", got.Body)
+
+	_, err = executor.Execute(lib.Request{URL: server.URL, Method: "GET", TLS: tlsOptions.WithClientCertificate("invalid", "invalid")})
+	require.NotNil(t, err)
+	require.False(t, lib.IsRequestError(err))
+}
+
+func generateClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "keptn"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestHTTPRequestExecutor_ExecuteWithProxy(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	executor := lib.NewHTTPRequestExecutor()
+	got, err := executor.Execute(lib.Request{URL: "http://my-webhook:8080/path", Method: "GET", Proxy: proxy.URL})
+	require.Nil(t, err)
+	require.Equal(t, "proxied", got.Body)
+	require.Equal(t, "http://my-webhook:8080/path", proxiedURL)
+}
+
+func TestHTTPRequestExecutor_ExecuteWithProxyDeniedByEgressRules(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	executor := lib.NewHTTPRequestExecutor(lib.WithDialEgressRules(&fake.EgressRuleProviderMock{
+		GetFunc: func() ([]lib.EgressRule, error) {
+			return lib.ParseEgressRules("- name: allow-webhook\n  action: allow\n  cidrs: [192.0.2.0/24]\n  projects: [my-project]")
+		},
+	}))
+	// the connection to the proxy is evaluated against the egress rules, although the target would be allowed
+	_, err := executor.Execute(lib.Request{URL: "http://192.0.2.1:8080/path", Method: "GET", Proxy: proxy.URL}.WithProject("my-project"))
+	require.True(t, lib.IsDeniedURLError(err))
+	require.False(t, proxied)
+}
+
+func TestHTTPRequestExecutor_ExecuteWithEnvironmentProxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+	// the proxy env vars are only read once per process, and are not used for requests to localhost
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	request := lib.Request{URL: "http://my-webhook.invalid:8080/path", Method: "GET"}
+
+	// proxies set in env vars are only used if enabled explicitly
+	_, err := lib.NewHTTPRequestExecutor().Execute(request)
+	require.NotNil(t, err)
+	require.False(t, proxied)
+
+	got, err := lib.NewHTTPRequestExecutor(lib.WithEnvironmentProxy()).Execute(request)
+	require.Nil(t, err)
+	require.Equal(t, "proxied", got.Body)
+	require.True(t, proxied)
+}
+
+func TestRequest_GetTimeout(t *testing.T) {
+	timeout, err := lib.Request{}.GetTimeout(time.Second)
+	require.Nil(t, err)
+	require.Equal(t, time.Second, timeout)
+
+	timeout, err = lib.Request{Timeout: "2m"}.GetTimeout(time.Second)
+	require.Nil(t, err)
+	require.Equal(t, 2*time.Minute, timeout)
+
+	_, err = lib.Request{Timeout: "-1s"}.GetTimeout(time.Second)
+	require.NotNil(t, err)
+
+	_, err = lib.Request{Timeout: "forever"}.GetTimeout(time.Second)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultSignatureHeader contains the HMAC-SHA256 signature of a signed request, e.g. sha256=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+	DefaultSignatureHeader = "X-Keptn-Signature"
+	// DefaultSignatureTimestampHeader contains the unix timestamp (in seconds) that is included in the signature of a signed request
+	DefaultSignatureTimestampHeader = "X-Keptn-Signature-Timestamp"
+)
+
+// SignatureSpec signs the payload of a request with HMAC-SHA256, so the receiver can verify that the request has been sent by Keptn.
+// The signature is calculated over "<timestamp>.<payload>", so the receiver can also reject replayed requests
+type SignatureSpec struct {
+	// SecretEnv is the name of the envFrom entry containing the signing secret
+	SecretEnv       string `yaml:"secretEnv"`
+	Header          string `yaml:"header,omitempty"`
+	TimestampHeader string `yaml:"timestampHeader,omitempty"`
+}
+
+// GetHeader returns the name of the header containing the signature
+func (s SignatureSpec) GetHeader() string {
+	if s.Header == "" {
+		return DefaultSignatureHeader
+	}
+	return s.Header
+}
+
+// GetTimestampHeader returns the name of the header containing the timestamp of the signature
+func (s SignatureSpec) GetTimestampHeader() string {
+	if s.TimestampHeader == "" {
+		return DefaultSignatureTimestampHeader
+	}
+	return s.TimestampHeader
+}
+
+// SignRequest adds the signature and timestamp headers to the request
+func SignRequest(request Request, secret string, timestamp time.Time) Request {
+	if request.Signature == nil {
+		return request
+	}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	signedRequest := request
+	signedRequest.Headers = append(append([]Header{}, request.Headers...),
+		Header{Key: request.Signature.GetTimestampHeader(), Value: ts},
+		Header{Key: request.Signature.GetHeader(), Value: "sha256=" + ComputeSignature(secret, ts, request.Payload)},
+	)
+	return signedRequest
+}
+
+// ComputeSignature returns the hex encoded HMAC-SHA256 of "<timestamp>.<payload>"
+func ComputeSignature(secret string, timestamp string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,36 @@
+package lib_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeSignature(t *testing.T) {
+	// expected value calculated with: echo -n '1640995200.{"foo":"bar"}' | openssl dgst -sha256 -hmac 'my-secret'
+	require.Equal(t, "859b8241935f1c75bad810ef2d73d68acd372199bce976edbe74cdd95bd14638", lib.ComputeSignature("my-secret", "1640995200", `{"foo":"bar"}`))
+}
+
+func TestSignRequest(t *testing.T) {
+	request := lib.Request{
+		URL:       "http://local:8080",
+		Method:    "POST",
+		Headers:   []lib.Header{{Key: "Content-Type", Value: "application/json"}},
+		Payload:   `{"foo":"bar"}`,
+		Signature: &lib.SignatureSpec{SecretEnv: "key"},
+	}
+	signed := lib.SignRequest(request, "my-secret", time.Unix(1640995200, 0))
+
+	require.Equal(t, []lib.Header{
+		{Key: "Content-Type", Value: "application/json"},
+		{Key: lib.DefaultSignatureTimestampHeader, Value: "1640995200"},
+		{Key: lib.DefaultSignatureHeader, Value: "sha256=" + lib.ComputeSignature("my-secret", "1640995200", `{"foo":"bar"}`)},
+	}, signed.Headers)
+	// the headers of the original request are not modified
+	require.Len(t, request.Headers, 1)
+
+	unsigned := lib.SignRequest(lib.Request{URL: "http://local:8080", Method: "GET"}, "my-secret", time.Now())
+	require.Empty(t, unsigned.Headers)
+}
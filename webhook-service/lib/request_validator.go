@@ -2,23 +2,37 @@ package lib
 
 import (
 	"fmt"
+	"net"
 	"strings"
 )
 
 type requestValidator struct {
-	denyListProvider DenyListProvider
-	ipResolver       IPResolver
+	denyListProvider   DenyListProvider
+	ipResolver         IPResolver
+	egressRuleProvider EgressRuleProvider
+}
+
+type RequestValidatorOption func(validator *requestValidator)
+
+// WithEgressRules validates requests without curl options against the egress rules of their project, using the resolved IP addresses of the target
+func WithEgressRules(egressRuleProvider EgressRuleProvider) RequestValidatorOption {
+	return func(validator *requestValidator) {
+		validator.egressRuleProvider = egressRuleProvider
+	}
 }
 
 type RequestValidator interface {
 	Validate(request Request) error
 }
 
-func NewRequestValidator(denyListProvider DenyListProvider, ipResolver IPResolver) RequestValidator {
+func NewRequestValidator(denyListProvider DenyListProvider, ipResolver IPResolver, opts ...RequestValidatorOption) RequestValidator {
 	validator := requestValidator{
 		denyListProvider: denyListProvider,
 		ipResolver:       ipResolver,
 	}
+	for _, opt := range opts {
+		opt(&validator)
+	}
 	return validator
 }
 
@@ -29,16 +43,58 @@ func (c requestValidator) Validate(request Request) error {
 
 	denyList := c.denyListProvider.Get()
 	ipAddresses := c.ipResolver.Resolve(request.URL)
+	if err := validateAgainstDenyList(denyList, "curl command", request.URL, ipAddresses); err != nil {
+		return err
+	}
+	// the proxy is validated as well, since it could otherwise be used to reach denied targets
+	var proxyIPAddresses []string
+	if request.Proxy != "" {
+		proxyIPAddresses = c.ipResolver.Resolve(request.Proxy)
+		if err := validateAgainstDenyList(denyList, "proxy", request.Proxy, proxyIPAddresses); err != nil {
+			return err
+		}
+	}
+	// requests with curl options are rejected by the handler if egress rules apply to their project
+	if c.egressRuleProvider == nil || request.IsCurlRequest() {
+		return nil
+	}
+	rules, err := c.egressRuleProvider.Get()
+	if err != nil {
+		return NewCurlError(fmt.Errorf("could not load egress rules: %w", err), DeniedURLError)
+	}
+	if err := validateEgress(rules, request.project, request.URL, ipAddresses); err != nil {
+		return err
+	}
+	if request.Proxy != "" {
+		return validateEgress(rules, request.project, request.Proxy, proxyIPAddresses)
+	}
+	return nil
+}
 
-	for _, url := range denyList {
-		if strings.Contains(request.URL, url) {
-			return fmt.Errorf("curl command contains denied URL '%s'", url)
+func validateAgainstDenyList(denyList []string, description string, url string, ipAddresses []string) error {
+	for _, deniedURL := range denyList {
+		if strings.Contains(url, deniedURL) {
+			return fmt.Errorf("%s contains denied URL '%s'", description, deniedURL)
 		}
 		for _, ip := range ipAddresses {
-			if strings.Contains(ip, url) {
-				return fmt.Errorf("curl command contains denied IP address '%s'", url)
+			if strings.Contains(ip, deniedURL) {
+				return fmt.Errorf("%s contains denied IP address '%s'", description, deniedURL)
 			}
 		}
 	}
 	return nil
 }
+
+func validateEgress(rules []EgressRule, project string, url string, ipAddresses []string) error {
+	ips := make([]net.IP, 0, len(ipAddresses))
+	for _, address := range ipAddresses {
+		if ip := net.ParseIP(address); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	target, err := NewEgressTarget(project, url, ips)
+	if err != nil {
+		return err
+	}
+	return EvaluateEgressRules(rules, target)
+}
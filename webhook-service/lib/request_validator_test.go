@@ -1,7 +1,9 @@
 package lib_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/keptn/keptn/webhook-service/lib"
@@ -126,6 +128,49 @@ func TestRequestValidator_Validate(t *testing.T) {
 			want:    fmt.Errorf("curl command contains denied IP address '1.1.1.1'"),
 			wantErr: true,
 		},
+		{
+			name: "denied proxy IP",
+			data: lib.Request{
+				Method: "GET",
+				URL:    "http://some-valid-url",
+				Proxy:  "http://my-proxy:3128",
+			},
+			ipResolver: fake.IPResolverMock{
+				ResolveIPAdressesFunc: func(curlURL string) []string {
+					if strings.Contains(curlURL, "my-proxy") {
+						return []string{"10.0.0.5"}
+					}
+					return []string{"1.1.1.1"}
+				},
+			},
+			denyListProvider: fake.DenyListProviderMock{
+				GetDenyListFunc: func() []string {
+					return []string{"10.0.0.5"}
+				},
+			},
+			want:    fmt.Errorf("proxy contains denied IP address '10.0.0.5'"),
+			wantErr: true,
+		},
+		{
+			name: "denied proxy URL",
+			data: lib.Request{
+				Method: "GET",
+				URL:    "http://some-valid-url",
+				Proxy:  "http://10.0.0.5:3128",
+			},
+			ipResolver: fake.IPResolverMock{
+				ResolveIPAdressesFunc: func(curlURL string) []string {
+					return []string{}
+				},
+			},
+			denyListProvider: fake.DenyListProviderMock{
+				GetDenyListFunc: func() []string {
+					return []string{"10.0.0.5"}
+				},
+			},
+			want:    fmt.Errorf("proxy contains denied URL '10.0.0.5'"),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -141,3 +186,58 @@ func TestRequestValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestValidator_ValidateEgressRules(t *testing.T) {
+	rules, err := lib.ParseEgressRules(`
+- name: allow-internal
+  action: allow
+  hosts: ["*.internal.example.com"]
+  projects: [secure-project]
+- name: deny-metadata
+  action: deny
+  cidrs: [169.254.169.254/32]`)
+	require.Nil(t, err)
+
+	validator := lib.NewRequestValidator(
+		fake.DenyListProviderMock{GetDenyListFunc: func() []string { return []string{} }},
+		fake.IPResolverMock{ResolveIPAdressesFunc: func(curlURL string) []string {
+			if strings.Contains(curlURL, "metadata") {
+				return []string{"169.254.169.254"}
+			}
+			return []string{"192.0.2.1"}
+		}},
+		lib.WithEgressRules(&fake.EgressRuleProviderMock{GetFunc: func() ([]lib.EgressRule, error) { return rules, nil }}),
+	)
+
+	require.Nil(t, validator.Validate(lib.Request{URL: "https://api.internal.example.com", Method: "GET"}.WithProject("secure-project")))
+	require.Nil(t, validator.Validate(lib.Request{URL: "https://example.com", Method: "GET"}.WithProject("my-project")))
+
+	err = validator.Validate(lib.Request{URL: "https://example.com", Method: "GET"}.WithProject("secure-project"))
+	require.True(t, lib.IsDeniedURLError(err))
+	require.Contains(t, err.Error(), "'allow-internal'")
+
+	// the rules are evaluated against the resolved IP address of the host
+	err = validator.Validate(lib.Request{URL: "http://metadata.example.com", Method: "GET"}.WithProject("my-project"))
+	require.True(t, lib.IsDeniedURLError(err))
+	require.Contains(t, err.Error(), "'deny-metadata'")
+
+	// the proxy of a request has to be allowed by the egress rules as well
+	err = validator.Validate(lib.Request{URL: "https://api.internal.example.com", Method: "GET", Proxy: "http://10.0.0.5:3128"}.WithProject("secure-project"))
+	require.True(t, lib.IsDeniedURLError(err))
+	require.Contains(t, err.Error(), "'allow-internal'")
+	require.Nil(t, validator.Validate(lib.Request{URL: "https://api.internal.example.com", Method: "GET", Proxy: "http://proxy.internal.example.com:3128"}.WithProject("secure-project")))
+
+	err = validator.Validate(lib.Request{URL: "https://example.com", Method: "GET", Proxy: "http://metadata.example.com"}.WithProject("my-project"))
+	require.True(t, lib.IsDeniedURLError(err))
+	require.Contains(t, err.Error(), "'deny-metadata'")
+
+	// requests with curl options are rejected by the handler if egress rules apply
+	require.Nil(t, validator.Validate(lib.Request{URL: "http://metadata.example.com", Method: "GET", Options: "--insecure"}.WithProject("my-project")))
+
+	failingValidator := lib.NewRequestValidator(
+		fake.DenyListProviderMock{GetDenyListFunc: func() []string { return []string{} }},
+		fake.IPResolverMock{ResolveIPAdressesFunc: func(curlURL string) []string { return []string{"192.0.2.1"} }},
+		lib.WithEgressRules(&fake.EgressRuleProviderMock{GetFunc: func() ([]lib.EgressRule, error) { return nil, errors.New("invalid rules") }}),
+	)
+	require.NotNil(t, failingValidator.Validate(lib.Request{URL: "https://example.com", Method: "GET"}))
+}
@@ -0,0 +1,283 @@
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// reservedOutputNames are the properties of the .finished event that contain the response bodies and the number of attempts of each request
+var reservedOutputNames = []string{"responses", "attempts"}
+
+// Response is the result of an executed request
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// ResponseSpec declares the expected response of a request, and the values that should be extracted from it
+type ResponseSpec struct {
+	// StatusCodes are the expected status codes. If empty, every status code < 400 is accepted
+	StatusCodes []int       `yaml:"statusCodes,omitempty"`
+	Assertions  []Assertion `yaml:"assertions,omitempty"`
+	Outputs     []Output    `yaml:"outputs,omitempty"`
+}
+
+// Assertion checks the value selected by JSONPath (or the whole body, if no JSONPath is set).
+// If Equals is set, the value must be equal to it, if Regex is set, the value must match it.
+// If neither is set, the JSONPath must exist in the response body
+type Assertion struct {
+	JSONPath string `yaml:"jsonPath,omitempty"`
+	Equals   string `yaml:"equals,omitempty"`
+	Regex    string `yaml:"regex,omitempty"`
+}
+
+// Output extracts a value from the response body, either using a JSONPath, or the first capture group of a Regex
+type Output struct {
+	Name     string `yaml:"name"`
+	JSONPath string `yaml:"jsonPath,omitempty"`
+	Regex    string `yaml:"regex,omitempty"`
+}
+
+// AssertionError indicates that a response did not match the expectations of the ResponseSpec
+type AssertionError struct {
+	msg string
+}
+
+func (e *AssertionError) Error() string {
+	return e.msg
+}
+
+func NewAssertionError(format string, args ...interface{}) *AssertionError {
+	return &AssertionError{msg: fmt.Sprintf(format, args...)}
+}
+
+func IsAssertionError(err error) bool {
+	var assertionErr *AssertionError
+	return errors.As(err, &assertionErr)
+}
+
+// Evaluate checks the status code and assertions of the ResponseSpec against the response, and returns the extracted outputs.
+// A nil ResponseSpec accepts every response with a status code < 400
+func (s *ResponseSpec) Evaluate(response Response) (map[string]interface{}, error) {
+	if s == nil {
+		s = &ResponseSpec{}
+	}
+	if err := s.checkStatusCode(response); err != nil {
+		return nil, err
+	}
+
+	var body interface{}
+	bodyParsed := false
+	getBody := func() (interface{}, error) {
+		if !bodyParsed {
+			if err := json.Unmarshal([]byte(response.Body), &body); err != nil {
+				return nil, fmt.Errorf("response body is not valid JSON: %w", err)
+			}
+			bodyParsed = true
+		}
+		return body, nil
+	}
+
+	for _, assertion := range s.Assertions {
+		if err := assertion.check(response.Body, getBody); err != nil {
+			return nil, err
+		}
+	}
+
+	outputs := map[string]interface{}{}
+	for _, output := range s.Outputs {
+		value, err := output.extract(response.Body, getBody)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract output '%s': %w", output.Name, err)
+		}
+		outputs[output.Name] = value
+	}
+	return outputs, nil
+}
+
+// NewResponseTemplateData returns the data of a named request that can be referenced by subsequent requests via {{.responses.<name>}}.
+// It contains the fields of the response body (if it is a JSON object) and the outputs of the request, which take precedence
+func NewResponseTemplateData(response Response, outputs map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{}
+	// bodies that are not JSON objects can only be referenced via outputs
+	_ = json.Unmarshal([]byte(response.Body), &data)
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	for name, value := range outputs {
+		data[name] = value
+	}
+	return data
+}
+
+func (s *ResponseSpec) checkStatusCode(response Response) error {
+	// the status code is not known for requests executed via curl, which fail for status codes >= 400
+	if response.StatusCode == 0 {
+		return nil
+	}
+	if len(s.StatusCodes) == 0 {
+		if response.StatusCode >= http.StatusBadRequest {
+			return &CurlError{err: fmt.Errorf("request failed with status code %d.\nResponse: \n%s", response.StatusCode, response.Body), reason: RequestError}
+		}
+		return nil
+	}
+	for _, statusCode := range s.StatusCodes {
+		if statusCode == response.StatusCode {
+			return nil
+		}
+	}
+	return NewAssertionError("unexpected status code %d, expected one of %v", response.StatusCode, s.StatusCodes)
+}
+
+func (s *ResponseSpec) validate() error {
+	outputNames := map[string]bool{}
+	for _, assertion := range s.Assertions {
+		if err := validateJSONPath(assertion.JSONPath); err != nil {
+			return err
+		}
+		if assertion.Regex != "" {
+			if _, err := regexp.Compile(assertion.Regex); err != nil {
+				return fmt.Errorf("invalid assertion regex '%s'", assertion.Regex)
+			}
+		}
+		if assertion.JSONPath == "" && assertion.Regex == "" && assertion.Equals == "" {
+			return errors.New("assertion must contain jsonPath, equals or regex")
+		}
+	}
+	for _, output := range s.Outputs {
+		if output.Name == "" || containsString(reservedOutputNames, output.Name) {
+			return fmt.Errorf("invalid output name '%s'", output.Name)
+		}
+		if outputNames[output.Name] {
+			return fmt.Errorf("duplicate output name '%s'", output.Name)
+		}
+		outputNames[output.Name] = true
+		if (output.JSONPath == "") == (output.Regex == "") {
+			return fmt.Errorf("output '%s' must contain either jsonPath or regex", output.Name)
+		}
+		if err := validateJSONPath(output.JSONPath); err != nil {
+			return err
+		}
+		if output.Regex != "" {
+			if _, err := regexp.Compile(output.Regex); err != nil {
+				return fmt.Errorf("invalid regex '%s' of output '%s'", output.Regex, output.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (a Assertion) check(rawBody string, getBody func() (interface{}, error)) error {
+	value := rawBody
+	if a.JSONPath != "" {
+		body, err := getBody()
+		if err != nil {
+			return NewAssertionError("assertion on '%s' failed: %s", a.JSONPath, err.Error())
+		}
+		result, err := findJSONPath(body, a.JSONPath)
+		if err != nil {
+			return NewAssertionError("assertion on '%s' failed: %s", a.JSONPath, err.Error())
+		}
+		value = toString(result)
+	}
+	if a.Equals != "" && value != a.Equals {
+		return NewAssertionError("assertion on '%s' failed: expected '%s' but got '%s'", a.target(), a.Equals, value)
+	}
+	if a.Regex != "" {
+		matched, err := regexp.MatchString(a.Regex, value)
+		if err != nil || !matched {
+			return NewAssertionError("assertion on '%s' failed: value does not match '%s'", a.target(), a.Regex)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) target() string {
+	if a.JSONPath == "" {
+		return "response body"
+	}
+	return a.JSONPath
+}
+
+func (o Output) extract(rawBody string, getBody func() (interface{}, error)) (interface{}, error) {
+	if o.JSONPath != "" {
+		body, err := getBody()
+		if err != nil {
+			return nil, err
+		}
+		return findJSONPath(body, o.JSONPath)
+	}
+
+	matches := regexp.MustCompile(o.Regex).FindStringSubmatch(rawBody)
+	if matches == nil {
+		return nil, fmt.Errorf("response body does not match '%s'", o.Regex)
+	}
+	if len(matches) > 1 {
+		return matches[1], nil
+	}
+	return matches[0], nil
+}
+
+func validateJSONPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := parseJSONPath(path); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	template := path
+	if !strings.HasPrefix(template, "{") {
+		template = "{" + template + "}"
+	}
+	jp := jsonpath.New("")
+	if err := jp.Parse(template); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath '%s': %w", path, err)
+	}
+	return jp, nil
+}
+
+// findJSONPath returns the value selected by the path, e.g. $.items[0].name. Paths selecting multiple values return a list
+func findJSONPath(data interface{}, path string) (interface{}, error) {
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(data)
+	if err != nil {
+		return nil, err
+	}
+	values := []interface{}{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s not found", path)
+	}
+	if len(values) == 1 && !strings.Contains(path, "*") && !strings.Contains(path, "..") {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+func toString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
@@ -0,0 +1,139 @@
+package lib_test
+
+import (
+	"testing"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
+)
+
+const responseSpecTestBody = `{"status": "healthy", "score": 95, "checks": [{"name": "db", "ok": true}, {"name": "cache", "ok": false}]}`
+
+func TestResponseSpec_Evaluate(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             *lib.ResponseSpec
+		response         lib.Response
+		want             map[string]interface{}
+		wantAssertionErr bool
+		wantErr          bool
+	}{
+		{
+			name:     "no spec accepts successful response",
+			response: lib.Response{StatusCode: 200, Body: "ok"},
+			want:     map[string]interface{}{},
+		},
+		{
+			name:     "no spec rejects error status code",
+			response: lib.Response{StatusCode: 500, Body: "oops"},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown status code of curl request",
+			spec:     &lib.ResponseSpec{StatusCodes: []int{201}},
+			response: lib.Response{Body: "ok"},
+			want:     map[string]interface{}{},
+		},
+		{
+			name:     "expected error status code",
+			spec:     &lib.ResponseSpec{StatusCodes: []int{404}},
+			response: lib.Response{StatusCode: 404, Body: "not found"},
+			want:     map[string]interface{}{},
+		},
+		{
+			name:             "unexpected status code",
+			spec:             &lib.ResponseSpec{StatusCodes: []int{200, 201}},
+			response:         lib.Response{StatusCode: 202, Body: "accepted"},
+			wantAssertionErr: true,
+		},
+		{
+			name: "assertions pass",
+			spec: &lib.ResponseSpec{Assertions: []lib.Assertion{
+				{JSONPath: "$.status", Equals: "healthy"},
+				{JSONPath: "$.score", Equals: "95"},
+				{JSONPath: "$.checks[0].ok", Equals: "true"},
+				{JSONPath: "$.checks[1].name", Regex: "^ca"},
+				{JSONPath: "$.checks"},
+				{Regex: `"status":\s*"healthy"`},
+			}},
+			response: lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			want:     map[string]interface{}{},
+		},
+		{
+			name:             "equals assertion fails",
+			spec:             &lib.ResponseSpec{Assertions: []lib.Assertion{{JSONPath: "$.checks[1].ok", Equals: "true"}}},
+			response:         lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			wantAssertionErr: true,
+		},
+		{
+			name:             "regex assertion fails",
+			spec:             &lib.ResponseSpec{Assertions: []lib.Assertion{{Regex: "degraded"}}},
+			response:         lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			wantAssertionErr: true,
+		},
+		{
+			name:             "JSONPath does not exist",
+			spec:             &lib.ResponseSpec{Assertions: []lib.Assertion{{JSONPath: "$.version"}}},
+			response:         lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			wantAssertionErr: true,
+		},
+		{
+			name:             "body is not JSON",
+			spec:             &lib.ResponseSpec{Assertions: []lib.Assertion{{JSONPath: "$.status"}}},
+			response:         lib.Response{StatusCode: 200, Body: "healthy"},
+			wantAssertionErr: true,
+		},
+		{
+			name: "outputs",
+			spec: &lib.ResponseSpec{Outputs: []lib.Output{
+				{Name: "status", JSONPath: "$.status"},
+				{Name: "score", JSONPath: "$.score"},
+				{Name: "checkNames", JSONPath: "$.checks[*].name"},
+				{Name: "firstCheck", JSONPath: "$.checks[0]"},
+				{Name: "scoreString", Regex: `"score": (\d+)`},
+				{Name: "statusField", Regex: `"status"`},
+			}},
+			response: lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			want: map[string]interface{}{
+				"status":      "healthy",
+				"score":       float64(95),
+				"checkNames":  []interface{}{"db", "cache"},
+				"firstCheck":  map[string]interface{}{"name": "db", "ok": true},
+				"scoreString": "95",
+				"statusField": `"status"`,
+			},
+		},
+		{
+			name:     "output not found",
+			spec:     &lib.ResponseSpec{Outputs: []lib.Output{{Name: "version", JSONPath: "$.version"}}},
+			response: lib.Response{StatusCode: 200, Body: responseSpecTestBody},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Evaluate(tt.response)
+			if tt.wantErr || tt.wantAssertionErr {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantAssertionErr, lib.IsAssertionError(err))
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewResponseTemplateData(t *testing.T) {
+	data := lib.NewResponseTemplateData(lib.Response{Body: `{"id": "42", "status": "open"}`}, map[string]interface{}{"status": "created"})
+	require.Equal(t, map[string]interface{}{"id": "42", "status": "created"}, data)
+
+	data = lib.NewResponseTemplateData(lib.Response{Body: "created ticket 42"}, map[string]interface{}{"id": "42"})
+	require.Equal(t, map[string]interface{}{"id": "42"}, data)
+
+	data = lib.NewResponseTemplateData(lib.Response{Body: `["42"]`}, nil)
+	require.Equal(t, map[string]interface{}{}, data)
+
+	data = lib.NewResponseTemplateData(lib.Response{Body: `null`}, nil)
+	require.Equal(t, map[string]interface{}{}, data)
+}
@@ -0,0 +1,180 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// network error classes that can be declared as retryable
+const (
+	NetworkErrorTimeout           = "timeout"
+	NetworkErrorConnectionRefused = "connectionRefused"
+	NetworkErrorConnectionReset   = "connectionReset"
+	NetworkErrorDNS               = "dns"
+)
+
+const (
+	// DefaultRetryInitialBackoff is the delay before the first retry, if no initialBackoff is set
+	DefaultRetryInitialBackoff = time.Second
+	// DefaultRetryMaxBackoff is the upper limit of the delay between two attempts, if no maxBackoff is set
+	DefaultRetryMaxBackoff = 30 * time.Second
+	// maxRetryAttempts limits the number of attempts, so a single request can not block the webhook-service indefinitely
+	maxRetryAttempts = 10
+)
+
+// DefaultRetryableStatusCodes are retried if a retry policy does not declare any status codes
+var DefaultRetryableStatusCodes = []int{429, 502, 503, 504}
+
+var networkErrorClasses = []string{NetworkErrorTimeout, NetworkErrorConnectionRefused, NetworkErrorConnectionReset, NetworkErrorDNS}
+
+// RetryPolicy determines how often a request is executed if it fails with a retryable status code or network error.
+// The delay between two attempts starts with InitialBackoff and is doubled after each attempt, up to MaxBackoff
+type RetryPolicy struct {
+	// Attempts is the maximum number of executions of the request, including the first one
+	Attempts       int    `yaml:"attempts"`
+	InitialBackoff string `yaml:"initialBackoff,omitempty"`
+	MaxBackoff     string `yaml:"maxBackoff,omitempty"`
+	// StatusCodes are the retryable status codes. Defaults to DefaultRetryableStatusCodes
+	StatusCodes []int `yaml:"statusCodes,omitempty"`
+	// NetworkErrors are the retryable network error classes (timeout, connectionRefused, connectionReset, dns). Defaults to all classes
+	NetworkErrors []string `yaml:"networkErrors,omitempty"`
+	// IdempotencyKeyHeader is the name of a header that is set to the same unique value for every attempt of the request,
+	// so the target can detect duplicate executions
+	IdempotencyKeyHeader string `yaml:"idempotencyKeyHeader,omitempty"`
+}
+
+// ExecuteWithRetry executes the request using the executor, and retries it according to the retry policy of the request.
+// It returns the response of the last attempt together with the number of attempts
+func ExecuteWithRetry(executor IRequestExecutor, request Request) (*Response, int, error) {
+	policy := request.Retry
+	if policy == nil {
+		response, err := executor.Execute(request)
+		return response, 1, err
+	}
+	if policy.IdempotencyKeyHeader != "" {
+		request.Headers = append(append([]Header{}, request.Headers...), Header{Key: policy.IdempotencyKeyHeader, Value: uuid.New().String()})
+	}
+
+	attempt := 0
+	for {
+		attempt++
+		response, err := executor.Execute(request)
+		if attempt >= policy.GetAttempts() {
+			return response, attempt, err
+		}
+		if err != nil && !policy.IsRetryableError(err) {
+			return response, attempt, err
+		}
+		if err == nil && !policy.IsRetryableStatusCode(response.StatusCode) {
+			return response, attempt, nil
+		}
+		time.Sleep(policy.GetBackoff(attempt))
+	}
+}
+
+// GetAttempts returns the maximum number of executions of the request
+func (p RetryPolicy) GetAttempts() int {
+	if p.Attempts < 1 {
+		return 1
+	}
+	return p.Attempts
+}
+
+// GetBackoff returns the delay after the given attempt
+func (p RetryPolicy) GetBackoff(attempt int) time.Duration {
+	// the durations have been validated when the webhook config was decoded
+	backoff, _ := parsePositiveDuration(p.InitialBackoff, DefaultRetryInitialBackoff, "retry initial backoff")
+	maxBackoff, _ := parsePositiveDuration(p.MaxBackoff, DefaultRetryMaxBackoff, "retry max backoff")
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff = backoff * 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// IsRetryableStatusCode returns true if a response with the given status code should be retried
+func (p RetryPolicy) IsRetryableStatusCode(statusCode int) bool {
+	statusCodes := p.StatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = DefaultRetryableStatusCodes
+	}
+	for _, code := range statusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryableError returns true if the error is a network error of a retryable class.
+// Invalid or denied requests are never retried
+func (p RetryPolicy) IsRetryableError(err error) bool {
+	if !IsRequestError(err) {
+		return false
+	}
+	class := GetNetworkErrorClass(err)
+	if class == "" {
+		return false
+	}
+	networkErrors := p.NetworkErrors
+	if len(networkErrors) == 0 {
+		networkErrors = networkErrorClasses
+	}
+	return containsString(networkErrors, class)
+}
+
+// GetNetworkErrorClass returns the class of a network error, or an empty string if the error is not a known network error
+func GetNetworkErrorClass(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return NetworkErrorDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return NetworkErrorConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return NetworkErrorConnectionReset
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return NetworkErrorTimeout
+	}
+	return ""
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (p RetryPolicy) validate() error {
+	if p.Attempts < 1 || p.Attempts > maxRetryAttempts {
+		return fmt.Errorf("retry attempts must be between 1 and %d", maxRetryAttempts)
+	}
+	if _, err := parsePositiveDuration(p.InitialBackoff, DefaultRetryInitialBackoff, "retry initial backoff"); err != nil {
+		return err
+	}
+	if _, err := parsePositiveDuration(p.MaxBackoff, DefaultRetryMaxBackoff, "retry max backoff"); err != nil {
+		return err
+	}
+	for _, class := range p.NetworkErrors {
+		if !containsString(networkErrorClasses, class) {
+			return fmt.Errorf("unknown retryable network error '%s'", class)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package lib_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/keptn/keptn/webhook-service/lib/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWithRetry(t *testing.T) {
+	refusedErr := lib.NewCurlError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}, lib.RequestError)
+
+	tests := []struct {
+		name         string
+		retry        *lib.RetryPolicy
+		results      []*lib.Response
+		errs         []error
+		wantAttempts int
+		wantStatus   int
+		wantErr      bool
+	}{
+		{
+			name:         "no retry policy",
+			results:      []*lib.Response{{StatusCode: 503}},
+			wantAttempts: 1,
+			wantStatus:   503,
+		},
+		{
+			name:         "retryable status code succeeds eventually",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms"},
+			results:      []*lib.Response{{StatusCode: 503}, {StatusCode: 502}, {StatusCode: 200}},
+			wantAttempts: 3,
+			wantStatus:   200,
+		},
+		{
+			name:         "attempts exhausted",
+			retry:        &lib.RetryPolicy{Attempts: 2, InitialBackoff: "1ms"},
+			results:      []*lib.Response{{StatusCode: 503}, {StatusCode: 503}, {StatusCode: 200}},
+			wantAttempts: 2,
+			wantStatus:   503,
+		},
+		{
+			name:         "status code is not retryable",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms"},
+			results:      []*lib.Response{{StatusCode: 500}, {StatusCode: 200}},
+			wantAttempts: 1,
+			wantStatus:   500,
+		},
+		{
+			name:         "custom retryable status codes",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms", StatusCodes: []int{500}},
+			results:      []*lib.Response{{StatusCode: 500}, {StatusCode: 200}},
+			wantAttempts: 2,
+			wantStatus:   200,
+		},
+		{
+			name:         "denied URL is not retried",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms"},
+			results:      []*lib.Response{nil},
+			errs:         []error{lib.NewCurlError(errors.New("denied"), lib.DeniedURLError)},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "network error is not declared as retryable",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms", NetworkErrors: []string{lib.NetworkErrorTimeout}},
+			results:      []*lib.Response{nil},
+			errs:         []error{lib.NewCurlError(&net.DNSError{Err: "no such host", Name: "my-webhook"}, lib.RequestError)},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "retryable network error",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms"},
+			results:      []*lib.Response{nil, {StatusCode: 200}},
+			errs:         []error{lib.NewCurlError(&net.DNSError{Err: "server misbehaving", Name: "my-webhook", IsTemporary: true}, lib.RequestError), nil},
+			wantAttempts: 2,
+			wantStatus:   200,
+		},
+		{
+			name:         "unknown network error",
+			retry:        &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms"},
+			results:      []*lib.Response{nil},
+			errs:         []error{refusedErr},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &fake.IRequestExecutorMock{}
+			executor.ExecuteFunc = func(request lib.Request) (*lib.Response, error) {
+				i := len(executor.ExecuteCalls()) - 1
+				var err error
+				if i < len(tt.errs) {
+					err = tt.errs[i]
+				}
+				return tt.results[i], err
+			}
+			response, attempts, err := lib.ExecuteWithRetry(executor, lib.Request{URL: "http://my-webhook", Method: "GET", Retry: tt.retry})
+			require.Equal(t, tt.wantAttempts, attempts)
+			require.Len(t, executor.ExecuteCalls(), tt.wantAttempts)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantStatus, response.StatusCode)
+		})
+	}
+}
+
+func TestExecuteWithRetry_IdempotencyKey(t *testing.T) {
+	executor := &fake.IRequestExecutorMock{}
+	executor.ExecuteFunc = func(request lib.Request) (*lib.Response, error) {
+		if len(executor.ExecuteCalls()) < 3 {
+			return &lib.Response{StatusCode: http.StatusTooManyRequests}, nil
+		}
+		return &lib.Response{StatusCode: http.StatusOK}, nil
+	}
+	request := lib.Request{
+		URL:     "http://my-webhook",
+		Method:  "POST",
+		Headers: []lib.Header{{Key: "x-token", Value: "my-token"}},
+		Retry:   &lib.RetryPolicy{Attempts: 3, InitialBackoff: "1ms", IdempotencyKeyHeader: "Idempotency-Key"},
+	}
+	_, attempts, err := lib.ExecuteWithRetry(executor, request)
+	require.Nil(t, err)
+	require.Equal(t, 3, attempts)
+
+	// every attempt carries the same key
+	key := executor.ExecuteCalls()[0].Request.Headers[1]
+	require.Equal(t, "Idempotency-Key", key.Key)
+	require.NotEmpty(t, key.Value)
+	for _, call := range executor.ExecuteCalls() {
+		require.Equal(t, []lib.Header{{Key: "x-token", Value: "my-token"}, key}, call.Request.Headers)
+	}
+	// the headers of the original request are not modified
+	require.Len(t, request.Headers, 1)
+}
+
+func TestRetryPolicy_GetBackoff(t *testing.T) {
+	policy := lib.RetryPolicy{Attempts: 5, InitialBackoff: "100ms", MaxBackoff: "300ms"}
+	require.Equal(t, 100*time.Millisecond, policy.GetBackoff(1))
+	require.Equal(t, 200*time.Millisecond, policy.GetBackoff(2))
+	require.Equal(t, 300*time.Millisecond, policy.GetBackoff(3))
+	require.Equal(t, 300*time.Millisecond, policy.GetBackoff(4))
+
+	require.Equal(t, lib.DefaultRetryInitialBackoff, lib.RetryPolicy{Attempts: 2}.GetBackoff(1))
+}
+
+func TestGetNetworkErrorClass(t *testing.T) {
+	// a port that has just been closed refuses connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	address := listener.Addr().String()
+	require.Nil(t, listener.Close())
+
+	executor := lib.NewHTTPRequestExecutor()
+	_, err = executor.Execute(lib.Request{URL: "http://" + address, Method: "GET"})
+	require.NotNil(t, err)
+	require.Equal(t, lib.NetworkErrorConnectionRefused, lib.GetNetworkErrorClass(err))
+
+	require.Equal(t, lib.NetworkErrorDNS, lib.GetNetworkErrorClass(&net.DNSError{Err: "no such host"}))
+	require.Equal(t, lib.NetworkErrorTimeout, lib.GetNetworkErrorClass(fmt.Errorf("request failed: %w", context.DeadlineExceeded)))
+	require.Equal(t, "", lib.GetNetworkErrorClass(errors.New("oops")))
+}
@@ -3,21 +3,90 @@ package lib
 import (
 	"context"
 	"errors"
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// supported secret backends
+const (
+	SecretBackendKubernetes = "kubernetes"
+	SecretBackendFile       = "file"
+	SecretBackendEnv        = "env"
+	SecretBackendVault      = "vault"
+)
+
+// ErrSecretNotFound is returned if a secret or one of its keys does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
 //go:generate moq  -pkg fake -out ./fake/secret_reader_mock.go . ISecretReader
 type ISecretReader interface {
 	ReadSecret(name, key string) (string, error)
 }
 
+// SecretReaderConfig selects and configures the backend the secrets referenced by webhooks are read from
+type SecretReaderConfig struct {
+	// Backend is one of kubernetes (default), file, env or vault
+	Backend string
+	// RequireManagedBy only allows reading Kubernetes secrets that are managed by Keptn's secret-service. Only used by the kubernetes backend
+	RequireManagedBy bool
+	// FilePath is a directory containing a file per secret key, or a YAML file mapping secret names to keys and values
+	FilePath string
+	// EnvPrefix is the prefix of the environment variables containing the secrets
+	EnvPrefix string
+	// VaultAddress, VaultToken, VaultMountPath and VaultNamespace configure the access to a Vault compatible KV v2 secrets engine
+	VaultAddress   string
+	VaultToken     string
+	VaultMountPath string
+	VaultNamespace string
+}
+
+// NewSecretReader creates the ISecretReader for the backend of the given config
+func NewSecretReader(config SecretReaderConfig, k8sClient kubernetes.Interface) (ISecretReader, error) {
+	switch config.Backend {
+	case "", SecretBackendKubernetes:
+		return NewK8sSecretReader(k8sClient, WithManagedByCheck(config.RequireManagedBy)), nil
+	case SecretBackendFile:
+		if config.FilePath == "" {
+			return nil, errors.New("the file secret backend requires a file path")
+		}
+		return NewFileSecretReader(config.FilePath), nil
+	case SecretBackendEnv:
+		return NewEnvSecretReader(config.EnvPrefix), nil
+	case SecretBackendVault:
+		if config.VaultAddress == "" {
+			return nil, errors.New("the vault secret backend requires an address")
+		}
+		return NewVaultSecretReader(config.VaultAddress, config.VaultToken,
+			WithVaultMountPath(config.VaultMountPath),
+			WithVaultNamespace(config.VaultNamespace),
+		), nil
+	}
+	return nil, fmt.Errorf("unknown secret backend '%s'", config.Backend)
+}
+
 type K8sSecretReater struct {
-	k8sClient kubernetes.Interface
+	k8sClient        kubernetes.Interface
+	requireManagedBy bool
 }
 
-func NewK8sSecretReader(k8sClient kubernetes.Interface) *K8sSecretReater {
-	return &K8sSecretReater{k8sClient: k8sClient}
+// K8sSecretReaderOption configures the K8sSecretReater
+type K8sSecretReaderOption func(reader *K8sSecretReater)
+
+// WithManagedByCheck determines whether only secrets managed by Keptn's secret-service can be read. Enabled by default
+func WithManagedByCheck(enabled bool) K8sSecretReaderOption {
+	return func(reader *K8sSecretReater) {
+		reader.requireManagedBy = enabled
+	}
+}
+
+func NewK8sSecretReader(k8sClient kubernetes.Interface, opts ...K8sSecretReaderOption) *K8sSecretReater {
+	reader := &K8sSecretReater{k8sClient: k8sClient, requireManagedBy: true}
+	for _, opt := range opts {
+		opt(reader)
+	}
+	return reader
 }
 
 func (sr *K8sSecretReater) ReadSecret(name, key string) (string, error) {
@@ -26,7 +95,7 @@ func (sr *K8sSecretReater) ReadSecret(name, key string) (string, error) {
 		return "", err
 	}
 	// only allow reading from secrets that are managed by Keptn's secret-service
-	if secret.Labels["app.kubernetes.io/managed-by"] != "keptn-secret-service" {
+	if sr.requireManagedBy && secret.Labels["app.kubernetes.io/managed-by"] != "keptn-secret-service" {
 		return "", errors.New("only secrets managed by Keptn's secret-service can be referenced")
 	}
 	return string(secret.Data[key]), nil
@@ -0,0 +1,47 @@
+package lib
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultSecretEnvPrefix is the prefix of the environment variables read by the EnvSecretReader, if no prefix is set
+const DefaultSecretEnvPrefix = "WEBHOOK_SECRET_"
+
+// EnvSecretReader reads secrets from environment variables named <prefix><NAME>_<KEY>, where all characters of the secret name and key
+// that are not letters or digits are replaced by underscores, e.g. the key 'token' of the secret 'my-secret' is read from WEBHOOK_SECRET_MY_SECRET_TOKEN
+type EnvSecretReader struct {
+	prefix string
+}
+
+func NewEnvSecretReader(prefix string) *EnvSecretReader {
+	if prefix == "" {
+		prefix = DefaultSecretEnvPrefix
+	}
+	return &EnvSecretReader{prefix: prefix}
+}
+
+func (sr *EnvSecretReader) ReadSecret(name, key string) (string, error) {
+	value, ok := os.LookupEnv(sr.EnvVarName(name, key))
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// EnvVarName returns the name of the environment variable containing the given key of a secret
+func (sr *EnvSecretReader) EnvVarName(name, key string) string {
+	return sr.prefix + toEnvVarName(name) + "_" + toEnvVarName(key)
+}
+
+func toEnvVarName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileSecretReader reads secrets from the local file system. The path can either be a directory containing a sub directory per secret
+// with a file per key, i.e. <path>/<name>/<key> like a mounted Kubernetes secret, or a YAML file mapping secret names to their keys and values
+type FileSecretReader struct {
+	path string
+}
+
+func NewFileSecretReader(path string) *FileSecretReader {
+	return &FileSecretReader{path: path}
+}
+
+func (sr *FileSecretReader) ReadSecret(name, key string) (string, error) {
+	if !isValidSecretPathElement(name) || !isValidSecretPathElement(key) {
+		return "", fmt.Errorf("invalid secret reference %s.%s", name, key)
+	}
+	info, err := os.Stat(sr.path)
+	if err != nil {
+		return "", fmt.Errorf("could not access secret path: %w", err)
+	}
+	if info.IsDir() {
+		return sr.readFromDirectory(name, key)
+	}
+	return sr.readFromFile(name, key)
+}
+
+func (sr *FileSecretReader) readFromDirectory(name, key string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(sr.path, name, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (sr *FileSecretReader) readFromFile(name, key string) (string, error) {
+	content, err := os.ReadFile(sr.path)
+	if err != nil {
+		return "", err
+	}
+	secrets := map[string]map[string]string{}
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return "", fmt.Errorf("could not parse secret file: %w", err)
+	}
+	value, ok := secrets[name][key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// isValidSecretPathElement prevents secret references from escaping the configured directory
+func isValidSecretPathElement(element string) bool {
+	return element != "" && element != "." && element != ".." && !strings.ContainsAny(element, `/\`)
+}
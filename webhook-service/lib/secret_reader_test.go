@@ -1,13 +1,17 @@
 package lib_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
 	"github.com/keptn/keptn/webhook-service/lib"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
-	"os"
-	"testing"
 )
 
 func TestK8sSecretReater_ReadSecret(t *testing.T) {
@@ -41,6 +45,139 @@ func TestK8sSecretReater_ReadSecretWithInvalidScope(t *testing.T) {
 	require.Equal(t, "", secret)
 }
 
+func TestK8sSecretReater_ReadSecretWithoutManagedByCheck(t *testing.T) {
+	_ = os.Setenv("POD_NAMESPACE", "keptn")
+	secretReader := lib.NewK8sSecretReader(fake.NewSimpleClientset(
+		getK8sSecret(map[string]string{}),
+	), lib.WithManagedByCheck(false))
+
+	secret, err := secretReader.ReadSecret("my-secret", "foo")
+
+	require.Nil(t, err)
+	require.Equal(t, "bar", secret)
+}
+
+func TestFileSecretReader_ReadSecretFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "my-secret"), 0700))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "my-secret", "foo"), []byte("bar"), 0600))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "outside"), []byte("baz"), 0600))
+
+	secretReader := lib.NewFileSecretReader(dir)
+
+	secret, err := secretReader.ReadSecret("my-secret", "foo")
+	require.Nil(t, err)
+	require.Equal(t, "bar", secret)
+
+	_, err = secretReader.ReadSecret("my-secret", "missing")
+	require.ErrorIs(t, err, lib.ErrSecretNotFound)
+
+	_, err = secretReader.ReadSecret("my-secret", "../outside")
+	require.NotNil(t, err)
+	_, err = secretReader.ReadSecret("..", "outside")
+	require.NotNil(t, err)
+}
+
+func TestFileSecretReader_ReadSecretFromFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secrets.yaml")
+	require.Nil(t, os.WriteFile(file, []byte("my-secret:\n  foo: bar\n"), 0600))
+
+	secretReader := lib.NewFileSecretReader(file)
+
+	secret, err := secretReader.ReadSecret("my-secret", "foo")
+	require.Nil(t, err)
+	require.Equal(t, "bar", secret)
+
+	_, err = secretReader.ReadSecret("my-missing-secret", "foo")
+	require.ErrorIs(t, err, lib.ErrSecretNotFound)
+
+	_, err = lib.NewFileSecretReader(filepath.Join(t.TempDir(), "missing.yaml")).ReadSecret("my-secret", "foo")
+	require.NotNil(t, err)
+}
+
+func TestEnvSecretReader_ReadSecret(t *testing.T) {
+	t.Setenv("WEBHOOK_SECRET_MY_SECRET_FOO", "bar")
+	t.Setenv("CUSTOM_MY_SECRET_API_TOKEN", "baz")
+
+	secret, err := lib.NewEnvSecretReader("").ReadSecret("my-secret", "foo")
+	require.Nil(t, err)
+	require.Equal(t, "bar", secret)
+
+	secret, err = lib.NewEnvSecretReader("CUSTOM_").ReadSecret("my-secret", "api.token")
+	require.Nil(t, err)
+	require.Equal(t, "baz", secret)
+
+	_, err = lib.NewEnvSecretReader("").ReadSecret("my-secret", "missing")
+	require.ErrorIs(t, err, lib.ErrSecretNotFound)
+}
+
+func TestVaultSecretReader_ReadSecret(t *testing.T) {
+	var receivedNamespace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedNamespace = r.Header.Get("X-Vault-Namespace")
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/keptn/data/my-secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"foo": "bar", "count": 3}, "metadata": {"version": 1}}}`))
+	}))
+	defer server.Close()
+
+	secretReader := lib.NewVaultSecretReader(server.URL, "my-token", lib.WithVaultMountPath("/keptn/"), lib.WithVaultNamespace("team-a"))
+
+	secret, err := secretReader.ReadSecret("my-secret", "foo")
+	require.Nil(t, err)
+	require.Equal(t, "bar", secret)
+	require.Equal(t, "team-a", receivedNamespace)
+
+	secret, err = secretReader.ReadSecret("my-secret", "count")
+	require.Nil(t, err)
+	require.Equal(t, "3", secret)
+
+	_, err = secretReader.ReadSecret("my-secret", "missing")
+	require.ErrorIs(t, err, lib.ErrSecretNotFound)
+
+	_, err = secretReader.ReadSecret("my-missing-secret", "foo")
+	require.ErrorIs(t, err, lib.ErrSecretNotFound)
+
+	_, err = lib.NewVaultSecretReader(server.URL, "wrong-token", lib.WithVaultMountPath("keptn")).ReadSecret("my-secret", "foo")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "status code 403")
+}
+
+func TestNewSecretReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  lib.SecretReaderConfig
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "default", config: lib.SecretReaderConfig{}, want: &lib.K8sSecretReater{}},
+		{name: "kubernetes", config: lib.SecretReaderConfig{Backend: lib.SecretBackendKubernetes, RequireManagedBy: true}, want: &lib.K8sSecretReater{}},
+		{name: "file", config: lib.SecretReaderConfig{Backend: lib.SecretBackendFile, FilePath: "/secrets"}, want: &lib.FileSecretReader{}},
+		{name: "file without path", config: lib.SecretReaderConfig{Backend: lib.SecretBackendFile}, wantErr: true},
+		{name: "env", config: lib.SecretReaderConfig{Backend: lib.SecretBackendEnv}, want: &lib.EnvSecretReader{}},
+		{name: "vault", config: lib.SecretReaderConfig{Backend: lib.SecretBackendVault, VaultAddress: "http://vault:8200"}, want: &lib.VaultSecretReader{}},
+		{name: "vault without address", config: lib.SecretReaderConfig{Backend: lib.SecretBackendVault}, wantErr: true},
+		{name: "unknown", config: lib.SecretReaderConfig{Backend: "unknown"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lib.NewSecretReader(tt.config, fake.NewSimpleClientset())
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.IsType(t, tt.want, got)
+		})
+	}
+}
+
 func getK8sSecret(labels map[string]string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
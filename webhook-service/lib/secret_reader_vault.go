@@ -0,0 +1,114 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultVaultMountPath is the mount path of the KV v2 secrets engine, if no mount path is set
+	DefaultVaultMountPath = "secret"
+	vaultRequestTimeout   = 10 * time.Second
+)
+
+// VaultSecretReader reads secrets from a HashiCorp Vault compatible KV v2 secrets engine, i.e. the key of a secret is read from
+// the data of GET <address>/v1/<mount>/data/<name>
+type VaultSecretReader struct {
+	address    string
+	token      string
+	mountPath  string
+	namespace  string
+	httpClient *http.Client
+}
+
+// VaultSecretReaderOption configures the VaultSecretReader
+type VaultSecretReaderOption func(reader *VaultSecretReader)
+
+// WithVaultMountPath sets the mount path of the KV v2 secrets engine
+func WithVaultMountPath(mountPath string) VaultSecretReaderOption {
+	return func(reader *VaultSecretReader) {
+		if mountPath != "" {
+			reader.mountPath = strings.Trim(mountPath, "/")
+		}
+	}
+}
+
+// WithVaultNamespace sets the Vault namespace of the secrets
+func WithVaultNamespace(namespace string) VaultSecretReaderOption {
+	return func(reader *VaultSecretReader) {
+		reader.namespace = namespace
+	}
+}
+
+// WithVaultHTTPClient sets the http.Client used to access Vault
+func WithVaultHTTPClient(httpClient *http.Client) VaultSecretReaderOption {
+	return func(reader *VaultSecretReader) {
+		reader.httpClient = httpClient
+	}
+}
+
+func NewVaultSecretReader(address string, token string, opts ...VaultSecretReaderOption) *VaultSecretReader {
+	reader := &VaultSecretReader{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		mountPath:  DefaultVaultMountPath,
+		httpClient: &http.Client{Timeout: vaultRequestTimeout},
+	}
+	for _, opt := range opts {
+		opt(reader)
+	}
+	return reader
+}
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+func (sr *VaultSecretReader) ReadSecret(name, key string) (string, error) {
+	if !isValidSecretPathElement(name) {
+		return "", fmt.Errorf("invalid secret reference %s.%s", name, key)
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/data/%s", sr.address, sr.mountPath, url.PathEscape(name)), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", sr.token)
+	if sr.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", sr.namespace)
+	}
+	resp, err := sr.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not read secret from vault: status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	secret := &vaultSecretResponse{}
+	if err := json.Unmarshal(body, secret); err != nil {
+		return "", fmt.Errorf("could not parse secret from vault: %w", err)
+	}
+	value, ok := secret.Data.Data[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprintf("%v", value), nil
+}
@@ -12,8 +12,10 @@ type ITemplateEngine interface {
 
 type TemplateEngine struct{}
 
+// ParseTemplate renders the template using the data. Besides the builtin functions of text/template, the functions
+// toJson, quote, default, upper, lower, b64enc, date, hasKey and get are available
 func (t *TemplateEngine) ParseTemplate(data interface{}, templateStr string) (string, error) {
-	tmpl, err := template.New("").Option("missingkey=error").Parse(templateStr)
+	tmpl, err := template.New("").Option("missingkey=error").Funcs(templateFuncs).Parse(templateStr)
 	if err != nil {
 		return "", err
 	}
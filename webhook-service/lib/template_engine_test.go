@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/keptn/keptn/webhook-service/lib"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTemplateEngine_ParseTemplate(t1 *testing.T) {
@@ -93,3 +94,124 @@ func TestTemplateEngine_ParseTemplate(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTemplateEngine_ParseTemplateFunctions(t *testing.T) {
+	data := map[string]interface{}{
+		"time": "2022-02-07T11:15:46.123Z",
+		"data": map[string]interface{}{
+			"project": "my-project",
+			"message": "line 1\n\"quoted\"",
+			"labels": map[string]interface{}{
+				"owner": "team-a",
+			},
+			"deployment": map[string]interface{}{
+				"deploymentURIsPublic": []interface{}{"http://my-service:8080"},
+			},
+			"empty": "",
+		},
+	}
+	tests := []struct {
+		name        string
+		templateStr string
+		want        string
+		wantErr     string
+	}{
+		{
+			name:        "toJson",
+			templateStr: `{"labels": {{ toJson .data.labels }}}`,
+			want:        `{"labels": {"owner":"team-a"}}`,
+		},
+		{
+			name:        "quote escapes special characters",
+			templateStr: `{"text": {{ quote .data.message }}}`,
+			want:        `{"text": "line 1\n\"quoted\""}`,
+		},
+		{
+			name:        "default for empty value",
+			templateStr: `{{ default "none" .data.empty }}`,
+			want:        "none",
+		},
+		{
+			name:        "default for missing key",
+			templateStr: `{{ default "latest" (get .data "configurationChange" "tag") }}`,
+			want:        "latest",
+		},
+		{
+			name:        "default for existing value",
+			templateStr: `{{ default "none" .data.project }}`,
+			want:        "my-project",
+		},
+		{
+			name:        "upper and lower",
+			templateStr: `{{ upper .data.project }} {{ lower "MY-STAGE" }}`,
+			want:        "MY-PROJECT my-stage",
+		},
+		{
+			name:        "b64enc",
+			templateStr: `{{ b64enc "user:password" }}`,
+			want:        "dXNlcjpwYXNzd29yZA==",
+		},
+		{
+			name:        "date",
+			templateStr: `{{ date "2006-01-02 15:04" .time }}`,
+			want:        "2022-02-07 11:15",
+		},
+		{
+			name:        "date with invalid timestamp",
+			templateStr: `{{ date "2006-01-02" .data.project }}`,
+			wantErr:     "could not parse 'my-project' as RFC3339 timestamp",
+		},
+		{
+			name:        "hasKey",
+			templateStr: `{{ if hasKey .data "deployment" }}deployed{{ end }}{{ if hasKey .data "evaluation" }}evaluated{{ end }}`,
+			want:        "deployed",
+		},
+		{
+			name:        "index on nested data",
+			templateStr: `{{ index .data "deployment" "deploymentURIsPublic" 0 }}`,
+			want:        "http://my-service:8080",
+		},
+		{
+			name:        "index out of range",
+			templateStr: `{{ index .data "deployment" "deploymentURIsPublic" 1 }}`,
+			wantErr:     "index out of range",
+		},
+		{
+			name:        "get on nested data",
+			templateStr: `{{ get .data "deployment" "deploymentURIsPublic" 0 }}`,
+			want:        "http://my-service:8080",
+		},
+		{
+			name:        "get out of range",
+			templateStr: `{{ default "none" (get .data "deployment" "deploymentURIsPublic" 1) }}`,
+			want:        "none",
+		},
+		{
+			name:        "get for missing key",
+			templateStr: `{{ default "none" (get .data "evaluation" "score") }}`,
+			want:        "none",
+		},
+		{
+			name:        "functions reading env vars are not available",
+			templateStr: `{{ env "HOME" }}`,
+			wantErr:     `function "env" not defined`,
+		},
+		{
+			name:        "functions reading files are not available",
+			templateStr: `{{ readFile "/etc/passwd" }}`,
+			wantErr:     `function "readFile" not defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&lib.TemplateEngine{}).ParseTemplate(data, tt.templateStr)
+			if tt.wantErr != "" {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,6 +3,9 @@ package lib
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"gopkg.in/yaml.v3"
@@ -31,6 +34,30 @@ type Webhook struct {
 	SendStarted    *bool         `yaml:"sendStarted,omitempty"`
 	EnvFrom        []EnvFrom     `yaml:"envFrom"`
 	Requests       []interface{} `yaml:"requests"`
+	// Async defers the .finished event until the job started by the requests has been completed
+	Async *AsyncSpec `yaml:"async,omitempty"`
+}
+
+// AsyncSpec determines how the completion of an asynchronous job is detected. Either Poll or Callback must be set
+type AsyncSpec struct {
+	// Timeout is the maximum duration of the job, e.g. 1h
+	Timeout  string        `yaml:"timeout,omitempty"`
+	Poll     *PollSpec     `yaml:"poll,omitempty"`
+	Callback *CallbackSpec `yaml:"callback,omitempty"`
+}
+
+// PollSpec periodically executes the Request until the response satisfies all Until assertions.
+// The Response of the Request is then evaluated to determine the result of the task
+type PollSpec struct {
+	Interval string      `yaml:"interval,omitempty"`
+	Request  Request     `yaml:"request"`
+	Until    []Assertion `yaml:"until"`
+}
+
+// CallbackSpec waits for the job to call the URL provided via {{.callback.url}}.
+// The body of the callback is evaluated using the Response to determine the result of the task
+type CallbackSpec struct {
+	Response *ResponseSpec `yaml:"response,omitempty"`
 }
 
 type EnvFrom struct {
@@ -39,11 +66,72 @@ type EnvFrom struct {
 }
 
 type Request struct {
+	// Name allows subsequent requests of the webhook to reference the response, e.g. {{.responses.<name>.id}}
+	Name    string   `yaml:"name,omitempty"`
 	URL     string   `yaml:"url"`
 	Method  string   `yaml:"method"`
 	Headers []Header `yaml:"headers,omitempty"`
 	Payload string   `yaml:"payload,omitempty"`
 	Options string   `yaml:"options,omitempty"`
+	// Timeout is the duration after which the request is canceled, e.g. 10s
+	Timeout string      `yaml:"timeout,omitempty"`
+	Proxy   string      `yaml:"proxy,omitempty"`
+	TLS     *TLSOptions `yaml:"tls,omitempty"`
+	// Response contains the assertions and outputs of the request
+	Response *ResponseSpec `yaml:"response,omitempty"`
+	// Retry executes the request again if it fails with a retryable status code or network error
+	Retry *RetryPolicy `yaml:"retry,omitempty"`
+	// Signature signs the payload of the request using a secret of the webhook
+	Signature *SignatureSpec `yaml:"signature,omitempty"`
+
+	// project is the project of the webhook, which determines the egress rules that apply to the request
+	project string
+}
+
+// TLSOptions configures the verification of the server certificate of a request
+type TLSOptions struct {
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+	// CACert is a PEM encoded certificate that is used instead of the system certificate pool
+	CACert     string `yaml:"caCert,omitempty"`
+	ServerName string `yaml:"serverName,omitempty"`
+	// ClientCert references the secret containing the client certificate for mutual TLS
+	ClientCert *ClientCertRef `yaml:"clientCert,omitempty"`
+
+	// clientCertPEM and clientKeyPEM are read from the ClientCert secret right before the request is executed
+	clientCertPEM string
+	clientKeyPEM  string
+}
+
+// ClientCertRef references the PEM encoded certificate and private key in a secret managed by the secret-service
+type ClientCertRef struct {
+	SecretName string `yaml:"secretName"`
+	// CertKey is the key of the certificate within the secret, tls.crt by default
+	CertKey string `yaml:"certKey,omitempty"`
+	// KeyKey is the key of the private key within the secret, tls.key by default
+	KeyKey string `yaml:"keyKey,omitempty"`
+}
+
+// GetCertKey returns the key of the certificate within the secret
+func (c ClientCertRef) GetCertKey() string {
+	if c.CertKey == "" {
+		return "tls.crt"
+	}
+	return c.CertKey
+}
+
+// GetKeyKey returns the key of the private key within the secret
+func (c ClientCertRef) GetKeyKey() string {
+	if c.KeyKey == "" {
+		return "tls.key"
+	}
+	return c.KeyKey
+}
+
+// WithClientCertificate returns a copy of the TLSOptions that presents the given PEM encoded certificate and key to the server
+func (o TLSOptions) WithClientCertificate(certPEM string, keyPEM string) *TLSOptions {
+	o.clientCertPEM = certPEM
+	o.clientKeyPEM = keyPEM
+	return &o
 }
 
 type Header struct {
@@ -57,10 +145,20 @@ type WebHookSecretRef struct {
 }
 
 const webhookConfInvalid = "Webhook configuration invalid: "
+
+const (
+	// DefaultAsyncTimeout is used for asynchronous webhooks that do not specify a timeout
+	DefaultAsyncTimeout = time.Hour
+	// DefaultPollInterval is used for polling webhooks that do not specify an interval
+	DefaultPollInterval = 30 * time.Second
+)
 const betaApiVersion = "webhookconfig.keptn.sh/v1beta1"
 
 var supportedCurlMethods = [4]string{"POST", "PUT", "GET", "HEAD"}
 
+// requestNameRegex ensures that request names can be used in templates, e.g. {{.responses.createTicket.id}}
+var requestNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // DecodeWebHookConfigYAML takes a webhook config string formatted as YAML and decodes it to
 // Shipyard value
 func DecodeWebHookConfigYAML(webhookConfigYaml []byte) (*WebHookConfig, error) {
@@ -94,22 +192,66 @@ func DecodeWebHookConfigYAML(webhookConfigYaml []byte) (*WebHookConfig, error) {
 		}
 	}
 
+	for _, webhook := range webHookConfig.Spec.Webhooks {
+		if webhook.Async == nil {
+			continue
+		}
+		if webHookConfig.ApiVersion != betaApiVersion {
+			return nil, errors.New(webhookConfInvalid + "asynchronous webhooks require API version " + betaApiVersion)
+		}
+		if !webhook.ShouldSendFinishedEvent() {
+			return nil, errors.New(webhookConfInvalid + "asynchronous webhooks require 'sendFinished' to be set")
+		}
+		if err := verifyAsyncSpec(*webhook.Async); err != nil {
+			return nil, err
+		}
+	}
+
 	return webHookConfig, nil
 }
 
 func normalizeBeta1Requests(webhooks []Webhook) error {
 	for i, webhook := range webhooks {
+		requestNames := map[string]bool{}
+		// the outputs of all requests are added to the same finished event, so their names must be unique within the webhook
+		outputNames := map[string]bool{}
 		for j, request := range webhook.Requests {
 			convertedRequest := ConvertToRequest(request)
 			if err := verifyBeta1Request(convertedRequest); err != nil {
 				return err
 			}
+			if convertedRequest.Signature != nil && !webhook.hasEnv(convertedRequest.Signature.SecretEnv) {
+				return fmt.Errorf(webhookConfInvalid+"signature secret '%s' is not declared in envFrom", convertedRequest.Signature.SecretEnv)
+			}
+			if convertedRequest.Name != "" {
+				if requestNames[convertedRequest.Name] {
+					return fmt.Errorf(webhookConfInvalid+"duplicate webhook request name '%s'", convertedRequest.Name)
+				}
+				requestNames[convertedRequest.Name] = true
+			}
+			if convertedRequest.Response != nil {
+				for _, output := range convertedRequest.Response.Outputs {
+					if outputNames[output.Name] {
+						return fmt.Errorf(webhookConfInvalid+"duplicate output name '%s'", output.Name)
+					}
+					outputNames[output.Name] = true
+				}
+			}
 			webhooks[i].Requests[j] = convertedRequest
 		}
 	}
 	return nil
 }
 
+func (w Webhook) hasEnv(name string) bool {
+	for _, envFrom := range w.EnvFrom {
+		if envFrom.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func verifyBeta1Request(request Request) error {
 	if request.URL == "" {
 		return fmt.Errorf(webhookConfInvalid + "webhook request URL empty")
@@ -117,6 +259,9 @@ func verifyBeta1Request(request Request) error {
 	if request.Method == "" {
 		return fmt.Errorf(webhookConfInvalid + "webhook request method empty")
 	}
+	if request.Name != "" && !requestNameRegex.MatchString(request.Name) {
+		return fmt.Errorf(webhookConfInvalid+"invalid webhook request name '%s'", request.Name)
+	}
 	if !isMethodSupported(request.Method) {
 		return fmt.Errorf(webhookConfInvalid + "unsupported webhook request method")
 	}
@@ -127,9 +272,118 @@ func verifyBeta1Request(request Request) error {
 			}
 		}
 	}
+	if _, err := request.GetTimeout(DefaultRequestTimeout); err != nil {
+		return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+	}
+	if request.Response != nil {
+		if request.IsCurlRequest() && len(request.Response.StatusCodes) > 0 {
+			return fmt.Errorf(webhookConfInvalid + "expected status codes are not supported for requests with curl options")
+		}
+		if err := request.Response.validate(); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+	}
+	if request.Retry != nil {
+		if request.IsCurlRequest() {
+			return fmt.Errorf(webhookConfInvalid + "retries are not supported for requests with curl options")
+		}
+		if err := request.Retry.validate(); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+	}
+	if request.Signature != nil {
+		if request.IsCurlRequest() {
+			return fmt.Errorf(webhookConfInvalid + "signing is not supported for requests with curl options")
+		}
+		if request.Signature.SecretEnv == "" {
+			return fmt.Errorf(webhookConfInvalid + "signature must contain 'secretEnv'")
+		}
+	}
+	if request.TLS != nil && request.TLS.ClientCert != nil {
+		if request.IsCurlRequest() {
+			return fmt.Errorf(webhookConfInvalid + "client certificates are not supported for requests with curl options")
+		}
+		if request.TLS.ClientCert.SecretName == "" {
+			return fmt.Errorf(webhookConfInvalid + "client certificate must contain 'secretName'")
+		}
+	}
+	if request.Proxy != "" && !strings.Contains(request.Proxy, "{{") {
+		if _, err := parseProxyURL(request.Proxy); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+	}
 	return nil
 }
 
+// GetTimeout returns the timeout of the request, or the given default if no timeout is set
+func (r Request) GetTimeout(defaultTimeout time.Duration) (time.Duration, error) {
+	return parsePositiveDuration(r.Timeout, defaultTimeout, "webhook request timeout")
+}
+
+// WithProject returns a copy of the request that is validated against the egress rules of the given project
+func (r Request) WithProject(project string) Request {
+	r.project = project
+	return r
+}
+
+// IsCurlRequest returns true if the request contains curl options, which can only be executed by the ICurlExecutor
+func (r Request) IsCurlRequest() bool {
+	return r.Options != ""
+}
+
+func verifyAsyncSpec(async AsyncSpec) error {
+	if (async.Poll == nil) == (async.Callback == nil) {
+		return errors.New(webhookConfInvalid + "asynchronous webhook must contain either 'poll' or 'callback'")
+	}
+	if _, err := async.GetTimeout(); err != nil {
+		return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+	}
+	if async.Poll != nil {
+		if _, err := async.Poll.GetInterval(); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+		if async.Poll.Request.IsCurlRequest() {
+			return errors.New(webhookConfInvalid + "poll requests must not contain curl options")
+		}
+		if err := verifyBeta1Request(async.Poll.Request); err != nil {
+			return err
+		}
+		if len(async.Poll.Until) == 0 {
+			return errors.New(webhookConfInvalid + "poll must contain at least one 'until' assertion")
+		}
+		if err := (&ResponseSpec{Assertions: async.Poll.Until}).validate(); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+	}
+	if async.Callback != nil && async.Callback.Response != nil {
+		if err := async.Callback.Response.validate(); err != nil {
+			return fmt.Errorf(webhookConfInvalid+"%s", err.Error())
+		}
+	}
+	return nil
+}
+
+// GetTimeout returns the maximum duration of the asynchronous job
+func (a AsyncSpec) GetTimeout() (time.Duration, error) {
+	return parsePositiveDuration(a.Timeout, DefaultAsyncTimeout, "asynchronous webhook timeout")
+}
+
+// GetInterval returns the duration between two poll requests
+func (p PollSpec) GetInterval() (time.Duration, error) {
+	return parsePositiveDuration(p.Interval, DefaultPollInterval, "poll interval")
+}
+
+func parsePositiveDuration(value string, defaultValue time.Duration, name string) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s '%s'", name, value)
+	}
+	return duration, nil
+}
+
 func isMethodSupported(method string) bool {
 	for _, m := range supportedCurlMethods {
 		if m == method {
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -309,6 +310,486 @@ spec:
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "valid Beta1 version input - response assertions and outputs",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: GET
+          response:
+            statusCodes: [200, 404]
+            assertions:
+              - jsonPath: $.status
+                equals: healthy
+            outputs:
+              - name: score
+                jsonPath: $.score`),
+			},
+			want: &WebHookConfig{
+				ApiVersion: "webhookconfig.keptn.sh/v1beta1",
+				Kind:       "WebhookConfig",
+				Metadata: Metadata{
+					Name: "webhook-configuration",
+				},
+				Spec: WebHookConfigSpec{
+					Webhooks: []Webhook{
+						{
+							Type:           "sh.keptn.event.webhook.triggered",
+							SubscriptionID: "my-subscription-id",
+							Requests: []interface{}{
+								Request{
+									Method: "GET",
+									URL:    "http://localhost:8080",
+									Response: &ResponseSpec{
+										StatusCodes: []int{200, 404},
+										Assertions:  []Assertion{{JSONPath: "$.status", Equals: "healthy"}},
+										Outputs:     []Output{{Name: "score", JSONPath: "$.score"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Beta1 version input - reserved output name",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: GET
+          response:
+            outputs:
+              - name: responses
+                jsonPath: $.score`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - invalid assertion regex",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: GET
+          response:
+            assertions:
+              - regex: "(healthy"`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - status codes with curl options",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: GET
+          options: --insecure
+          response:
+            statusCodes: [200]`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - duplicate request name",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - name: createTicket
+          url: http://localhost:8080
+          method: POST
+        - name: createTicket
+          url: http://localhost:8080
+          method: POST`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - duplicate output name in different requests",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          response:
+            outputs:
+              - name: ticketId
+                jsonPath: "$.id"
+        - url: http://localhost:8080
+          method: POST
+          response:
+            outputs:
+              - name: ticketId
+                jsonPath: "$.key"`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - invalid request name",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - name: create-ticket
+          url: http://localhost:8080
+          method: POST`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - async webhook with poll and callback",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://localhost:8080
+          method: POST
+      async:
+        poll:
+          request:
+            url: http://localhost:8080/status
+            method: GET
+          until:
+            - jsonPath: $.state
+              equals: done
+        callback: {}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - async webhook with invalid timeout",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://localhost:8080
+          method: POST
+      async:
+        timeout: forever
+        callback: {}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - async poll without until assertions",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://localhost:8080
+          method: POST
+      async:
+        poll:
+          interval: 10s
+          request:
+            url: http://localhost:8080/status
+            method: GET`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - async poll request with curl options",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - url: http://localhost:8080
+          method: POST
+      async:
+        poll:
+          request:
+            url: http://localhost:8080/status
+            method: GET
+            options: --insecure
+          until:
+            - jsonPath: $.state
+              equals: done`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - async webhook without sendFinished",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: false
+      requests:
+        - url: http://localhost:8080
+          method: POST
+      async:
+        callback: {}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - too many retry attempts",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          retry:
+            attempts: 100`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - invalid retry backoff",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          retry:
+            attempts: 3
+            initialBackoff: soon`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - unknown retryable network error",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          retry:
+            attempts: 3
+            networkErrors: [tls]`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - retry with curl options",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          options: --insecure
+          retry:
+            attempts: 3`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - reserved output name attempts",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          response:
+            outputs:
+              - name: attempts
+                jsonPath: $.count`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - signature secret not in envFrom",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          signature:
+            secretEnv: signingKey`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - signature with curl options",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      envFrom:
+        - name: "signingKey"
+          secretRef:
+            name: "my-signing-secret"
+            key: "key"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          options: --insecure
+          signature:
+            secretEnv: signingKey`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Beta1 version input - client certificate without secret name",
+			args: args{
+				webhookConfigYaml: []byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: https://localhost:8080
+          method: POST
+          tls:
+            clientCert:
+              certKey: cert.pem`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "invalid input",
 			args: args{
@@ -488,3 +969,118 @@ func TestWebhook_ShouldSendFinishedEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeWebHookConfigYAML_Async(t *testing.T) {
+	config, err := DecodeWebHookConfigYAML([]byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      sendFinished: true
+      requests:
+        - name: startJob
+          url: http://localhost:8080/jobs
+          method: POST
+      async:
+        timeout: 2h
+        poll:
+          interval: 1m
+          request:
+            url: http://localhost:8080/jobs/{{.responses.startJob.id}}
+            method: GET
+            response:
+              assertions:
+                - jsonPath: $.result
+                  equals: success
+          until:
+            - jsonPath: $.state
+              regex: "^(done|failed)$"`))
+	require.Nil(t, err)
+
+	async := config.Spec.Webhooks[0].Async
+	require.NotNil(t, async)
+	require.NotNil(t, async.Poll)
+	require.Nil(t, async.Callback)
+	require.Equal(t, "http://localhost:8080/jobs/{{.responses.startJob.id}}", async.Poll.Request.URL)
+	require.Equal(t, []Assertion{{JSONPath: "$.state", Regex: "^(done|failed)$"}}, async.Poll.Until)
+	require.Equal(t, []Assertion{{JSONPath: "$.result", Equals: "success"}}, async.Poll.Request.Response.Assertions)
+
+	timeout, err := async.GetTimeout()
+	require.Nil(t, err)
+	require.Equal(t, 2*time.Hour, timeout)
+	interval, err := async.Poll.GetInterval()
+	require.Nil(t, err)
+	require.Equal(t, time.Minute, interval)
+
+	timeout, err = AsyncSpec{}.GetTimeout()
+	require.Nil(t, err)
+	require.Equal(t, DefaultAsyncTimeout, timeout)
+}
+
+func TestDecodeWebHookConfigYAML_Retry(t *testing.T) {
+	config, err := DecodeWebHookConfigYAML([]byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      requests:
+        - url: http://localhost:8080
+          method: POST
+          retry:
+            attempts: 3
+            initialBackoff: 2s
+            maxBackoff: 10s
+            statusCodes: [503]
+            networkErrors: [timeout, connectionReset]
+            idempotencyKeyHeader: Idempotency-Key`))
+	require.Nil(t, err)
+
+	request := ConvertToRequest(config.Spec.Webhooks[0].Requests[0])
+	require.Equal(t, &RetryPolicy{
+		Attempts:             3,
+		InitialBackoff:       "2s",
+		MaxBackoff:           "10s",
+		StatusCodes:          []int{503},
+		NetworkErrors:        []string{NetworkErrorTimeout, NetworkErrorConnectionReset},
+		IdempotencyKeyHeader: "Idempotency-Key",
+	}, request.Retry)
+}
+
+func TestDecodeWebHookConfigYAML_Signature(t *testing.T) {
+	config, err := DecodeWebHookConfigYAML([]byte(`apiVersion: webhookconfig.keptn.sh/v1beta1
+kind: WebhookConfig
+metadata:
+  name: webhook-configuration
+spec:
+  webhooks:
+    - type: "sh.keptn.event.webhook.triggered"
+      subscriptionID: "my-subscription-id"
+      envFrom:
+        - name: "signingKey"
+          secretRef:
+            name: "my-signing-secret"
+            key: "key"
+      requests:
+        - url: https://localhost:8080
+          method: POST
+          signature:
+            secretEnv: signingKey
+            header: X-Hub-Signature-256
+          tls:
+            clientCert:
+              secretName: my-client-cert`))
+	require.Nil(t, err)
+	request := config.Spec.Webhooks[0].Requests[0].(Request)
+	require.Equal(t, &SignatureSpec{SecretEnv: "signingKey", Header: "X-Hub-Signature-256"}, request.Signature)
+	require.Equal(t, "X-Hub-Signature-256", request.Signature.GetHeader())
+	require.Equal(t, DefaultSignatureTimestampHeader, request.Signature.GetTimestampHeader())
+	require.Equal(t, &ClientCertRef{SecretName: "my-client-cert"}, request.TLS.ClientCert)
+	require.Equal(t, "tls.crt", request.TLS.ClientCert.GetCertKey())
+	require.Equal(t, "tls.key", request.TLS.ClientCert.GetKeyKey())
+}
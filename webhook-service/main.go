@@ -1,8 +1,11 @@
 package main
 
 import (
+	"net/http"
 	"os"
+	"strconv"
 
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/go-sdk/pkg/sdk"
 	"github.com/keptn/keptn/webhook-service/handler"
 	"github.com/keptn/keptn/webhook-service/lib"
@@ -14,6 +17,19 @@ import (
 const eventTypeWildcard = "*"
 const serviceName = "webhook-service"
 const envVarLogLevel = "LOG_LEVEL"
+const envVarCallbackBaseURL = "CALLBACK_BASE_URL"
+const envVarCallbackPort = "CALLBACK_PORT"
+const defaultCallbackBaseURL = "http://webhook-service:8082"
+const defaultCallbackPort = "8082"
+const envVarSecretBackend = "SECRET_BACKEND"
+const envVarSecretRequireManagedBy = "SECRET_REQUIRE_MANAGED_BY"
+const envVarSecretFilePath = "SECRET_FILE_PATH"
+const envVarSecretEnvPrefix = "SECRET_ENV_PREFIX"
+const envVarVaultAddress = "VAULT_ADDR"
+const envVarVaultToken = "VAULT_TOKEN"
+const envVarVaultMountPath = "VAULT_MOUNT_PATH"
+const envVarVaultNamespace = "VAULT_NAMESPACE"
+const envVarUseEnvironmentProxy = "USE_ENVIRONMENT_PROXY"
 
 func main() {
 	if os.Getenv(envVarLogLevel) != "" {
@@ -24,11 +40,20 @@ func main() {
 			log.SetLevel(logLevel)
 		}
 	}
+	secretReaderConfig := getSecretReaderConfig()
+	// the kubernetes client is only required by the kubernetes secret backend. Without it, the keptn-webhook-config ConfigMap
+	// and asynchronous webhooks are not available, which allows running the webhook-service outside of Kubernetes
 	kubeAPI, err := createKubeAPI()
 	if err != nil {
-		log.Fatalf("could not create kubernetes client: %s", err.Error())
+		if usesKubernetesSecretBackend(secretReaderConfig) {
+			log.Fatalf("could not create kubernetes client: %s", err.Error())
+		}
+		log.WithError(err).Warn("running without kubernetes: the keptn-webhook-config ConfigMap and asynchronous webhooks are not available")
+	}
+	secretReader, err := lib.NewSecretReader(secretReaderConfig, kubeAPI)
+	if err != nil {
+		log.Fatalf("could not create secret reader: %s", err.Error())
 	}
-	secretReader := lib.NewK8sSecretReader(kubeAPI)
 
 	curlExecutor := lib.NewCmdCurlExecutor(
 		&lib.OSCmdExecutor{},
@@ -36,10 +61,36 @@ func main() {
 
 	ipResolver := lib.NewIPResolver()
 	denyListProvider := lib.NewDenyListProvider(kubeAPI)
-	requestValidator := lib.NewRequestValidator(denyListProvider, ipResolver)
-	taskHandler := handler.NewTaskHandler(&lib.TemplateEngine{}, curlExecutor, requestValidator, secretReader)
+	egressRuleProvider := lib.NewEgressRuleProvider(kubeAPI)
+	requestValidator := lib.NewRequestValidator(denyListProvider, ipResolver, lib.WithEgressRules(egressRuleProvider))
+	requestExecutorOptions := []lib.HTTPRequestExecutorOption{
+		lib.WithDialDenyList(denyListProvider),
+		lib.WithDialEgressRules(egressRuleProvider),
+	}
+	if useEnvironmentProxy, _ := strconv.ParseBool(os.Getenv(envVarUseEnvironmentProxy)); useEnvironmentProxy {
+		requestExecutorOptions = append(requestExecutorOptions, lib.WithEnvironmentProxy())
+	}
+	requestExecutor := lib.NewHTTPRequestExecutor(requestExecutorOptions...)
+	callbackBaseURL := os.Getenv(envVarCallbackBaseURL)
+	if callbackBaseURL == "" {
+		callbackBaseURL = defaultCallbackBaseURL
+	}
+	taskHandlerOptions := []handler.TaskHandlerOption{
+		handler.WithRequestExecutor(requestExecutor),
+		handler.WithEgressRules(egressRuleProvider),
+	}
+	if kubeAPI != nil {
+		taskHandlerOptions = append(taskHandlerOptions, handler.WithAsyncJobs(lib.NewK8sJobStore(kubeAPI), callbackBaseURL))
+	}
+	taskHandler := handler.NewTaskHandler(
+		&lib.TemplateEngine{},
+		curlExecutor,
+		requestValidator,
+		secretReader,
+		taskHandlerOptions...,
+	)
 
-	log.Fatal(sdk.NewKeptn(
+	keptn := sdk.NewKeptn(
 		serviceName,
 		sdk.WithTaskHandler(
 			eventTypeWildcard,
@@ -47,10 +98,58 @@ func main() {
 		),
 		sdk.WithAutomaticResponse(false),
 		sdk.WithLogger(log.New()),
-	).Start())
+	)
+
+	if err := taskHandler.ResumeAsyncJobs(keptn); err != nil {
+		log.WithError(err).Error("could not resume asynchronous webhook jobs")
+	}
+	eventSender, err := keptnv2.NewHTTPEventSender(sdk.DefaultHTTPEventEndpoint)
+	if err != nil {
+		log.Fatalf("could not create event sender: %s", err.Error())
+	}
+	inboundReceiver := handler.NewInboundReceiver(lib.NewInboundWebhookProvider(kubeAPI), secretReader, &lib.TemplateEngine{}, eventSender, serviceName)
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/callback/", taskHandler.CallbackHandler())
+	mux.Handle("/v1/inbound/", inboundReceiver)
+	go func() {
+		callbackPort := os.Getenv(envVarCallbackPort)
+		if callbackPort == "" {
+			callbackPort = defaultCallbackPort
+		}
+		log.Fatal(http.ListenAndServe(":"+callbackPort, mux))
+	}()
+
+	log.Fatal(keptn.Start())
+}
+
+func getSecretReaderConfig() lib.SecretReaderConfig {
+	requireManagedBy := true
+	if value := os.Getenv(envVarSecretRequireManagedBy); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.WithError(err).Errorf("could not parse '%s' env var", envVarSecretRequireManagedBy)
+		} else {
+			requireManagedBy = parsed
+		}
+	}
+	return lib.SecretReaderConfig{
+		Backend:          os.Getenv(envVarSecretBackend),
+		RequireManagedBy: requireManagedBy,
+		FilePath:         os.Getenv(envVarSecretFilePath),
+		EnvPrefix:        os.Getenv(envVarSecretEnvPrefix),
+		VaultAddress:     os.Getenv(envVarVaultAddress),
+		VaultToken:       os.Getenv(envVarVaultToken),
+		VaultMountPath:   os.Getenv(envVarVaultMountPath),
+		VaultNamespace:   os.Getenv(envVarVaultNamespace),
+	}
+}
+
+func usesKubernetesSecretBackend(config lib.SecretReaderConfig) bool {
+	return config.Backend == "" || config.Backend == lib.SecretBackendKubernetes
 }
 
-func createKubeAPI() (*kubernetes.Clientset, error) {
+func createKubeAPI() (kubernetes.Interface, error) {
 	var config *rest.Config
 	config, err := rest.InClusterConfig()
 